@@ -1,10 +1,40 @@
 package app
 
 import (
-	"gorm.io/gorm"
+	"main.go/config"
+	"main.go/cookbook"
+	"main.go/health"
+	"main.go/render"
+	"main.go/repository"
+	"main.go/storage"
+	"main.go/trash"
 )
 
 // Objeto de acesso aos dados (DAO), que intermedia a interação com o banco
 type App struct {
-	DB *gorm.DB
+	// Config é a configuração validada na inicialização
+	Config *config.Config
+	// Users, Recipes e Ingredients acessam os dados dos usuários, das receitas e dos ingredientes
+	Users       repository.UserRepository
+	Recipes     repository.RecipeRepository
+	Ingredients repository.IngredientRepository
+	// Substitutions, Images e Exports acessam as substituições de ingredientes, os registros das
+	// imagens das receitas e os pedidos de exportação de livros de receitas
+	Substitutions repository.SubstitutionRepository
+	Images        repository.ImageRepository
+	Exports       repository.ExportRepository
+	// Catalog importa e exporta o catálogo inteiro de ingredientes ou receitas
+	Catalog repository.CatalogRepository
+	// Storage guarda os arquivos enviados (imagens das receitas)
+	Storage storage.BlobStore
+	// Signer gera as URLs assinadas para acessar os arquivos guardados
+	Signer *storage.URLSigner
+	// Renderer gera as receitas em Markdown e HTML a partir dos templates
+	Renderer *render.Renderer
+	// Cookbooks processa em segundo plano as exportações de livros de receitas
+	Cookbooks *cookbook.Exporter
+	// Health verifica as dependências da API para o /readyz
+	Health *health.Registry
+	// Trash remove definitivamente os itens da lixeira após o tempo de retenção
+	Trash *trash.Purger
 }
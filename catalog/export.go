@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// Export escreve todos os registros do tipo kind no formato informado, em ordem de nome. O arquivo
+// gerado pode ser importado novamente com Import.
+func Export(db *gorm.DB, kind string, w io.Writer, format string) error {
+	if format != FormatCSV && format != FormatNDJSON {
+		return ErrInvalidFormat
+	}
+
+	switch kind {
+	case KindIngredients:
+		records, err := ingredientRecords(db)
+		if err != nil {
+			return err
+		}
+		if format == FormatNDJSON {
+			return writeNDJSON(w, records)
+		}
+		return writeIngredientsCSV(w, records)
+	case KindRecipes:
+		records, err := recipeRecords(db)
+		if err != nil {
+			return err
+		}
+		if format == FormatNDJSON {
+			return writeNDJSON(w, records)
+		}
+		return writeRecipesCSV(w, records)
+	default:
+		return ErrInvalidKind
+	}
+}
+
+// Funções privadas
+
+func ingredientRecords(db *gorm.DB) ([]IngredientRecord, error) {
+	var ingredients []models.Ingredient
+	if err := db.Order("name").Find(&ingredients).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]IngredientRecord, len(ingredients))
+	for i, ingredient := range ingredients {
+		records[i] = IngredientRecord{Name: ingredient.Name}
+	}
+	return records, nil
+}
+
+func recipeRecords(db *gorm.DB) ([]RecipeRecord, error) {
+	var recipes []models.Recipe
+	if err := db.Scopes(PreloadIngredients).Order("name, user_id").Find(&recipes).Error; err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	if err := db.Select("id", "username").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	usernames := map[uint]string{}
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+
+	records := make([]RecipeRecord, len(recipes))
+	for i, recipe := range recipes {
+		record := RecipeRecord{
+			Name:         recipe.Name,
+			User:         usernames[recipe.UserID],
+			Instructions: recipe.Instructions,
+			Servings:     recipe.Servings,
+			PrepTime:     recipe.PrepTime,
+			CookTime:     recipe.CookTime,
+			TotalTime:    recipe.TotalTime,
+			Visibility:   recipe.Visibility,
+			Ingredients:  []RecipeIngredientRecord{},
+		}
+		for _, item := range recipe.IngredientsRecipes {
+			record.Ingredients = append(record.Ingredients, RecipeIngredientRecord{
+				Name:     item.Ingredient.Name,
+				Quantity: item.Quantity,
+				Group:    item.Group,
+				Note:     item.Note,
+				Optional: item.Optional,
+				ToTaste:  item.ToTaste,
+			})
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+func writeNDJSON[T any](w io.Writer, records []T) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeIngredientsCSV(w io.Writer, records []IngredientRecord) error {
+	writer := csv.NewWriter(w)
+	writer.Write(ingredientColumns)
+	for _, record := range records {
+		writer.Write([]string{record.Name})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeRecipesCSV escreve uma linha por ingrediente de cada receita, repetindo as colunas da
+// receita; receitas sem ingredientes ocupam uma linha com as colunas do ingrediente vazias
+func writeRecipesCSV(w io.Writer, records []RecipeRecord) error {
+	writer := csv.NewWriter(w)
+	writer.Write(recipeColumns)
+	for _, record := range records {
+		columns := []string{
+			record.Name,
+			record.User,
+			record.Instructions,
+			optionalInt(record.Servings),
+			optionalInt(record.PrepTime),
+			optionalInt(record.CookTime),
+			optionalInt(record.TotalTime),
+			record.Visibility,
+		}
+
+		ingredients := record.Ingredients
+		if len(ingredients) == 0 {
+			ingredients = []RecipeIngredientRecord{{}}
+		}
+		for _, ingredient := range ingredients {
+			writer.Write(append(columns[:len(columns):len(columns)],
+				ingredient.Name,
+				ingredient.Quantity,
+				ingredient.Group,
+				ingredient.Note,
+				optionalBool(ingredient.Optional),
+				optionalBool(ingredient.ToTaste),
+			))
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func optionalBool(b bool) string {
+	if !b {
+		return ""
+	}
+	return "true"
+}
+
+func optionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,372 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"main.go/history"
+	"main.go/logging"
+	"main.go/models"
+)
+
+// ErrInvalidKind é retornado quando o tipo de registro não é ingredients nem recipes.
+var ErrInvalidKind = errors.New("catalog: invalid kind")
+
+// ErrInvalidFormat é retornado quando o formato do arquivo não é csv nem ndjson.
+var ErrInvalidFormat = errors.New("catalog: invalid format")
+
+// ParseError é retornado quando o arquivo não pode ser lido no formato informado, como um cabeçalho
+// CSV incompleto. Os problemas de cada registro não interrompem a leitura e vão para o relatório.
+type ParseError struct {
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return "catalog: invalid file: " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// errRollback desfaz a transação do lote sem ser tratado como falha da importação
+var errRollback = errors.New("catalog: rollback")
+
+// Options configura uma importação.
+type Options struct {
+	// Format é o formato do arquivo (csv ou ndjson).
+	Format string
+	// DryRun valida e aplica o lote dentro da transação, mas sempre a desfaz no final.
+	DryRun bool
+	// DefaultUserID é o dono das receitas novas que não informam a coluna user e o autor
+	// registrado nas revisões das receitas importadas.
+	DefaultUserID uint
+}
+
+// Report representa o resultado de uma importação.
+// @Description Relatório da importação em lote, com os erros encontrados por linha.
+type Report struct {
+	// DryRun indica se a importação foi apenas simulada.
+	DryRun bool `json:"dry_run"`
+	// Committed indica se as alterações foram gravadas no banco.
+	Committed bool `json:"committed"`
+	// Total é o número de registros lidos do arquivo.
+	Total int `json:"total"`
+	// Created é o número de registros novos.
+	Created int `json:"created"`
+	// Updated é o número de registros existentes que foram alterados.
+	Updated int `json:"updated"`
+	// Unchanged é o número de registros existentes que já estavam iguais ao arquivo.
+	Unchanged int `json:"unchanged"`
+	// IngredientsCreated é o número de ingredientes criados pelas receitas importadas.
+	IngredientsCreated int `json:"ingredients_created,omitempty"`
+	// Errors lista os registros rejeitados; qualquer erro impede a gravação do lote.
+	Errors []RowError `json:"errors"`
+}
+
+// RowError representa um registro rejeitado na importação.
+// @Description Erro de um registro do arquivo importado.
+type RowError struct {
+	// Line é a linha do arquivo onde o registro começa.
+	Line int `json:"line" example:"3"`
+	// Name é o nome do registro, quando informado.
+	Name string `json:"name,omitempty" example:"bolo de chocolate"`
+	// Error descreve o problema encontrado.
+	Error string `json:"error" example:"ingredient 2: quantity is required"`
+}
+
+// Import lê os registros do tipo kind e grava-os no banco, atualizando os já existentes com o
+// mesmo nome (sem diferenciar maiúsculas e minúsculas; receitas, entre as do mesmo autor). Todo o lote é gravado em uma única
+// transação: se algum registro for rejeitado, ou em modo dry-run, nada é gravado. Um arquivo que não
+// pode ser lido no formato resulta em *ParseError.
+func Import(db *gorm.DB, kind string, r io.Reader, opts Options) (*Report, error) {
+	if opts.Format != FormatCSV && opts.Format != FormatNDJSON {
+		return nil, ErrInvalidFormat
+	}
+
+	report := &Report{DryRun: opts.DryRun, Errors: []RowError{}}
+
+	var apply func(tx *gorm.DB) error
+	switch kind {
+	case KindIngredients:
+		rows, err := readIngredients(r, opts.Format)
+		if err != nil {
+			return nil, &ParseError{Err: err}
+		}
+		report.Total = len(rows)
+		apply = func(tx *gorm.DB) error {
+			for _, row := range rows {
+				if err := importRow(tx, report, row.Line, row.Record.Name, row.Err, func() (string, error) {
+					return importIngredient(tx, row.Record)
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case KindRecipes:
+		rows, err := readRecipes(r, opts.Format)
+		if err != nil {
+			return nil, &ParseError{Err: err}
+		}
+		report.Total = len(rows)
+		apply = func(tx *gorm.DB) error {
+			for _, row := range rows {
+				if err := importRow(tx, report, row.Line, row.Record.Name, row.Err, func() (string, error) {
+					return importRecipe(tx, report, row.Record, opts.DefaultUserID)
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	default:
+		return nil, ErrInvalidKind
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := apply(tx); err != nil {
+			return err
+		}
+		if opts.DryRun || len(report.Errors) > 0 {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && err != errRollback {
+		return nil, err
+	}
+
+	report.Committed = err == nil
+	return report, nil
+}
+
+// Funções privadas
+
+// Resultados da gravação de um registro
+const (
+	rowCreated   = "created"
+	rowUpdated   = "updated"
+	rowUnchanged = "unchanged"
+)
+
+// rowError é um erro de validação de um registro, que é reportado sem interromper o lote
+type rowError struct {
+	message string
+}
+
+func (e *rowError) Error() string {
+	return e.message
+}
+
+func invalid(format string, args ...any) error {
+	return &rowError{message: fmt.Sprintf(format, args...)}
+}
+
+// importRow grava um registro dentro de um savepoint, para que um registro rejeitado não invalide
+// a transação e os registros seguintes continuem sendo validados
+func importRow(tx *gorm.DB, report *Report, line int, name string, readErr error, save func() (string, error)) error {
+	if readErr != nil {
+		report.Errors = append(report.Errors, RowError{Line: line, Name: name, Error: readErr.Error()})
+		return nil
+	}
+
+	if err := tx.SavePoint("catalog_row").Error; err != nil {
+		return err
+	}
+
+	outcome, err := save()
+	if err != nil {
+		if rollbackErr := tx.RollbackTo("catalog_row").Error; rollbackErr != nil {
+			return rollbackErr
+		}
+
+		var invalidErr *rowError
+		if !errors.As(err, &invalidErr) {
+			// Erros do banco (ex.: violação de restrição) também são atribuídos ao registro
+			logging.FromContext(tx.Statement.Context).Error("Error importing catalog row", "line", line, "error", err)
+		}
+		report.Errors = append(report.Errors, RowError{Line: line, Name: name, Error: err.Error()})
+		return nil
+	}
+
+	switch outcome {
+	case rowCreated:
+		report.Created++
+	case rowUpdated:
+		report.Updated++
+	default:
+		report.Unchanged++
+	}
+	return nil
+}
+
+func importIngredient(tx *gorm.DB, record IngredientRecord) (string, error) {
+	name := strings.TrimSpace(record.Name)
+	if name == "" {
+		return "", invalid("name is required")
+	}
+
+	_, created, err := FindOrCreateIngredient(tx, name)
+	if err != nil {
+		return "", err
+	}
+	if created {
+		return rowCreated, nil
+	}
+	return rowUnchanged, nil
+}
+
+func importRecipe(tx *gorm.DB, report *Report, record RecipeRecord, defaultUserID uint) (string, error) {
+	name := strings.TrimSpace(record.Name)
+	if name == "" {
+		return "", invalid("name is required")
+	}
+	if strings.TrimSpace(record.Instructions) == "" {
+		return "", invalid("instructions are required")
+	}
+	if record.Servings < 0 || record.PrepTime < 0 || record.CookTime < 0 || record.TotalTime < 0 {
+		return "", invalid("servings and times must not be negative")
+	}
+	if record.Visibility != "" && !slices.Contains(models.Visibilities, record.Visibility) {
+		return "", invalid("visibility must be one of %s", strings.Join(models.Visibilities, ", "))
+	}
+
+	// Dono da receita: o usuário da coluna user ou o usuário padrão da importação
+	userID := defaultUserID
+	if record.User != "" {
+		var user models.User
+		err := tx.Select("id").Where("username = ?", record.User).First(&user).Error
+		if err == gorm.ErrRecordNotFound {
+			return "", invalid("user %q not found", record.User)
+		}
+		if err != nil {
+			return "", err
+		}
+		userID = user.ID
+	}
+	if userID == 0 {
+		return "", invalid("user is required")
+	}
+
+	// Os nomes são únicos por autor: a receita é atualizada quando o dono já tem uma com o mesmo nome
+	var recipe models.Recipe
+	result := tx.Preload("IngredientsRecipes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, ingredient_id")
+	}).Where("user_id = ? AND LOWER(name) = ?", userID, strings.ToLower(name)).First(&recipe)
+	exists := result.Error == nil
+	if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
+		return "", result.Error
+	}
+
+	// Ingredientes do arquivo, com as quantidades de linhas repetidas do mesmo ingrediente somadas
+	var ingredients []models.IngredientsRecipes
+	positions := map[uint]int{}
+	for i, item := range record.Ingredients {
+		itemName := strings.TrimSpace(item.Name)
+		quantity := strings.TrimSpace(item.Quantity)
+		if itemName == "" {
+			return "", invalid("ingredient %d: name is required", i+1)
+		}
+		if quantity == "" && !item.ToTaste {
+			return "", invalid("ingredient %d: quantity is required", i+1)
+		}
+
+		ingredient, created, err := FindOrCreateIngredient(tx, itemName)
+		if err != nil {
+			return "", err
+		}
+		if created {
+			report.IngredientsCreated++
+		}
+
+		if j, ok := positions[ingredient.ID]; ok {
+			ingredients[j].Quantity = strings.TrimPrefix(ingredients[j].Quantity+" + "+quantity, " + ")
+			continue
+		}
+		positions[ingredient.ID] = len(ingredients)
+		ingredients = append(ingredients, models.IngredientsRecipes{
+			IngredientID: ingredient.ID,
+			Quantity:     quantity,
+			Position:     len(ingredients),
+			Group:        strings.TrimSpace(item.Group),
+			Note:         strings.TrimSpace(item.Note),
+			Optional:     item.Optional,
+			ToTaste:      item.ToTaste,
+		})
+	}
+
+	updated := models.Recipe{
+		ID:           recipe.ID,
+		UserID:       userID,
+		Name:         name,
+		Instructions: record.Instructions,
+		Servings:     record.Servings,
+		PrepTime:     record.PrepTime,
+		CookTime:     record.CookTime,
+		TotalTime:    record.TotalTime,
+		Visibility:   record.Visibility,
+		PublishAt:    recipe.PublishAt,
+		ParentID:     recipe.ParentID,
+	}
+
+	// Sem a coluna visibility, receitas existentes mantêm o estado e as novas do catálogo são publicadas
+	if updated.Visibility == "" {
+		updated.Visibility = recipe.Visibility
+	}
+	if updated.Visibility == "" {
+		updated.Visibility = models.VisibilityPublished
+	}
+
+	if exists && sameRecipe(recipe, updated, ingredients) {
+		return rowUnchanged, nil
+	}
+
+	if err := AssignSlug(tx, &updated); err != nil {
+		return "", err
+	}
+	if err := tx.Omit(clause.Associations).Save(&updated).Error; err != nil {
+		return "", err
+	}
+
+	// Substitui os ingredientes da receita pelos do arquivo
+	if err := ReplaceIngredients(tx, updated.ID, ingredients); err != nil {
+		return "", err
+	}
+
+	if _, err := history.Record(tx, updated.ID, defaultUserID, "imported from catalog file"); err != nil {
+		return "", err
+	}
+
+	if exists {
+		return rowUpdated, nil
+	}
+	return rowCreated, nil
+}
+
+// sameRecipe compara a receita gravada com a do arquivo, incluindo a ordem e os dados dos ingredientes
+func sameRecipe(current models.Recipe, updated models.Recipe, ingredients []models.IngredientsRecipes) bool {
+	if current.UserID != updated.UserID || current.Name != updated.Name || current.Instructions != updated.Instructions ||
+		current.Servings != updated.Servings || current.PrepTime != updated.PrepTime ||
+		current.CookTime != updated.CookTime || current.TotalTime != updated.TotalTime ||
+		current.Visibility != updated.Visibility {
+		return false
+	}
+
+	if len(current.IngredientsRecipes) != len(ingredients) {
+		return false
+	}
+	for i, item := range ingredients {
+		stored := current.IngredientsRecipes[i]
+		if stored.IngredientID != item.IngredientID || stored.Quantity != item.Quantity || stored.Group != item.Group ||
+			stored.Note != item.Note || stored.Optional != item.Optional || stored.ToTaste != item.ToTaste {
+			return false
+		}
+	}
+	return true
+}
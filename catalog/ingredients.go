@@ -0,0 +1,226 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/history"
+	"main.go/models"
+)
+
+// ErrIngredientNotFound indica que o ingrediente canônico ou algum dos duplicados não existe.
+var ErrIngredientNotFound = errors.New("catalog: ingredient not found")
+
+// FindIngredient busca o ingrediente pelo nome, por um de seus nomes alternativos ou por uma de suas
+// traduções, sem diferenciar maiúsculas e minúsculas. Ingredientes na lixeira não são considerados.
+func FindIngredient(db *gorm.DB, name string) (*models.Ingredient, error) {
+	return findIngredient(db, name, false)
+}
+
+// FindOrCreateIngredient busca o ingrediente pelo nome, por um de seus nomes alternativos ou por uma de
+// suas traduções, sem diferenciar maiúsculas e minúsculas, cadastrando-o quando não existir. Um ingrediente na lixeira com o mesmo nome é restaurado. O
+// retorno created indica se o ingrediente foi criado.
+func FindOrCreateIngredient(db *gorm.DB, name string) (*models.Ingredient, bool, error) {
+	found, err := findIngredient(db, name, true)
+	if err == nil {
+		if found.DeletedAt.Valid {
+			err := db.Unscoped().Model(found).Updates(map[string]any{"deleted_at": nil, "deleted_by": 0}).Error
+			if err != nil {
+				return nil, false, err
+			}
+			found.DeletedAt = gorm.DeletedAt{}
+		}
+		return found, false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, false, err
+	}
+
+	ingredient := models.Ingredient{Name: name}
+	if err := db.Create(&ingredient).Error; err != nil {
+		return nil, false, err
+	}
+	return &ingredient, true, nil
+}
+
+// MergeIngredients unifica os ingredientes duplicados ao canônico: as receitas passam a usar o
+// canônico, os nomes dos duplicados viram nomes alternativos dele e os duplicados são removidos
+// definitivamente. Quando a receita já usa o canônico, a linha dele prevalece e a do duplicado é
+// descartada (ela continua no histórico). Deve ser chamado dentro de uma transação.
+func MergeIngredients(tx *gorm.DB, canonicalID uint, duplicateIDs []uint, authorID uint) (*models.IngredientMergeReport, error) {
+	var canonical models.Ingredient
+	if err := tx.Where("id = ?", canonicalID).First(&canonical).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %d", ErrIngredientNotFound, canonicalID)
+		}
+		return nil, err
+	}
+
+	// Duplicados na lixeira também podem ser unificados
+	var duplicates []models.Ingredient
+	if err := tx.Unscoped().Where("id IN ?", duplicateIDs).Order("id").Find(&duplicates).Error; err != nil {
+		return nil, err
+	}
+	if len(duplicates) != len(duplicateIDs) {
+		for _, id := range duplicateIDs {
+			if !slices.ContainsFunc(duplicates, func(ingredient models.Ingredient) bool { return ingredient.ID == id }) {
+				return nil, fmt.Errorf("%w: %d", ErrIngredientNotFound, id)
+			}
+		}
+	}
+
+	// Receitas fora da lixeira que ganham uma revisão com a troca
+	report := &models.IngredientMergeReport{RecipeIDs: []uint{}}
+	err := tx.Model(&models.Recipe{}).
+		Joins("JOIN ingredients_recipes ON ingredients_recipes.recipe_id = recipes.id").
+		Where("ingredients_recipes.ingredient_id IN ?", duplicateIDs).
+		Distinct().Order("recipes.id").Pluck("recipes.id", &report.RecipeIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var using []uint
+	if err := tx.Model(&models.IngredientsRecipes{}).Where("ingredient_id = ?", canonical.ID).Pluck("recipe_id", &using).Error; err != nil {
+		return nil, err
+	}
+	hasCanonical := make(map[uint]bool, len(using))
+	for _, recipeID := range using {
+		hasCanonical[recipeID] = true
+	}
+
+	var lines []models.IngredientsRecipes
+	if err := tx.Where("ingredient_id IN ?", duplicateIDs).Order("recipe_id, position").Find(&lines).Error; err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		query := tx.Model(&models.IngredientsRecipes{}).Where("recipe_id = ? AND ingredient_id = ?", line.RecipeID, line.IngredientID)
+		if hasCanonical[line.RecipeID] {
+			if err := query.Delete(&models.IngredientsRecipes{}).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := query.Update("ingredient_id", canonical.ID).Error; err != nil {
+			return nil, err
+		}
+		hasCanonical[line.RecipeID] = true
+	}
+
+	err = tx.Model(&models.IngredientAlias{}).Where("ingredient_id IN ?", duplicateIDs).Update("ingredient_id", canonical.ID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// As substituições dos duplicados passam para o canônico
+	err = tx.Model(&models.Substitution{}).Where("ingredient_id IN ?", duplicateIDs).Update("ingredient_id", canonical.ID).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Model(&models.SubstitutionItem{}).Where("ingredient_id IN ?", duplicateIDs).Update("ingredient_id", canonical.ID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// As traduções dos duplicados completam as do canônico; as de idiomas que ele já tem viram nomes
+	// alternativos
+	var canonicalLocales []string
+	if err := tx.Model(&models.IngredientTranslation{}).Where("ingredient_id = ?", canonical.ID).Pluck("locale", &canonicalLocales).Error; err != nil {
+		return nil, err
+	}
+	locales := make(map[string]bool, len(canonicalLocales))
+	for _, locale := range canonicalLocales {
+		locales[locale] = true
+	}
+
+	var translations []models.IngredientTranslation
+	if err := tx.Where("ingredient_id IN ?", duplicateIDs).Order("id").Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	for _, translation := range translations {
+		if !locales[translation.Locale] {
+			locales[translation.Locale] = true
+			if err := tx.Model(&translation).Update("ingredient_id", canonical.ID).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := mergeAlias(tx, canonical, translation.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		names = append(names, fmt.Sprintf("%q", duplicate.Name))
+		if err := mergeAlias(tx, canonical, duplicate.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Unscoped().Where("id IN ?", duplicateIDs).Delete(&models.Ingredient{}).Error; err != nil {
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("merged ingredient %s into %q", strings.Join(names, ", "), canonical.Name)
+	for _, recipeID := range report.RecipeIDs {
+		if _, err := history.Record(tx, recipeID, authorID, summary); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Preload("Aliases").Preload("Translations").Where("id = ?", canonical.ID).First(&report.Ingredient).Error; err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Funções privadas
+
+// mergeAlias guarda o nome como nome alternativo do canônico, a menos que seja o próprio nome dele
+func mergeAlias(tx *gorm.DB, canonical models.Ingredient, name string) error {
+	if strings.EqualFold(name, canonical.Name) {
+		return nil
+	}
+
+	var alias models.IngredientAlias
+	result := tx.Where("LOWER(name) = ?", strings.ToLower(name)).Limit(1).Find(&alias)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return tx.Model(&alias).Update("ingredient_id", canonical.ID).Error
+	}
+	return tx.Create(&models.IngredientAlias{IngredientID: canonical.ID, Name: name}).Error
+}
+
+// findIngredient busca o ingrediente pelo nome e, não encontrando, pelos nomes alternativos e pelas
+// traduções. Com trashed, ingredientes na lixeira também são considerados.
+func findIngredient(db *gorm.DB, name string, trashed bool) (*models.Ingredient, error) {
+	lower := strings.ToLower(name)
+	ingredients := func() *gorm.DB {
+		if trashed {
+			return db.Unscoped()
+		}
+		return db
+	}
+
+	var ingredient models.Ingredient
+	err := ingredients().Where("LOWER(name) = ?", lower).First(&ingredient).Error
+	if err != gorm.ErrRecordNotFound {
+		if err != nil {
+			return nil, err
+		}
+		return &ingredient, nil
+	}
+
+	aliases := db.Model(&models.IngredientAlias{}).Select("ingredient_id").Where("LOWER(name) = ?", lower)
+	translations := db.Model(&models.IngredientTranslation{}).Select("ingredient_id").Where("LOWER(name) = ?", lower)
+	err = ingredients().Where("id IN (?) OR id IN (?)", aliases, translations).Order("id").First(&ingredient).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ingredient, nil
+}
@@ -0,0 +1,64 @@
+package catalog
+
+import (
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// localeMatcher escolhe, entre os idiomas aceitos, o mais próximo dos pedidos pelo cliente
+var localeMatcher = language.NewMatcher(localeTags())
+
+// MatchLocale escolhe o idioma dos nomes dos ingredientes a partir do cabeçalho Accept-Language
+// (ex.: "pt" resulta em pt-BR e "en-US" em en). Sem cabeçalho ou sem idioma aceito, retorna
+// models.DefaultLocale.
+func MatchLocale(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return models.DefaultLocale
+	}
+
+	_, index, confidence := localeMatcher.Match(tags...)
+	if confidence == language.No {
+		return models.DefaultLocale
+	}
+	return models.Locales[index]
+}
+
+// Localize troca o nome dos ingredientes pela tradução no idioma informado, quando houver.
+func Localize(db *gorm.DB, locale string, ingredients ...*models.Ingredient) error {
+	if locale == models.DefaultLocale || len(ingredients) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		ids = append(ids, ingredient.ID)
+	}
+
+	var translations []models.IngredientTranslation
+	if err := db.Where("ingredient_id IN ? AND locale = ?", ids, locale).Find(&translations).Error; err != nil {
+		return err
+	}
+
+	names := make(map[uint]string, len(translations))
+	for _, translation := range translations {
+		names[translation.IngredientID] = translation.Name
+	}
+	for _, ingredient := range ingredients {
+		if name, ok := names[ingredient.ID]; ok {
+			ingredient.Name = name
+		}
+	}
+	return nil
+}
+
+// Funções privadas
+
+func localeTags() []language.Tag {
+	tags := make([]language.Tag, 0, len(models.Locales))
+	for _, locale := range models.Locales {
+		tags = append(tags, language.MustParse(locale))
+	}
+	return tags
+}
@@ -0,0 +1,107 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// PreloadIngredients carrega os ingredientes das receitas na ordem definida pelo autor, incluindo os
+// que estão na lixeira. Deve ser usado como escopo da consulta: db.Scopes(catalog.PreloadIngredients).
+func PreloadIngredients(db *gorm.DB) *gorm.DB {
+	return db.Preload("IngredientsRecipes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, ingredient_id")
+	}).Preload("IngredientsRecipes.Ingredient", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	})
+}
+
+// ResolveIngredients converte os ingredientes informados em uma receita nas associações a gravar,
+// buscando cada ingrediente pelo ID ou pelo nome. Com createMissing, os ingredientes informados pelo
+// nome que não existem são cadastrados. Os problemas de cada item são retornados como erros de
+// validação; o erro só é retornado em falhas do banco.
+func ResolveIngredients(tx *gorm.DB, items []models.RecipeIngredientRequest, createMissing bool) ([]models.IngredientsRecipes, []models.FieldError, error) {
+	var ingredients []models.IngredientsRecipes
+	var errs []models.FieldError
+
+	// Posição de cada ingrediente na lista, para apontar os repetidos
+	positions := map[uint]int{}
+
+	for i, item := range items {
+		field := fmt.Sprintf("ingredients[%d]", i)
+		name := strings.TrimSpace(item.Name)
+		quantity := strings.TrimSpace(item.Quantity)
+
+		if quantity == "" && !item.ToTaste {
+			errs = append(errs, models.FieldError{Field: field + ".quantity", Message: "quantity is required"})
+		}
+
+		var ingredient models.Ingredient
+		switch {
+		case item.IngredientID != 0:
+			err := tx.Where("id = ?", item.IngredientID).First(&ingredient).Error
+			if err == gorm.ErrRecordNotFound {
+				errs = append(errs, models.FieldError{Field: field + ".ingredient_id", Message: fmt.Sprintf("ingredient %d not found", item.IngredientID)})
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+		case name != "" && createMissing:
+			found, _, err := FindOrCreateIngredient(tx, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			ingredient = *found
+		case name != "":
+			found, err := FindIngredient(tx, name)
+			if err == gorm.ErrRecordNotFound {
+				errs = append(errs, models.FieldError{Field: field + ".name", Message: fmt.Sprintf("ingredient %q not found", name)})
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			ingredient = *found
+		default:
+			errs = append(errs, models.FieldError{Field: field, Message: "ingredient_id or name is required"})
+			continue
+		}
+
+		if j, ok := positions[ingredient.ID]; ok {
+			errs = append(errs, models.FieldError{Field: field, Message: fmt.Sprintf("ingredient is already listed in ingredients[%d]", j)})
+			continue
+		}
+		positions[ingredient.ID] = i
+
+		ingredients = append(ingredients, models.IngredientsRecipes{
+			IngredientID: ingredient.ID,
+			Quantity:     quantity,
+			Position:     len(ingredients),
+			Group:        strings.TrimSpace(item.Group),
+			Note:         strings.TrimSpace(item.Note),
+			Optional:     item.Optional,
+			ToTaste:      item.ToTaste,
+			Ingredient:   ingredient,
+		})
+	}
+
+	return ingredients, errs, nil
+}
+
+// ReplaceIngredients troca todos os ingredientes da receita pelos informados.
+func ReplaceIngredients(tx *gorm.DB, recipeID uint, ingredients []models.IngredientsRecipes) error {
+	if err := tx.Where("recipe_id = ?", recipeID).Delete(&models.IngredientsRecipes{}).Error; err != nil {
+		return err
+	}
+	if len(ingredients) == 0 {
+		return nil
+	}
+
+	for i := range ingredients {
+		ingredients[i].RecipeID = recipeID
+	}
+	return tx.Omit("Ingredient").Create(&ingredients).Error
+}
@@ -0,0 +1,229 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Formatos de arquivo aceitos na importação e exportação
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Tipos de registro do catálogo
+const (
+	KindIngredients = "ingredients"
+	KindRecipes     = "recipes"
+)
+
+// IngredientRecord é uma linha de ingrediente do arquivo. Em CSV usa a coluna "name".
+type IngredientRecord struct {
+	Name string `json:"name"`
+}
+
+// RecipeRecord é uma receita do arquivo. Em CSV cada linha traz um ingrediente da receita,
+// com as colunas da receita repetidas: name, user, instructions, servings, prep_time,
+// cook_time, total_time, visibility, ingredient, quantity e, opcionalmente, group, note,
+// optional e to_taste.
+type RecipeRecord struct {
+	Name         string                   `json:"name"`
+	User         string                   `json:"user,omitempty"`
+	Instructions string                   `json:"instructions"`
+	Servings     int                      `json:"servings,omitempty"`
+	PrepTime     int                      `json:"prep_time,omitempty"`
+	CookTime     int                      `json:"cook_time,omitempty"`
+	TotalTime    int                      `json:"total_time,omitempty"`
+	Visibility   string                   `json:"visibility,omitempty"`
+	Ingredients  []RecipeIngredientRecord `json:"ingredients"`
+}
+
+// RecipeIngredientRecord é um ingrediente de uma receita do arquivo.
+type RecipeIngredientRecord struct {
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+	Group    string `json:"group,omitempty"`
+	Note     string `json:"note,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	ToTaste  bool   `json:"to_taste,omitempty"`
+}
+
+var ingredientColumns = []string{"name"}
+
+var recipeColumns = []string{"name", "user", "instructions", "servings", "prep_time", "cook_time", "total_time", "visibility", "ingredient", "quantity", "group", "note", "optional", "to_taste"}
+
+// row é um registro lido do arquivo, com o número da linha de origem para o relatório de erros
+type row[T any] struct {
+	Line   int
+	Record T
+	Err    error
+}
+
+// readNDJSON lê um registro JSON por linha, ignorando linhas em branco
+func readNDJSON[T any](r io.Reader) ([]row[T], error) {
+	var rows []row[T]
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record T
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&record)
+		rows = append(rows, row[T]{Line: line, Record: record, Err: err})
+	}
+
+	return rows, scanner.Err()
+}
+
+// readCSV lê o arquivo CSV, exigindo um cabeçalho com as colunas obrigatórias
+func readCSV(r io.Reader, columns []string, required ...string) ([]map[string]string, []int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		index[name] = i
+	}
+	for _, name := range required {
+		if _, ok := index[name]; !ok {
+			return nil, nil, fmt.Errorf("CSV header is missing column %q", name)
+		}
+	}
+
+	var records []map[string]string
+	var lines []int
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		record := map[string]string{}
+		for _, name := range columns {
+			if i, ok := index[name]; ok && i < len(fields) {
+				record[name] = strings.TrimSpace(fields[i])
+			}
+		}
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+
+	return records, lines, nil
+}
+
+func readIngredients(r io.Reader, format string) ([]row[IngredientRecord], error) {
+	if format == FormatNDJSON {
+		return readNDJSON[IngredientRecord](r)
+	}
+
+	records, lines, err := readCSV(r, ingredientColumns, "name")
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]row[IngredientRecord], len(records))
+	for i, record := range records {
+		rows[i] = row[IngredientRecord]{Line: lines[i], Record: IngredientRecord{Name: record["name"]}}
+	}
+	return rows, nil
+}
+
+// readRecipes lê as receitas; em CSV, linhas consecutivas com o mesmo nome e usuário formam uma receita
+func readRecipes(r io.Reader, format string) ([]row[RecipeRecord], error) {
+	if format == FormatNDJSON {
+		return readNDJSON[RecipeRecord](r)
+	}
+
+	records, lines, err := readCSV(r, recipeColumns, "name", "ingredient", "quantity")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []row[RecipeRecord]
+	for i, record := range records {
+		name := record["name"]
+
+		if len(rows) == 0 || !strings.EqualFold(rows[len(rows)-1].Record.Name, name) || name == "" ||
+			rows[len(rows)-1].Record.User != record["user"] {
+			current := row[RecipeRecord]{Line: lines[i], Record: RecipeRecord{
+				Name:         name,
+				User:         record["user"],
+				Instructions: record["instructions"],
+				Visibility:   record["visibility"],
+			}}
+			for _, field := range []struct {
+				column string
+				target *int
+			}{
+				{"servings", &current.Record.Servings},
+				{"prep_time", &current.Record.PrepTime},
+				{"cook_time", &current.Record.CookTime},
+				{"total_time", &current.Record.TotalTime},
+			} {
+				if record[field.column] == "" {
+					continue
+				}
+				n, err := strconv.Atoi(record[field.column])
+				if err != nil {
+					current.Err = fmt.Errorf("column %s must be an integer", field.column)
+				}
+				*field.target = n
+			}
+			rows = append(rows, current)
+		}
+
+		if record["ingredient"] != "" || record["quantity"] != "" {
+			last := &rows[len(rows)-1]
+			item := RecipeIngredientRecord{
+				Name:     record["ingredient"],
+				Quantity: record["quantity"],
+				Group:    record["group"],
+				Note:     record["note"],
+			}
+			for _, flag := range []struct {
+				column string
+				target *bool
+			}{
+				{"optional", &item.Optional},
+				{"to_taste", &item.ToTaste},
+			} {
+				if record[flag.column] == "" {
+					continue
+				}
+				value, err := strconv.ParseBool(record[flag.column])
+				if err != nil {
+					last.Err = fmt.Errorf("ingredient %d: column %s must be true or false", len(last.Record.Ingredients)+1, flag.column)
+				}
+				*flag.target = value
+			}
+			last.Record.Ingredients = append(last.Record.Ingredients, item)
+		}
+	}
+
+	return rows, nil
+}
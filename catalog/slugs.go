@@ -0,0 +1,128 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// defaultSlug é usado para nomes sem nenhuma letra ou número aproveitável
+const defaultSlug = "receita"
+
+// Slugify converte o nome em um slug: minúsculo, sem acentos e com hífens no lugar de espaços e
+// pontuação (ex.: "Pão de Açúcar!" vira "pao-de-acucar").
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+
+	// Na forma decomposta (NFD) os acentos são marcas separadas da letra e podem ser descartados
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+
+	if b.Len() == 0 {
+		return defaultSlug
+	}
+	return b.String()
+}
+
+// AssignSlug define o slug da receita antes de gravá-la. O slug atual é mantido enquanto o nome e o
+// autor não mudarem; caso contrário, o antigo passa a redirecionar para o novo. Slugs já usados por
+// outra receita do autor, atuais ou antigos, recebem um sufixo numérico (ex.: "bolo-2").
+func AssignSlug(tx *gorm.DB, recipe *models.Recipe) error {
+	base := Slugify(recipe.Name)
+
+	if recipe.ID != 0 {
+		var current models.Recipe
+
+		result := tx.Unscoped().Select("id", "user_id", "name", "slug").Where("id = ?", recipe.ID).Limit(1).Find(&current)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected > 0 && current.Slug != "" {
+			if current.UserID == recipe.UserID && Slugify(current.Name) == base {
+				recipe.Slug = current.Slug
+				return nil
+			}
+
+			redirect := models.RecipeSlugRedirect{RecipeID: recipe.ID, UserID: current.UserID, Slug: current.Slug}
+			if err := tx.Where(redirect).FirstOrCreate(&redirect).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		available, err := slugAvailable(tx, recipe, candidate)
+		if err != nil {
+			return err
+		}
+		if available {
+			recipe.Slug = candidate
+			// Um slug antigo da própria receita volta a ser o atual
+			return tx.Where("recipe_id = ? AND user_id = ? AND slug = ?", recipe.ID, recipe.UserID, candidate).
+				Delete(&models.RecipeSlugRedirect{}).Error
+		}
+	}
+}
+
+// BackfillSlugs gera o slug das receitas criadas antes dos slugs existirem, incluindo as que estão na
+// lixeira.
+func BackfillSlugs(db *gorm.DB) error {
+	var recipes []models.Recipe
+	if err := db.Unscoped().Select("id", "user_id", "name").Where("slug = ''").Order("id").Find(&recipes).Error; err != nil {
+		return err
+	}
+
+	for _, recipe := range recipes {
+		if err := AssignSlug(db, &recipe); err != nil {
+			return fmt.Errorf("recipe %d: %w", recipe.ID, err)
+		}
+		if err := db.Unscoped().Model(&recipe).UpdateColumn("slug", recipe.Slug).Error; err != nil {
+			return fmt.Errorf("recipe %d: %w", recipe.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Funções privadas
+
+// slugAvailable indica que o slug não é usado por outra receita do autor, nem como slug atual nem
+// como redirecionamento
+func slugAvailable(tx *gorm.DB, recipe *models.Recipe, slug string) (bool, error) {
+	var count int64
+
+	// Receitas na lixeira mantêm o slug, para poderem ser restauradas
+	err := tx.Unscoped().Model(&models.Recipe{}).Where("user_id = ? AND slug = ? AND id <> ?", recipe.UserID, slug, recipe.ID).Count(&count).Error
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	err = tx.Model(&models.RecipeSlugRedirect{}).Where("user_id = ? AND slug = ? AND recipe_id <> ?", recipe.UserID, slug, recipe.ID).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
@@ -0,0 +1,233 @@
+package catalog
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// quantityNumberRe separa o número do início da quantidade (inteiro, decimal, fração, número misto
+// ou intervalo, como "2-3") do restante, geralmente a unidade
+var quantityNumberRe = regexp.MustCompile(`^\s*(\d+\s+\d+/\d+|\d+/\d+|\d+(?:[.,]\d+)?)(?:\s*-\s*(\d+\s+\d+/\d+|\d+/\d+|\d+(?:[.,]\d+)?))?(.*)$`)
+
+// FindSubstitutions busca as substituições dos ingredientes informados, com os ingredientes usados
+// em cada uma. Com reason, apenas as substituições desse motivo são consideradas. O retorno é
+// indexado pelo ID do ingrediente substituído.
+func FindSubstitutions(db *gorm.DB, ingredientIDs []uint, reason string) (map[uint][]models.Substitution, error) {
+	query := db.Preload("Replacements", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("Replacements.Ingredient").Where("ingredient_id IN ?", ingredientIDs)
+	if reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+
+	var substitutions []models.Substitution
+	if err := query.Order("id").Find(&substitutions).Error; err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint][]models.Substitution)
+	for _, substitution := range substitutions {
+		found[substitution.IngredientID] = append(found[substitution.IngredientID], substitution)
+	}
+	return found, nil
+}
+
+// SuggestSubstitutions lista, para cada ingrediente da receita já carregada, as substituições
+// possíveis com as quantidades calculadas a partir da quantidade usada na receita. Os nomes dos
+// ingredientes são traduzidos para o idioma informado.
+func SuggestSubstitutions(db *gorm.DB, recipe *models.Recipe, reason string, locale string) (*models.RecipeSubstitutions, error) {
+	ids := make([]uint, 0, len(recipe.IngredientsRecipes))
+	for _, item := range recipe.IngredientsRecipes {
+		ids = append(ids, item.IngredientID)
+	}
+
+	found, err := FindSubstitutions(db, ids, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredients := make([]*models.Ingredient, 0, len(ids))
+	for i := range recipe.IngredientsRecipes {
+		ingredients = append(ingredients, &recipe.IngredientsRecipes[i].Ingredient)
+	}
+	for _, substitutions := range found {
+		for i := range substitutions {
+			for j := range substitutions[i].Replacements {
+				ingredients = append(ingredients, &substitutions[i].Replacements[j].Ingredient)
+			}
+		}
+	}
+	if err := Localize(db, locale, ingredients...); err != nil {
+		return nil, err
+	}
+
+	return Suggestions(recipe, found), nil
+}
+
+// Suggestions monta as sugestões de SuggestSubstitutions a partir das substituições já buscadas, como
+// em FindSubstitutions, sem traduzir os nomes dos ingredientes.
+func Suggestions(recipe *models.Recipe, found map[uint][]models.Substitution) *models.RecipeSubstitutions {
+	suggestions := &models.RecipeSubstitutions{RecipeID: recipe.ID, Ingredients: []models.RecipeIngredientSubstitutions{}}
+	for _, item := range recipe.IngredientsRecipes {
+		entry := models.RecipeIngredientSubstitutions{
+			IngredientID:  item.IngredientID,
+			Name:          item.Ingredient.Name,
+			Quantity:      item.Quantity,
+			Substitutions: []models.SubstitutionSuggestion{},
+		}
+
+		for _, substitution := range found[item.IngredientID] {
+			suggestion := models.SubstitutionSuggestion{
+				SubstitutionID: substitution.ID,
+				Reason:         substitution.Reason,
+				Notes:          substitution.Notes,
+				Replacements:   []models.SuggestedReplacement{},
+			}
+			for _, replacement := range substitution.Replacements {
+				suggestion.Replacements = append(suggestion.Replacements, models.SuggestedReplacement{
+					IngredientID: replacement.IngredientID,
+					Name:         replacement.Ingredient.Name,
+					Quantity:     ScaleQuantity(item.Quantity, replacement.Ratio),
+					Note:         replacement.Note,
+				})
+			}
+			entry.Substitutions = append(entry.Substitutions, suggestion)
+		}
+
+		suggestions.Ingredients = append(suggestions.Ingredients, entry)
+	}
+
+	return suggestions
+}
+
+// ApplySubstitutions troca cada ingrediente das linhas, já carregadas com os ingredientes, pela
+// primeira substituição do motivo informado; os ingredientes sem substituição são mantidos. Um
+// ingrediente que já está na receita tem as quantidades somadas em uma única linha.
+func ApplySubstitutions(db *gorm.DB, lines []models.IngredientsRecipes, reason string) ([]models.IngredientsRecipes, error) {
+	ids := make([]uint, 0, len(lines))
+	for _, line := range lines {
+		ids = append(ids, line.IngredientID)
+	}
+
+	found, err := FindSubstitutions(db, ids, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return Substitute(lines, found), nil
+}
+
+// Substitute troca os ingredientes das linhas como ApplySubstitutions, a partir das substituições já
+// buscadas, como em FindSubstitutions.
+func Substitute(lines []models.IngredientsRecipes, found map[uint][]models.Substitution) []models.IngredientsRecipes {
+	var applied []models.IngredientsRecipes
+	positions := make(map[uint]int)
+	add := func(line models.IngredientsRecipes) {
+		if i, ok := positions[line.IngredientID]; ok {
+			applied[i].Quantity = joinQuantities(applied[i].Quantity, line.Quantity)
+			if line.Note != "" && line.Note != applied[i].Note {
+				applied[i].Note = strings.TrimPrefix(applied[i].Note+"; "+line.Note, "; ")
+			}
+			applied[i].ToTaste = applied[i].ToTaste && line.ToTaste
+			applied[i].Optional = applied[i].Optional && line.Optional
+			return
+		}
+		line.Position = len(applied)
+		positions[line.IngredientID] = line.Position
+		applied = append(applied, line)
+	}
+
+	for _, line := range lines {
+		substitutions := found[line.IngredientID]
+		if len(substitutions) == 0 {
+			add(line)
+			continue
+		}
+
+		for _, replacement := range substitutions[0].Replacements {
+			note := fmt.Sprintf("replaces %s", line.Ingredient.Name)
+			if replacement.Note != "" {
+				note = replacement.Note + "; " + note
+			}
+			add(models.IngredientsRecipes{
+				IngredientID: replacement.IngredientID,
+				Quantity:     ScaleQuantity(line.Quantity, replacement.Ratio),
+				Group:        line.Group,
+				Note:         note,
+				Optional:     line.Optional,
+				ToTaste:      line.ToTaste,
+				Ingredient:   replacement.Ingredient,
+			})
+		}
+	}
+
+	return applied
+}
+
+// ScaleQuantity multiplica o número do início da quantidade pela proporção, mantendo a unidade (ex.:
+// "2 xícaras" com 0.5 vira "1 xícaras"). Quantidades sem número recebem a proporção por extenso.
+func ScaleQuantity(quantity string, ratio float64) string {
+	if ratio == 1 || strings.TrimSpace(quantity) == "" {
+		return quantity
+	}
+
+	match := quantityNumberRe.FindStringSubmatch(quantity)
+	if match == nil {
+		return fmt.Sprintf("%s (x%s)", quantity, formatNumber(ratio, false))
+	}
+
+	// Decimais usam vírgula, a menos que a quantidade original use ponto
+	comma := !strings.Contains(match[1], ".") && !strings.Contains(match[2], ".")
+	scaled := formatNumber(parseNumber(match[1])*ratio, comma)
+	if match[2] != "" {
+		scaled += "-" + formatNumber(parseNumber(match[2])*ratio, comma)
+	}
+	return scaled + match[3]
+}
+
+// Funções privadas
+
+// parseNumber converte números como "2", "1,5", "1/2" e "1 1/2", já validados pela expressão
+func parseNumber(value string) float64 {
+	value = strings.ReplaceAll(value, ",", ".")
+
+	var total float64
+	for _, part := range strings.Fields(value) {
+		if numerator, denominator, ok := strings.Cut(part, "/"); ok {
+			n, _ := strconv.ParseFloat(numerator, 64)
+			d, _ := strconv.ParseFloat(denominator, 64)
+			if d != 0 {
+				total += n / d
+			}
+			continue
+		}
+		n, _ := strconv.ParseFloat(part, 64)
+		total += n
+	}
+	return total
+}
+
+// formatNumber escreve o número com até duas casas decimais, usando vírgula quando pedido
+func formatNumber(value float64, comma bool) string {
+	formatted := strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+	if comma {
+		formatted = strings.ReplaceAll(formatted, ".", ",")
+	}
+	return formatted
+}
+
+func joinQuantities(a string, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + " + " + b
+}
@@ -0,0 +1,30 @@
+package catalog
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// Visible é o escopo das receitas que o usuário pode abrir pelo endereço: as próprias, em qualquer
+// estado, e as publicadas ou não listadas de outros autores cuja data de publicação já chegou.
+// Usuários anônimos têm ID 0.
+func Visible(userID uint) func(db *gorm.DB) *gorm.DB {
+	return visibility(userID, models.VisibilityPublished, models.VisibilityUnlisted)
+}
+
+// Listed é o escopo das receitas que aparecem nas listagens para o usuário: as próprias, em qualquer
+// estado, e as publicadas de outros autores cuja data de publicação já chegou.
+func Listed(userID uint) func(db *gorm.DB) *gorm.DB {
+	return visibility(userID, models.VisibilityPublished)
+}
+
+// Funções privadas
+
+func visibility(userID uint, states ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("recipes.user_id = ? OR (recipes.visibility IN ? AND (recipes.publish_at IS NULL OR recipes.publish_at <= ?))",
+			userID, states, time.Now().UTC())
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/catalog"
+	"main.go/models"
+)
+
+// catalogImport importa um arquivo CSV ou NDJSON e imprime o relatório em JSON. Sai com erro se
+// algum registro for rejeitado.
+func catalogImport(db *gorm.DB, args []string) error {
+	flags := newFlagSet("catalog import")
+	format := flags.String("format", "", "formato do arquivo (padrão pela extensão)")
+	dryRun := flags.Bool("dry-run", false, "apenas validar, sem gravar")
+	username := flags.String("user", "", "dono das receitas sem a coluna user")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 2 {
+		return errUsage
+	}
+	kind, path := flags.Arg(0), flags.Arg(1)
+
+	if *format == "" {
+		*format = formatFromPath(path)
+	}
+
+	opts := catalog.Options{Format: *format, DryRun: *dryRun}
+	if *username != "" {
+		var user models.User
+		if err := db.Select("id").Where("username = ?", *username).First(&user).Error; err != nil {
+			return fmt.Errorf("user %q: %w", *username, err)
+		}
+		opts.DefaultUserID = user.ID
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	report, err := catalog.Import(db, kind, input, opts)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d of %d records rejected, nothing was written", len(report.Errors), report.Total)
+	}
+	return nil
+}
+
+// catalogExport exporta o catálogo para um arquivo ou para a saída padrão
+func catalogExport(db *gorm.DB, args []string) error {
+	flags := newFlagSet("catalog export")
+	format := flags.String("format", "", "formato do arquivo (padrão pela extensão, ou csv)")
+	output := flags.String("output", "", "arquivo de saída (padrão: saída padrão)")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 1 {
+		return errUsage
+	}
+
+	if *format == "" {
+		*format = formatFromPath(*output)
+	}
+
+	if *output == "" {
+		return catalog.Export(db, flags.Arg(0), os.Stdout, *format)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := catalog.Export(db, flags.Arg(0), file, *format); err != nil {
+		file.Close()
+		os.Remove(*output)
+		return err
+	}
+	return file.Close()
+}
+
+// formatFromPath deduz o formato pela extensão do arquivo, usando CSV quando não for possível
+func formatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl", ".json":
+		return catalog.FormatNDJSON
+	}
+	return catalog.FormatCSV
+}
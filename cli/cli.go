@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gorm.io/gorm"
+	"main.go/config"
+	"main.go/db"
+)
+
+// command é um comando da linha de comando, identificado por grupo e nome (ex.: catalog import).
+// Os comandos com run recebem a conexão com o banco; os com runConfig, apenas a configuração, e
+// funcionam mesmo sem acesso ao banco.
+type command struct {
+	group     string
+	name      string
+	usage     string
+	run       func(db *gorm.DB, args []string) error
+	runConfig func(cfg *config.Config, args []string) error
+}
+
+var commands = []command{
+	{"catalog", "import", "catalog import [-format csv|ndjson] [-dry-run] [-user username] <ingredients|recipes> <file|->", catalogImport, nil},
+	{"catalog", "export", "catalog export [-format csv|ndjson] [-output file] <ingredients|recipes>", catalogExport, nil},
+	{"user", "role", "user role <email> <user|editor|admin>", userRole, nil},
+	{"migrate", "up", "migrate up", migrateUp, nil},
+	{"migrate", "down", "migrate down [-steps n]", migrateDown, nil},
+	{"migrate", "status", "migrate status", migrateStatus, nil},
+	{"config", "print", "config print", nil, configPrint},
+}
+
+// errUsage indica argumentos inválidos; o uso do comando é exibido no lugar do erro
+var errUsage = errors.New("invalid arguments")
+
+// Run executa o comando informado nos argumentos (sem o nome do programa) e retorna o código de
+// saída do processo. A conexão com o banco só é aberta para os comandos que a usam.
+func Run(cfg *config.Config, args []string) int {
+	if len(args) >= 2 {
+		for _, cmd := range commands {
+			if cmd.group != args[0] || cmd.name != args[1] {
+				continue
+			}
+
+			var err error
+			if cmd.runConfig != nil {
+				err = cmd.runConfig(cfg, args[2:])
+			} else if err = cfg.Database.Validate(); err == nil {
+				err = cmd.run(db.InitDB(cfg.Database), args[2:])
+			}
+			if err == errUsage || err == flag.ErrHelp {
+				fmt.Fprintf(os.Stderr, "usage: %s\n", cmd.usage)
+				return 2
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				return 1
+			}
+			return 0
+		}
+	}
+
+	printUsage(os.Stderr)
+	return 2
+}
+
+// Funções privadas
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\n", cmd.usage)
+	}
+}
+
+// newFlagSet cria o conjunto de flags de um comando, sem imprimir mensagens próprias de erro
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"os"
+
+	"main.go/config"
+)
+
+// configPrint imprime a configuração carregada, sem os valores secretos, e sai com erro se ela não
+// for válida
+func configPrint(cfg *config.Config, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	if err := cfg.Print(os.Stdout); err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"gorm.io/gorm"
+	"main.go/db/migrations"
+)
+
+// migrateUp aplica as migrações pendentes
+func migrateUp(db *gorm.DB, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		fmt.Printf("Applied %s\n", migration)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("Database is up to date")
+	}
+	return nil
+}
+
+// migrateDown desfaz as últimas migrações aplicadas; por padrão, apenas a última
+func migrateDown(db *gorm.DB, args []string) error {
+	flags := newFlagSet("migrate down")
+	steps := flags.Int("steps", 1, "quantidade de migrações a desfazer")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != 0 || *steps < 1 {
+		return errUsage
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	reverted, err := migrator.Down(context.Background(), *steps)
+	for _, migration := range reverted {
+		fmt.Printf("Reverted %s\n", migration)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(reverted) == 0 {
+		fmt.Println("No migrations to revert")
+	}
+	return nil
+}
+
+// migrateStatus lista as migrações com a situação de cada uma no banco
+func migrateStatus(db *gorm.DB, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, status.Status, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// userRole altera o papel de um usuário; é a forma de definir os primeiros administradores
+func userRole(db *gorm.DB, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	email, role := args[0], args[1]
+
+	if role != models.RoleUser && role != models.RoleEditor && role != models.RoleAdmin {
+		return fmt.Errorf("invalid role %q", role)
+	}
+
+	result := db.Model(&models.User{}).Where("email = ?", email).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user %q not found", email)
+	}
+
+	fmt.Printf("User %s is now %s\n", email, role)
+	return nil
+}
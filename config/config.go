@@ -0,0 +1,464 @@
+// Package config lê a configuração da aplicação. Cada valor vem, em ordem de prioridade, das
+// variáveis de ambiente, do arquivo .env (opcional), do arquivo JSON indicado em CONFIG_FILE
+// (opcional) ou do valor padrão.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// MinSecretLength é o tamanho mínimo do SECRET, que assina os tokens JWT e as URLs dos arquivos
+const MinSecretLength = 32
+
+// redacted substitui os valores secretos em Print
+const redacted = "[REDACTED]"
+
+// Config é a configuração da aplicação. A tag env indica a variável de ambiente (e a chave do .env)
+// de cada campo, e a tag json, a chave no arquivo de configuração. Os campos com a tag secret não
+// são exibidos por Print.
+type Config struct {
+	// Secret assina os tokens de autenticação e as URLs dos arquivos guardados
+	Secret string `json:"secret" env:"SECRET" secret:"true"`
+	// MigrateOnStart aplica as migrações pendentes ao iniciar o servidor; sem ele, o servidor não
+	// inicia enquanto houver migrações pendentes
+	MigrateOnStart bool `json:"migrate_on_start" env:"MIGRATE_ON_START"`
+	// TemplatesDir contém os templates que substituem os embutidos na exportação das receitas
+	TemplatesDir string `json:"templates_dir" env:"TEMPLATES_DIR"`
+	// TrashRetentionDays é o tempo que os itens ficam na lixeira antes de serem removidos
+	TrashRetentionDays int `json:"trash_retention_days" env:"TRASH_RETENTION_DAYS"`
+
+	Log      Log      `json:"log"`
+	Tracing  Tracing  `json:"tracing"`
+	Server   Server   `json:"server"`
+	Database Database `json:"database"`
+	Storage  Storage  `json:"storage"`
+}
+
+// Log é o log da aplicação, escrito na saída padrão.
+type Log struct {
+	// Level é o nível mínimo: debug, info, warn ou error. No nível debug, o log das requisições inclui
+	// os cabeçalhos
+	Level string `json:"level" env:"LOG_LEVEL"`
+	// Format é json ou text
+	Format string `json:"format" env:"LOG_FORMAT"`
+}
+
+// Tracing é o envio dos traces do OpenTelemetry: "otlp" envia ao coletor em Endpoint (ou no
+// OTEL_EXPORTER_OTLP_ENDPOINT padrão do OpenTelemetry), "stdout" escreve os spans em JSON na saída
+// padrão ou no arquivo em File, para uso sem coletor, e "none" desativa os traces.
+type Tracing struct {
+	Exporter    string `json:"exporter" env:"TRACING_EXPORTER"`
+	Endpoint    string `json:"endpoint" env:"TRACING_ENDPOINT"`
+	File        string `json:"file" env:"TRACING_FILE"`
+	ServiceName string `json:"service_name" env:"OTEL_SERVICE_NAME"`
+	// SamplePercent é a porcentagem das requisições iniciadas aqui que são registradas; as que chegam
+	// com um trace seguem a decisão de quem as enviou
+	SamplePercent int `json:"sample_percent" env:"TRACING_SAMPLE_PERCENT"`
+}
+
+// Server é o servidor HTTP. Com TLSCertFile e TLSKeyFile, ele atende por HTTPS, e RedirectAddr, se
+// informado, escuta por HTTP apenas para redirecionar as requisições ao endereço HTTPS.
+type Server struct {
+	// Addr é o endereço em que o servidor HTTP escuta
+	Addr string `json:"addr" env:"HTTP_ADDR"`
+	// ReadHeaderTimeoutSeconds limita a leitura dos cabeçalhos, e ReadTimeoutSeconds, a da
+	// requisição inteira, incluindo os arquivos enviados
+	ReadHeaderTimeoutSeconds int `json:"read_header_timeout_seconds" env:"HTTP_READ_HEADER_TIMEOUT_SECONDS"`
+	ReadTimeoutSeconds       int `json:"read_timeout_seconds" env:"HTTP_READ_TIMEOUT_SECONDS"`
+	// WriteTimeoutSeconds limita a escrita da resposta, incluindo os downloads
+	WriteTimeoutSeconds int `json:"write_timeout_seconds" env:"HTTP_WRITE_TIMEOUT_SECONDS"`
+	// IdleTimeoutSeconds é o tempo que uma conexão keep-alive fica aberta sem requisições
+	IdleTimeoutSeconds int `json:"idle_timeout_seconds" env:"HTTP_IDLE_TIMEOUT_SECONDS"`
+	// MaxHeaderBytes é o tamanho máximo dos cabeçalhos de uma requisição
+	MaxHeaderBytes int `json:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES"`
+	// ShutdownTimeoutSeconds é o tempo que as requisições em andamento têm para terminar depois de
+	// SIGINT ou SIGTERM
+	ShutdownTimeoutSeconds int    `json:"shutdown_timeout_seconds" env:"SHUTDOWN_TIMEOUT_SECONDS"`
+	TLSCertFile            string `json:"tls_cert_file" env:"TLS_CERT_FILE"`
+	TLSKeyFile             string `json:"tls_key_file" env:"TLS_KEY_FILE"`
+	RedirectAddr           string `json:"redirect_addr" env:"HTTP_REDIRECT_ADDR"`
+	// PublicURL é o endereço público da API (ex.: https://api.example.com), usado nas URLs absolutas
+	// dos documentos gerados; sem ele, as URLs usam o endereço em que a requisição chegou
+	PublicURL string `json:"public_url" env:"PUBLIC_URL"`
+	// TrustProxy aceita os cabeçalhos X-Forwarded-Proto e X-Forwarded-Host, que só devem ser
+	// considerados quando o servidor fica atrás de um proxy que os define
+	TrustProxy bool `json:"trust_proxy" env:"TRUST_PROXY"`
+}
+
+// Database é a conexão com o banco: o Postgres ou o SQLite, com o arquivo em Name.
+type Database struct {
+	Driver   string `json:"driver" env:"DB_DRIVER"`
+	Host     string `json:"host" env:"HOST"`
+	User     string `json:"user" env:"USER"`
+	Password string `json:"password" env:"PASSWORD" secret:"true"`
+	Name     string `json:"name" env:"DATABASE"`
+	Port     string `json:"port" env:"PORT"`
+	SSLMode  string `json:"sslmode" env:"SSLMODE"`
+	TimeZone string `json:"timezone" env:"TIMEZONE"`
+}
+
+// Storage é o armazenamento dos arquivos enviados: um diretório local ou um bucket S3.
+type Storage struct {
+	Driver string `json:"driver" env:"STORAGE_DRIVER"`
+	Path   string `json:"path" env:"STORAGE_PATH"`
+	// MediaURLTTLMinutes é a validade das URLs assinadas dos arquivos
+	MediaURLTTLMinutes int `json:"media_url_ttl_minutes" env:"MEDIA_URL_TTL_MINUTES"`
+	S3                 S3  `json:"s3"`
+}
+
+// S3 é o acesso ao bucket usado quando Storage.Driver é "s3".
+type S3 struct {
+	Endpoint  string `json:"endpoint" env:"S3_ENDPOINT"`
+	Region    string `json:"region" env:"S3_REGION"`
+	Bucket    string `json:"bucket" env:"S3_BUCKET"`
+	AccessKey string `json:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `json:"secret_key" env:"S3_SECRET_KEY" secret:"true"`
+}
+
+// Default retorna a configuração usada quando nenhum valor é informado.
+func Default() *Config {
+	return &Config{
+		MigrateOnStart:     true,
+		TrashRetentionDays: 30,
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: Tracing{
+			Exporter:      "none",
+			ServiceName:   "cookbook-api",
+			SamplePercent: 100,
+		},
+		Server: Server{
+			Addr:                     ":3000",
+			ReadHeaderTimeoutSeconds: 5,
+			ReadTimeoutSeconds:       60,
+			WriteTimeoutSeconds:      120,
+			IdleTimeoutSeconds:       120,
+			MaxHeaderBytes:           1 << 20,
+			ShutdownTimeoutSeconds:   30,
+		},
+		Database: Database{
+			Driver: "postgres",
+		},
+		Storage: Storage{
+			Driver:             "local",
+			Path:               "uploads",
+			MediaURLTTLMinutes: 15,
+		},
+	}
+}
+
+// Load lê a configuração do arquivo .env do diretório atual, do arquivo em CONFIG_FILE e das
+// variáveis de ambiente. Os valores não são validados; veja Validate.
+func Load() (*Config, error) {
+	dotenv, err := godotenv.Read(".env")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read .env: %w", err)
+	}
+
+	lookup := func(name string) (string, bool) {
+		if value, ok := os.LookupEnv(name); ok {
+			return value, true
+		}
+		value, ok := dotenv[name]
+		return value, ok
+	}
+
+	cfg := Default()
+
+	if path, _ := lookup("CONFIG_FILE"); path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	cfg.fields(func(field reflect.StructField, value reflect.Value) {
+		text, ok := lookup(field.Tag.Get("env"))
+		if !ok {
+			return
+		}
+		if err := set(value, text); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field.Tag.Get("env"), err))
+		}
+	})
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return cfg, nil
+}
+
+// Validate confere a configuração, retornando todos os problemas encontrados.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if err := validateSecret("SECRET", c.Secret); err != nil {
+		errs = append(errs, err)
+	}
+	if c.TrashRetentionDays < 1 {
+		errs = append(errs, errors.New("TRASH_RETENTION_DAYS must be at least 1"))
+	}
+	if err := c.Log.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Server.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Database.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Storage.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate confere o nível e o formato do log.
+func (l *Log) Validate() error {
+	var errs []error
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, strings.ToLower(l.Level)) {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, not %q", l.Level))
+	}
+	if l.Format != "json" && l.Format != "text" {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT must be json or text, not %q", l.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate confere o envio dos traces.
+func (t *Tracing) Validate() error {
+	var errs []error
+
+	switch t.Exporter {
+	case "none", "otlp", "stdout":
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER must be none, otlp or stdout, not %q", t.Exporter))
+	}
+	if t.Exporter != "none" && t.ServiceName == "" {
+		errs = append(errs, errors.New("OTEL_SERVICE_NAME is required when tracing is enabled"))
+	}
+	if t.SamplePercent < 0 || t.SamplePercent > 100 {
+		errs = append(errs, errors.New("TRACING_SAMPLE_PERCENT must be between 0 and 100"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate confere o servidor HTTP.
+func (s *Server) Validate() error {
+	var errs []error
+
+	if s.Addr == "" {
+		errs = append(errs, errors.New("HTTP_ADDR is required"))
+	}
+	timeouts := []struct {
+		name  string
+		value int
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT_SECONDS", s.ReadHeaderTimeoutSeconds},
+		{"HTTP_READ_TIMEOUT_SECONDS", s.ReadTimeoutSeconds},
+		{"HTTP_WRITE_TIMEOUT_SECONDS", s.WriteTimeoutSeconds},
+		{"HTTP_IDLE_TIMEOUT_SECONDS", s.IdleTimeoutSeconds},
+		{"SHUTDOWN_TIMEOUT_SECONDS", s.ShutdownTimeoutSeconds},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 1 {
+			errs = append(errs, fmt.Errorf("%s must be at least 1", timeout.name))
+		}
+	}
+	if s.MaxHeaderBytes < 1024 {
+		errs = append(errs, errors.New("HTTP_MAX_HEADER_BYTES must be at least 1024"))
+	}
+	if (s.TLSCertFile == "") != (s.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if s.RedirectAddr != "" && !s.TLS() {
+		errs = append(errs, errors.New("HTTP_REDIRECT_ADDR requires TLS_CERT_FILE and TLS_KEY_FILE"))
+	}
+	if s.RedirectAddr != "" && s.RedirectAddr == s.Addr {
+		errs = append(errs, errors.New("HTTP_REDIRECT_ADDR must differ from HTTP_ADDR"))
+	}
+	if s.PublicURL != "" {
+		u, err := url.Parse(s.PublicURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+			errs = append(errs, fmt.Errorf("PUBLIC_URL must be an absolute http or https URL, not %q", s.PublicURL))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate confere a conexão com o banco, necessária também aos comandos de manutenção.
+func (d *Database) Validate() error {
+	var errs []error
+
+	switch d.Driver {
+	case "postgres":
+		if d.Host == "" {
+			errs = append(errs, errors.New("HOST is required for the postgres driver"))
+		}
+		if d.Name == "" {
+			errs = append(errs, errors.New("DATABASE is required for the postgres driver"))
+		}
+	case "sqlite":
+		if d.Name == "" {
+			errs = append(errs, errors.New("DATABASE must name the SQLite file (or :memory:)"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("DB_DRIVER must be postgres or sqlite, not %q", d.Driver))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate confere o armazenamento dos arquivos.
+func (s *Storage) Validate() error {
+	var errs []error
+
+	switch s.Driver {
+	case "local":
+		if s.Path == "" {
+			errs = append(errs, errors.New("STORAGE_PATH is required for the local driver"))
+		}
+	case "s3":
+		if s.S3.Endpoint == "" || s.S3.Bucket == "" {
+			errs = append(errs, errors.New("S3_ENDPOINT and S3_BUCKET are required for the s3 driver"))
+		}
+		if s.S3.AccessKey == "" || s.S3.SecretKey == "" {
+			errs = append(errs, errors.New("S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 driver"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("STORAGE_DRIVER must be local or s3, not %q", s.Driver))
+	}
+	if s.MediaURLTTLMinutes < 1 {
+		errs = append(errs, errors.New("MEDIA_URL_TTL_MINUTES must be at least 1"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// TLS indica se o servidor atende por HTTPS.
+func (s *Server) TLS() bool {
+	return s.TLSCertFile != "" && s.TLSKeyFile != ""
+}
+
+// TrashRetention é o tempo que os itens ficam na lixeira.
+func (c *Config) TrashRetention() time.Duration {
+	return time.Duration(c.TrashRetentionDays) * 24 * time.Hour
+}
+
+// MediaURLTTL é a validade das URLs assinadas dos arquivos.
+func (s *Storage) MediaURLTTL() time.Duration {
+	return time.Duration(s.MediaURLTTLMinutes) * time.Minute
+}
+
+// Print escreve a configuração no formato do .env, com os valores secretos ocultos.
+func (c *Config) Print(w io.Writer) error {
+	var err error
+	c.fields(func(field reflect.StructField, value reflect.Value) {
+		text := fmt.Sprint(value.Interface())
+		if field.Tag.Get("secret") == "true" && text != "" {
+			text = redacted
+		}
+		if err == nil {
+			_, err = fmt.Fprintf(w, "%s=%s\n", field.Tag.Get("env"), strconv.Quote(text))
+		}
+	})
+	return err
+}
+
+// Funções privadas
+
+// readFile lê o arquivo de configuração sobre os valores atuais, recusando chaves desconhecidas
+func (c *Config) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("read config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// fields percorre os campos com a tag env, na ordem da declaração, incluindo os das structs aninhadas
+func (c *Config) fields(fn func(field reflect.StructField, value reflect.Value)) {
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Type.Kind() == reflect.Struct {
+				walk(v.Field(i))
+				continue
+			}
+			if field.Tag.Get("env") != "" {
+				fn(field, v.Field(i))
+			}
+		}
+	}
+	walk(reflect.ValueOf(c).Elem())
+}
+
+// set converte o texto da variável para o tipo do campo
+func set(value reflect.Value, text string) error {
+	text = strings.TrimSpace(text)
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Int:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", text)
+		}
+		value.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", text)
+		}
+		value.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// validateSecret recusa segredos vazios, curtos ou formados por um único caractere repetido
+func validateSecret(name string, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("%s is required", name)
+	}
+	if len(secret) < MinSecretLength {
+		return fmt.Errorf("%s must have at least %d characters", name, MinSecretLength)
+	}
+	if len(slices.Compact([]rune(secret))) == 1 {
+		return fmt.Errorf("%s must not repeat a single character", name)
+	}
+	return nil
+}
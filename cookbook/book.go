@@ -0,0 +1,171 @@
+package cookbook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"main.go/catalog"
+	"main.go/media"
+	"main.go/models"
+	"main.go/render"
+	"main.go/storage"
+)
+
+// Book reúne o conteúdo de um livro de receitas, independente do formato de saída.
+type Book struct {
+	Title   string
+	Author  string
+	Recipes []render.RecipeView
+	// Images guarda as imagens em JPEG, indexadas pelo nome usado nas visões das receitas.
+	Images map[string][]byte
+}
+
+// IndexEntry é uma entrada do índice de ingredientes, com as posições das receitas que o usam.
+type IndexEntry struct {
+	Ingredient string
+	Recipes    []int
+}
+
+// Index monta o índice remissivo de ingredientes, em ordem alfabética (respeitando acentos).
+func (b *Book) Index() []IndexEntry {
+	positions := map[string][]int{}
+	names := map[string]string{}
+
+	for i, recipe := range b.Recipes {
+		for _, ingredient := range recipe.Ingredients {
+			key := strings.ToLower(ingredient.Name)
+			if _, ok := names[key]; !ok {
+				names[key] = ingredient.Name
+			}
+			list := positions[key]
+			if len(list) == 0 || list[len(list)-1] != i {
+				positions[key] = append(list, i)
+			}
+		}
+	}
+
+	var entries []IndexEntry
+	for key, recipes := range positions {
+		entries = append(entries, IndexEntry{Ingredient: names[key], Recipes: recipes})
+	}
+
+	collator := collate.New(language.BrazilianPortuguese, collate.IgnoreCase)
+	sort.Slice(entries, func(i, j int) bool {
+		return collator.CompareString(entries[i].Ingredient, entries[j].Ingredient) < 0
+	})
+
+	return entries
+}
+
+// LoadBook carrega do banco as receitas da exportação e suas imagens do armazenamento.
+func LoadBook(ctx context.Context, db *gorm.DB, store storage.BlobStore, export *models.CookbookExport) (*Book, error) {
+	query := db.WithContext(ctx).Scopes(catalog.PreloadIngredients).Preload("Images.Thumbnails").Order("name")
+
+	if export.RecipeIDs != "" {
+		var ids []uint
+		for _, part := range strings.Split(export.RecipeIDs, ",") {
+			id, err := strconv.ParseUint(part, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recipe id %q", part)
+			}
+			ids = append(ids, uint(id))
+		}
+		query = query.Scopes(catalog.Visible(export.UserID)).Where("id IN ?", ids)
+	} else {
+		query = query.Scopes(catalog.Listed(export.UserID)).Where("recipes.user_id = ?", export.AuthorID)
+	}
+
+	var recipes []models.Recipe
+	if err := query.Find(&recipes).Error; err != nil {
+		return nil, err
+	}
+	if len(recipes) == 0 {
+		return nil, fmt.Errorf("no recipes found")
+	}
+
+	// Nomes dos autores das receitas
+	authors := map[uint]string{}
+	for _, recipe := range recipes {
+		authors[recipe.UserID] = ""
+	}
+	var users []models.User
+	userIDs := make([]uint, 0, len(authors))
+	for id := range authors {
+		userIDs = append(userIDs, id)
+	}
+	if err := db.WithContext(ctx).Select("id", "username").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		authors[user.ID] = user.Username
+	}
+
+	book := &Book{Title: export.Title, Images: map[string][]byte{}}
+	if export.AuthorID != 0 {
+		book.Author = authors[export.AuthorID]
+	}
+
+	for _, recipe := range recipes {
+		// Chaves das imagens da receita trocadas pela versão mais adequada para impressão
+		names := map[string]string{}
+		for _, image := range recipe.Images {
+			name := fmt.Sprintf("img%d.jpg", image.ID)
+			data, err := loadImage(ctx, store, image)
+			if err != nil {
+				// Uma imagem ausente não impede a geração do livro
+				continue
+			}
+			book.Images[name] = data
+			names[image.Key] = name
+		}
+
+		// Remove da visão as imagens que não puderam ser carregadas
+		var images []models.RecipeImage
+		for _, image := range recipe.Images {
+			if _, ok := names[image.Key]; ok {
+				images = append(images, image)
+			}
+		}
+		recipe.Images = images
+
+		view := render.NewRecipeView(recipe, authors[recipe.UserID], func(key string) string {
+			return names[key]
+		})
+		book.Recipes = append(book.Recipes, view)
+	}
+
+	return book, nil
+}
+
+// loadImage lê a imagem do armazenamento, preferindo a maior miniatura (até 1024px) ao original,
+// e a converte para JPEG
+func loadImage(ctx context.Context, store storage.BlobStore, image models.RecipeImage) ([]byte, error) {
+	key := image.Key
+	largest := 0
+	for _, thumbnail := range image.Thumbnails {
+		if thumbnail.Size > largest {
+			largest = thumbnail.Size
+			key = thumbnail.Key
+		}
+	}
+
+	reader, _, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return media.ToJPEG(data)
+}
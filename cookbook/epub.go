@@ -0,0 +1,250 @@
+package cookbook
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+
+	"main.go/render"
+)
+
+var epubFuncs = template.FuncMap{
+	"minutes": render.Minutes,
+	"chapter": chapterFile,
+	"inc":     func(i int) int { return i + 1 },
+}
+
+var epubTemplates = template.Must(template.New("epub").Funcs(epubFuncs).Parse(`
+{{define "container"}}<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+{{end}}
+
+{{define "opf"}}<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="pt-BR">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">{{.ID}}</dc:identifier>
+    <dc:title>{{.Book.Title}}</dc:title>
+    <dc:language>pt-BR</dc:language>
+    {{if .Book.Author}}<dc:creator>{{.Book.Author}}</dc:creator>{{end}}
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="style" href="style.css" media-type="text/css"/>
+    <item id="title" href="title.xhtml" media-type="application/xhtml+xml"/>
+    {{range $i, $r := .Book.Recipes}}<item id="recipe-{{inc $i}}" href="{{chapter $i}}" media-type="application/xhtml+xml"/>
+    {{end}}<item id="index" href="index.xhtml" media-type="application/xhtml+xml"/>
+    {{range .Images}}<item id="{{.}}" href="images/{{.}}" media-type="image/jpeg"/>
+    {{end}}
+  </manifest>
+  <spine toc="ncx">
+    <itemref idref="title"/>
+    <itemref idref="nav"/>
+    {{range $i, $r := .Book.Recipes}}<itemref idref="recipe-{{inc $i}}"/>
+    {{end}}<itemref idref="index"/>
+  </spine>
+</package>
+{{end}}
+
+{{define "ncx"}}<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head><meta name="dtb:uid" content="{{.ID}}"/></head>
+  <docTitle><text>{{.Book.Title}}</text></docTitle>
+  <navMap>
+    {{range $i, $r := .Book.Recipes}}<navPoint id="nav-{{inc $i}}" playOrder="{{inc $i}}"><navLabel><text>{{$r.Name}}</text></navLabel><content src="{{chapter $i}}"/></navPoint>
+    {{end}}<navPoint id="nav-index" playOrder="{{len .Book.Recipes | inc}}"><navLabel><text>Índice de ingredientes</text></navLabel><content src="index.xhtml"/></navPoint>
+  </navMap>
+</ncx>
+{{end}}
+
+{{define "head"}}<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="pt-BR" lang="pt-BR">
+<head><meta charset="utf-8"/><title>{{.}}</title><link rel="stylesheet" type="text/css" href="style.css"/></head>
+{{end}}
+
+{{define "title"}}{{template "head" .Title}}<body class="title-page">
+  <h1>{{.Title}}</h1>
+  {{if .Author}}<p class="author">{{.Author}}</p>{{end}}
+</body>
+</html>
+{{end}}
+
+{{define "nav"}}{{template "head" "Sumário"}}<body>
+  <nav epub:type="toc" id="toc">
+    <h1>Sumário</h1>
+    <ol>
+      {{range $i, $r := .Recipes}}<li><a href="{{chapter $i}}">{{$r.Name}}</a></li>
+      {{end}}<li><a href="index.xhtml">Índice de ingredientes</a></li>
+    </ol>
+  </nav>
+</body>
+</html>
+{{end}}
+
+{{define "recipe"}}{{template "head" .Name}}<body>
+  <section epub:type="chapter">
+    <h1>{{.Name}}</h1>
+    {{if .Author}}<p class="author">por {{.Author}}</p>{{end}}
+    {{range .Images}}<div class="cover"><img src="images/{{.}}" alt="{{$.Name}}"/></div>{{end}}
+    {{if or .Servings .PrepTime .CookTime .TotalTime}}<p class="meta">
+      {{if .Servings}}Rendimento: {{.Servings}} porções. {{end}}
+      {{if .PrepTime}}Preparo: {{minutes .PrepTime}}. {{end}}
+      {{if .CookTime}}Cozimento: {{minutes .CookTime}}. {{end}}
+      {{if .TotalTime}}Tempo total: {{minutes .TotalTime}}.{{end}}
+    </p>{{end}}
+    <h2>Ingredientes</h2>
+    <ul>
+      {{range .Ingredients}}{{if .Heading}}<li class="group">{{.Heading}}</li>
+      {{end}}<li>{{.Text}}</li>
+      {{end}}
+    </ul>
+    <h2>Modo de preparo</h2>
+    <ol>
+      {{range .Steps}}<li>{{.Text}}{{range .Images}}<img class="step" src="images/{{.}}" alt="Passo"/>{{end}}</li>
+      {{end}}
+    </ol>
+  </section>
+</body>
+</html>
+{{end}}
+
+{{define "index"}}{{template "head" "Índice de ingredientes"}}<body>
+  <section epub:type="index">
+    <h1>Índice de ingredientes</h1>
+    <dl>
+      {{range .Entries}}<dt>{{.Ingredient}}</dt>
+      {{range .Recipes}}<dd><a href="{{chapter .}}">{{index $.Names .}}</a></dd>{{end}}
+      {{end}}
+    </dl>
+  </section>
+</body>
+</html>
+{{end}}
+`))
+
+// O html/template escaparia a declaração XML, por isso ela é escrita antes de cada template
+const xmlDeclaration = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+const epubStyle = `body { font-family: serif; line-height: 1.4; }
+h1 { font-size: 1.6em; margin-bottom: 0.2em; }
+.title-page { text-align: center; margin-top: 30%; }
+.author { font-style: italic; color: #555; }
+.meta { font-size: 0.9em; color: #555; }
+img { max-width: 100%; }
+img.step { display: block; max-width: 60%; margin: 0.5em 0; }
+li.group { list-style: none; font-weight: bold; margin: 0.5em 0 0.2em -1em; }
+dt { font-weight: bold; margin-top: 0.5em; }
+dd { margin-left: 1.5em; }
+`
+
+// epubFile é um arquivo do EPUB gerado a partir de um dos templates
+type epubFile struct {
+	name     string
+	template string
+	data     any
+}
+
+// WriteEPUB gera o livro no formato EPUB 3 (com navegação compatível com EPUB 2),
+// contendo sumário, um capítulo por receita, índice de ingredientes e imagens.
+func WriteEPUB(w io.Writer, book *Book) error {
+	zw := zip.NewWriter(w)
+
+	// O arquivo mimetype deve ser o primeiro e não pode ser comprimido
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	images := make([]string, 0, len(book.Images))
+	for name := range book.Images {
+		images = append(images, name)
+	}
+	sort.Strings(images)
+
+	id, err := bookID()
+	if err != nil {
+		return err
+	}
+
+	pkg := map[string]any{
+		"ID":       id,
+		"Book":     book,
+		"Images":   images,
+		"Modified": time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+
+	names := make([]string, len(book.Recipes))
+	for i, recipe := range book.Recipes {
+		names[i] = recipe.Name
+	}
+
+	files := []epubFile{
+		{"META-INF/container.xml", "container", nil},
+		{"OEBPS/content.opf", "opf", pkg},
+		{"OEBPS/toc.ncx", "ncx", pkg},
+		{"OEBPS/title.xhtml", "title", book},
+		{"OEBPS/nav.xhtml", "nav", book},
+		{"OEBPS/index.xhtml", "index", map[string]any{"Entries": book.Index(), "Names": names}},
+	}
+	for i, recipe := range book.Recipes {
+		files = append(files, epubFile{"OEBPS/" + chapterFile(i), "recipe", recipe})
+	}
+
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, xmlDeclaration); err != nil {
+			return err
+		}
+		if err := epubTemplates.ExecuteTemplate(fw, file.template, file.data); err != nil {
+			return fmt.Errorf("epub: rendering %s: %w", file.name, err)
+		}
+	}
+
+	style, err := zw.Create("OEBPS/style.css")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(style, epubStyle); err != nil {
+		return err
+	}
+
+	// As imagens JPEG já são comprimidas
+	for _, name := range images {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: "OEBPS/images/" + name, Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(book.Images[name]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func chapterFile(i int) string {
+	return fmt.Sprintf("recipe-%03d.xhtml", i+1)
+}
+
+// bookID gera o identificador único do livro no formato urn:uuid
+func bookID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,175 @@
+package cookbook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/logging"
+	"main.go/models"
+	"main.go/storage"
+)
+
+// ErrQueueFull indica que a fila de exportações está cheia; a exportação deve ser pedida de novo
+// mais tarde.
+var ErrQueueFull = errors.New("cookbook: export queue is full")
+
+// Exporter processa as exportações de livros de receitas em segundo plano, guardando
+// os arquivos gerados no BlobStore.
+type Exporter struct {
+	db      *gorm.DB
+	store   storage.BlobStore
+	workers int
+	queue   chan uint
+	running sync.WaitGroup
+}
+
+// NewExporter cria um Exporter com o número de workers informado.
+func NewExporter(db *gorm.DB, store storage.BlobStore, workers int) *Exporter {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Exporter{db: db, store: store, workers: workers, queue: make(chan uint, 100)}
+}
+
+// Start inicia os workers e recoloca na fila as exportações que não terminaram antes
+// de o servidor ser reiniciado. Os workers param quando ctx for cancelado.
+func (e *Exporter) Start(ctx context.Context) {
+	for i := 0; i < e.workers; i++ {
+		e.running.Add(1)
+		go func() {
+			defer e.running.Done()
+			e.work(ctx)
+		}()
+	}
+
+	var pending []models.CookbookExport
+	err := e.db.Where("status IN ?", []string{models.ExportPending, models.ExportRunning}).Order("id").Find(&pending).Error
+	if err != nil {
+		logging.FromContext(ctx).Error("Error querying pending cookbook exports", "error", err)
+		return
+	}
+
+	// As pendentes entram na fila à medida que os workers a esvaziam; as que não couberem antes de
+	// o ctx ser cancelado continuam pendentes no banco até o próximo Start
+	e.running.Add(1)
+	go func() {
+		defer e.running.Done()
+		for _, export := range pending {
+			select {
+			case e.queue <- export.ID:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Enqueue coloca a exportação na fila de processamento, sem bloquear quem chama. Retorna
+// ErrQueueFull quando a fila está cheia.
+func (e *Exporter) Enqueue(id uint) error {
+	select {
+	case e.queue <- id:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Wait espera os workers pararem depois de o ctx de Start ser cancelado, incluindo as exportações
+// em andamento.
+func (e *Exporter) Wait() {
+	e.running.Wait()
+}
+
+func (e *Exporter) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-e.queue:
+			e.process(ctx, id)
+		}
+	}
+}
+
+// process gera o EPUB e o PDF da exportação e registra o resultado no banco
+func (e *Exporter) process(ctx context.Context, id uint) {
+	ctx = logging.With(ctx, "export_id", id)
+	log := logging.FromContext(ctx)
+
+	var export models.CookbookExport
+	if err := e.db.First(&export, id).Error; err != nil {
+		log.Error("Error loading cookbook export", "error", err)
+		return
+	}
+
+	if export.Status == models.ExportDone || export.Status == models.ExportFailed {
+		return
+	}
+
+	// Sem o registro do início, a exportação é dada como falha em vez de gerada às escondidas
+	if err := e.db.Model(&export).Update("status", models.ExportRunning).Error; err != nil {
+		log.Error("Error starting cookbook export", "error", err)
+		e.finish(ctx, &export, map[string]any{"status": models.ExportFailed, "error": err.Error()})
+		return
+	}
+
+	epubKey, pdfKey, err := e.generate(ctx, &export)
+
+	// Interrompida pelo encerramento do servidor, a exportação continua em andamento e volta para a
+	// fila no próximo Start
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		log.Error("Cookbook export failed", "error", err)
+		e.finish(ctx, &export, map[string]any{"status": models.ExportFailed, "error": err.Error()})
+	} else {
+		e.finish(ctx, &export, map[string]any{"status": models.ExportDone, "epub_key": epubKey, "pdf_key": pdfKey})
+	}
+}
+
+// finish registra o resultado da exportação com o horário de término
+func (e *Exporter) finish(ctx context.Context, export *models.CookbookExport, updates map[string]any) {
+	now := time.Now()
+	updates["finished_at"] = &now
+
+	if err := e.db.Model(export).Updates(updates).Error; err != nil {
+		logging.FromContext(ctx).Error("Error updating cookbook export", "error", err)
+	}
+}
+
+func (e *Exporter) generate(ctx context.Context, export *models.CookbookExport) (string, string, error) {
+	book, err := LoadBook(ctx, e.db, e.store, export)
+	if err != nil {
+		return "", "", err
+	}
+
+	var epub bytes.Buffer
+	if err := WriteEPUB(&epub, book); err != nil {
+		return "", "", fmt.Errorf("generating EPUB: %w", err)
+	}
+
+	var pdf bytes.Buffer
+	if err := WritePDF(&pdf, book); err != nil {
+		return "", "", fmt.Errorf("generating PDF: %w", err)
+	}
+
+	epubKey := fmt.Sprintf("cookbooks/%d/cookbook.epub", export.ID)
+	if err := e.store.Put(ctx, epubKey, &epub, int64(epub.Len()), "application/epub+zip"); err != nil {
+		return "", "", fmt.Errorf("storing EPUB: %w", err)
+	}
+
+	pdfKey := fmt.Sprintf("cookbooks/%d/cookbook.pdf", export.ID)
+	if err := e.store.Put(ctx, pdfKey, &pdf, int64(pdf.Len()), "application/pdf"); err != nil {
+		return "", "", fmt.Errorf("storing PDF: %w", err)
+	}
+
+	return epubKey, pdfKey, nil
+}
@@ -0,0 +1,109 @@
+package cookbook
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"main.go/db"
+	"main.go/db/migrations"
+	"main.go/models"
+)
+
+func TestExporterEnqueueFull(t *testing.T) {
+	exporter := NewExporter(nil, nil, 1)
+
+	for id := uint(1); id <= uint(cap(exporter.queue)); id++ {
+		if err := exporter.Enqueue(id); err != nil {
+			t.Fatalf("Enqueue(%d): %v", id, err)
+		}
+	}
+	if err := exporter.Enqueue(999); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Enqueue with a full queue: err = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestExporterStartResumesPending(t *testing.T) {
+	database := openTestDB(t)
+
+	// Mais pendentes do que cabem na fila: todas devem ser processadas
+	exporter := NewExporter(database, nil, 2)
+	total := cap(exporter.queue) + 20
+	for i := 0; i < total; i++ {
+		export := models.CookbookExport{Title: "Livro", Status: models.ExportPending, RecipeIDs: "999"}
+		if err := database.Create(&export).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	exporter.Start(ctx)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var pending int64
+		database.Model(&models.CookbookExport{}).Where("status IN ?", []string{models.ExportPending, models.ExportRunning}).Count(&pending)
+		if pending == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d exports still pending", pending)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	exporter.Wait()
+}
+
+func TestExporterFailsWhenStartIsNotRecorded(t *testing.T) {
+	database := openTestDB(t)
+
+	export := models.CookbookExport{Title: "Livro", Status: models.ExportPending, RecipeIDs: "999"}
+	if err := database.Create(&export).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Somente a primeira atualização, a do início da exportação, falha
+	var updates atomic.Int32
+	err := database.Callback().Update().Before("gorm:update").Register("test:fail_start", func(tx *gorm.DB) {
+		if updates.Add(1) == 1 {
+			tx.AddError(errors.New("database is locked"))
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	NewExporter(database, nil, 1).process(context.Background(), export.ID)
+
+	if err := database.First(&export, export.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if export.Status != models.ExportFailed || export.Error != "database is locked" || export.FinishedAt == nil {
+		t.Fatalf("export = %+v, want failed with the update error", export)
+	}
+}
+
+// openTestDB abre um banco SQLite temporário com as migrações aplicadas
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	database, err := db.OpenSQLite(filepath.Join(t.TempDir(), "cookbook.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := migrations.New(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return database
+}
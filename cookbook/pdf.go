@@ -0,0 +1,231 @@
+package cookbook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"main.go/render"
+)
+
+const (
+	pdfFont       = "Helvetica"
+	pdfLineHeight = 6.0
+)
+
+// pdfLayout guarda as páginas onde começam as receitas e o índice, descobertas na
+// primeira passagem e usadas no sumário e no índice da segunda
+type pdfLayout struct {
+	recipePages []int
+	indexPage   int
+}
+
+// WritePDF gera o livro em PDF, com capa, sumário, uma seção por receita, índice de ingredientes
+// com números de página e imagens. Usa apenas as fontes padrão do PDF, sem arquivos externos.
+func WritePDF(w io.Writer, book *Book) error {
+	// A primeira passagem descobre em que página cada receita começa
+	_, layout, err := buildPDF(book, nil)
+	if err != nil {
+		return err
+	}
+
+	pdf, _, err := buildPDF(book, layout)
+	if err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+func buildPDF(book *Book, pages *pdfLayout) (*fpdf.Fpdf, *pdfLayout, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetTitle(book.Title, true)
+	if book.Author != "" {
+		pdf.SetAuthor(book.Author, true)
+	}
+	pdf.SetCreator("Cookbook API", true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - 40
+
+	for name, data := range book.Images {
+		pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "JPG"}, bytes.NewReader(data))
+	}
+	if err := pdf.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	// Número da página no rodapé, exceto na capa
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-15)
+		pdf.SetFont(pdfFont, "I", 9)
+		pdf.CellFormat(0, 10, strconv.Itoa(pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	layout := &pdfLayout{}
+	recipeLinks := make([]int, len(book.Recipes))
+	for i := range recipeLinks {
+		recipeLinks[i] = pdf.AddLink()
+	}
+	indexLink := pdf.AddLink()
+
+	// Capa
+	pdf.AddPage()
+	pdf.SetY(100)
+	pdf.SetFont(pdfFont, "B", 28)
+	pdf.MultiCell(0, 12, tr(book.Title), "", "C", false)
+	if book.Author != "" {
+		pdf.Ln(6)
+		pdf.SetFont(pdfFont, "I", 16)
+		pdf.MultiCell(0, 8, tr(book.Author), "", "C", false)
+	}
+
+	// Sumário
+	pdf.AddPage()
+	pdf.Bookmark(tr("Sumário"), 0, -1)
+	pdf.SetFont(pdfFont, "B", 20)
+	pdf.CellFormat(0, 12, tr("Sumário"), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+	pdf.SetFont(pdfFont, "", 12)
+	for i, recipe := range book.Recipes {
+		tocLine(pdf, tr(recipe.Name), pageNumber(pages, i), recipeLinks[i])
+	}
+	tocLine(pdf, tr("Índice de ingredientes"), pageNumber(pages, -1), indexLink)
+
+	// Receitas
+	for i, recipe := range book.Recipes {
+		pdf.AddPage()
+		layout.recipePages = append(layout.recipePages, pdf.PageNo())
+		pdf.SetLink(recipeLinks[i], -1, -1)
+		pdf.Bookmark(tr(recipe.Name), 0, -1)
+		writePDFRecipe(pdf, tr, contentWidth, recipe)
+	}
+
+	// Índice de ingredientes
+	pdf.AddPage()
+	layout.indexPage = pdf.PageNo()
+	pdf.SetLink(indexLink, -1, -1)
+	pdf.Bookmark(tr("Índice de ingredientes"), 0, -1)
+	pdf.SetFont(pdfFont, "B", 20)
+	pdf.CellFormat(0, 12, tr("Índice de ingredientes"), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+	for _, entry := range book.Index() {
+		pdf.SetFont(pdfFont, "B", 11)
+		pdf.CellFormat(0, pdfLineHeight, tr(entry.Ingredient), "", 1, "L", false, 0, "")
+		pdf.SetFont(pdfFont, "", 11)
+		for _, i := range entry.Recipes {
+			pdf.SetX(28)
+			tocLine(pdf, tr(book.Recipes[i].Name), pageNumber(pages, i), recipeLinks[i])
+		}
+	}
+
+	return pdf, layout, pdf.Error()
+}
+
+func writePDFRecipe(pdf *fpdf.Fpdf, tr func(string) string, width float64, recipe render.RecipeView) {
+	pdf.SetFont(pdfFont, "B", 20)
+	pdf.MultiCell(0, 10, tr(recipe.Name), "", "L", false)
+
+	if recipe.Author != "" {
+		pdf.SetFont(pdfFont, "I", 11)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(0, pdfLineHeight, tr("por "+recipe.Author), "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.Ln(2)
+
+	for _, name := range recipe.Images {
+		writePDFImage(pdf, name, width, 80)
+	}
+
+	var meta []string
+	if recipe.Servings > 0 {
+		meta = append(meta, fmt.Sprintf("Rendimento: %d porções", recipe.Servings))
+	}
+	if recipe.PrepTime > 0 {
+		meta = append(meta, "Preparo: "+render.Minutes(recipe.PrepTime))
+	}
+	if recipe.CookTime > 0 {
+		meta = append(meta, "Cozimento: "+render.Minutes(recipe.CookTime))
+	}
+	if recipe.TotalTime > 0 {
+		meta = append(meta, "Tempo total: "+render.Minutes(recipe.TotalTime))
+	}
+	if len(meta) > 0 {
+		pdf.SetFont(pdfFont, "", 10)
+		pdf.MultiCell(0, pdfLineHeight, tr(strings.Join(meta, "   |   ")), "TB", "L", false)
+		pdf.Ln(2)
+	}
+
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.CellFormat(0, 10, "Ingredientes", "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFont, "", 11)
+	for _, ingredient := range recipe.Ingredients {
+		if ingredient.Heading != "" {
+			pdf.SetFont(pdfFont, "B", 11)
+			pdf.MultiCell(0, pdfLineHeight, tr(ingredient.Heading), "", "L", false)
+			pdf.SetFont(pdfFont, "", 11)
+		}
+		pdf.MultiCell(0, pdfLineHeight, tr("•  "+ingredient.Text()), "", "L", false)
+	}
+	pdf.Ln(2)
+
+	pdf.SetFont(pdfFont, "B", 14)
+	pdf.CellFormat(0, 10, "Modo de preparo", "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFont, "", 11)
+	for _, step := range recipe.Steps {
+		pdf.MultiCell(0, pdfLineHeight, tr(fmt.Sprintf("%d. %s", step.Number, step.Text)), "", "L", false)
+		for _, name := range step.Images {
+			writePDFImage(pdf, name, width/2, 50)
+		}
+		pdf.Ln(1)
+	}
+}
+
+// writePDFImage insere a imagem no fluxo do texto, limitada à largura e altura máximas informadas
+func writePDFImage(pdf *fpdf.Fpdf, name string, maxWidth float64, maxHeight float64) {
+	info := pdf.GetImageInfo(name)
+	if info == nil {
+		return
+	}
+
+	width, height := maxWidth, maxWidth*info.Height()/info.Width()
+	if height > maxHeight {
+		width, height = maxHeight*info.Width()/info.Height(), maxHeight
+	}
+
+	pdf.ImageOptions(name, -1, -1, width, height, true, fpdf.ImageOptions{ImageType: "JPG"}, 0, "")
+	pdf.Ln(2)
+}
+
+// tocLine escreve uma linha do sumário ou do índice: o texto com link e o número da página à direita
+func tocLine(pdf *fpdf.Fpdf, text string, page string, link int) {
+	pageWidth, _ := pdf.GetPageSize()
+	_, _, rightMargin, _ := pdf.GetMargins()
+	numberWidth := pdf.GetStringWidth("0000")
+	textWidth := pageWidth - rightMargin - pdf.GetX() - numberWidth
+
+	pdf.CellFormat(textWidth, pdfLineHeight, text, "", 0, "L", false, link, "")
+	pdf.CellFormat(numberWidth, pdfLineHeight, page, "", 1, "R", false, link, "")
+}
+
+// pageNumber retorna o número da página da receita i (ou do índice, para i = -1) descoberto na
+// primeira passagem; na primeira passagem o número ainda não é conhecido
+func pageNumber(pages *pdfLayout, i int) string {
+	if pages == nil {
+		return ""
+	}
+	if i < 0 {
+		return strconv.Itoa(pages.indexPage)
+	}
+	return strconv.Itoa(pages.recipePages[i])
+}
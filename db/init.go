@@ -2,43 +2,40 @@ package db
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"main.go/models"
+	"main.go/config"
 )
 
-func InitDB() *gorm.DB {
-	err := godotenv.Load(".env")
+// InitDB conecta ao banco da configuração: o Postgres ou o SQLite, com o arquivo informado em
+// DATABASE (":memory:" para um banco em memória). O esquema é criado e atualizado pelas migrações
+// em db/migrations.
+func InitDB(cfg config.Database) *gorm.DB {
+	var db *gorm.DB
+	var err error
+	switch cfg.Driver {
+	case "sqlite":
+		db, err = OpenSQLite(cfg.Name)
+	default:
+		db, err = openPostgres(cfg)
+	}
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		slog.Error("Failed to connect database", "error", err)
+		os.Exit(1)
 	}
 
-	// Pega informações para acesso ao banco, guardadas em arquivo .env
-	host := os.Getenv("HOST")
-	user := os.Getenv("USER")
-	password := os.Getenv("PASSWORD")
-	dbname := os.Getenv("DATABASE")
-	port := os.Getenv("PORT")
-	sslmode := os.Getenv("SSLMODE")
-	timezone := os.Getenv("TIMEZONE")
+	return db
+}
+
+// Funções privadas
 
+func openPostgres(cfg config.Database) (*gorm.DB, error) {
 	// Gera String no formato aceito para acesso ao Postgres
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s", host, user, password, dbname, port, sslmode, timezone)
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s", cfg.Host, cfg.User, cfg.Password, cfg.Name, cfg.Port, cfg.SSLMode, cfg.TimeZone)
 
 	// Conecta com o banco de dados
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatalf("Failed to connect database: %v", err)
-	}
-
-	err = db.AutoMigrate(&models.User{}, &models.Ingredient{}, &models.Recipe{}, &models.IngredientsRecipes{})
-	if err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
-	}
-
-	return db
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: Logger})
 }
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"main.go/logging"
+)
+
+// slowQueryThreshold é a duração a partir da qual uma consulta é registrada como lenta
+const slowQueryThreshold = 200 * time.Millisecond
+
+// logger envia as mensagens do GORM para o logger do contexto da consulta, que traz o ID da
+// requisição quando a consulta usa WithContext. As consultas são registradas sem os valores dos
+// parâmetros (ex.: senhas), apenas com os marcadores "?".
+type logger struct{}
+
+// Logger é o logger do GORM usado nas conexões abertas por InitDB e OpenSQLite.
+var Logger gormlogger.Interface = logger{}
+
+// LogMode é ignorado: o nível é o do logger da aplicação (as consultas são registradas no nível debug,
+// as lentas como aviso e as que falham como erro).
+func (l logger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (logger) Info(ctx context.Context, msg string, args ...any) {
+	logging.FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (logger) Warn(ctx context.Context, msg string, args ...any) {
+	logging.FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (logger) Error(ctx context.Context, msg string, args ...any) {
+	logging.FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (logger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	log := logging.FromContext(ctx)
+	elapsed := time.Since(begin)
+
+	level := slog.LevelDebug
+	msg := "Query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, context.Canceled):
+		level, msg = slog.LevelError, "Query failed"
+	case elapsed > slowQueryThreshold:
+		level, msg = slog.LevelWarn, "Slow query"
+	}
+	if !log.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	log.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// ParamsFilter retira os valores dos parâmetros da consulta registrada
+func (logger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	return sql, nil
+}
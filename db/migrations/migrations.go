@@ -0,0 +1,408 @@
+// Package migrations aplica as migrações versionadas do banco, escritas em SQL e embutidas no
+// binário. Cada migração tem um arquivo NNNN_nome.up.sql e um NNNN_nome.down.sql, em um diretório
+// por banco (postgres e sqlite) com as mesmas versões; as aplicadas são registradas na tabela
+// schema_migrations com o checksum do arquivo up.
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/catalog"
+	"main.go/models"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// lockKey identifica o advisory lock do Postgres que impede duas execuções simultâneas
+const lockKey = 4172635001
+
+// Estados de uma migração em Status
+const (
+	StatusApplied  = "applied"
+	StatusPending  = "pending"
+	StatusModified = "modified" // aplicada, mas o arquivo mudou depois
+	StatusMissing  = "missing"  // aplicada, mas o arquivo não existe mais
+)
+
+var fileNameRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// ErrModified indica uma migração alterada depois de aplicada, o que impede novas execuções
+var ErrModified = errors.New("migration was modified after being applied")
+
+// Migration é uma migração lida dos arquivos embutidos
+type Migration struct {
+	Version  uint
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Record é a linha da tabela schema_migrations de uma migração aplicada
+type Record struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (Record) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus é a situação de uma migração, conhecida pelos arquivos ou pela tabela
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Status    string
+	AppliedAt *time.Time
+}
+
+// Migrator aplica e desfaz as migrações em um banco
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New cria o Migrator com as migrações embutidas no binário para o banco da conexão
+func New(db *gorm.DB) (*Migrator, error) {
+	dialect := db.Dialector.Name()
+	if _, err := fs.Stat(files, dialect); err != nil {
+		return nil, fmt.Errorf("no migrations for database %q", dialect)
+	}
+
+	fsys, err := fs.Sub(files, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db, fsys)
+}
+
+// Up aplica as migrações pendentes, em ordem, cada uma em sua própria transação, e retorna as
+// aplicadas. Bancos criados pelo AutoMigrate, antes das migrações versionadas, são marcados com a
+// migração inicial sem executá-la.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.locked(ctx, func(db *gorm.DB) error {
+		if err := baselineLegacySchema(db, m.migrations[0]); err != nil {
+			return fmt.Errorf("baseline legacy schema: %w", err)
+		}
+
+		pending, err := m.pending(db)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pending {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&Record{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Checksum:  migration.Checksum,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down desfaz as últimas migrações aplicadas, da mais recente para a mais antiga, e retorna as
+// desfeitas
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.locked(ctx, func(db *gorm.DB) error {
+		records, err := appliedRecords(db)
+		if err != nil {
+			return err
+		}
+
+		known := m.byVersion()
+		for i := len(records) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration, ok := known[records[i].Version]
+			if !ok {
+				return fmt.Errorf("migration %04d_%s: file not found", records[i].Version, records[i].Name)
+			}
+			if migration.Checksum != records[i].Checksum {
+				return fmt.Errorf("migration %s: %w", migration, ErrModified)
+			}
+
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&Record{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s: %w", migration, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lista as migrações conhecidas e as registradas no banco, em ordem de versão
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	db := m.db.WithContext(ctx)
+
+	records, err := appliedRecords(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, Status: StatusPending}
+		if record, ok := applied[migration.Version]; ok {
+			status.Status = StatusApplied
+			if record.Checksum != migration.Checksum {
+				status.Status = StatusModified
+			}
+			status.AppliedAt = &record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range applied {
+		statuses = append(statuses, MigrationStatus{Version: record.Version, Name: record.Name, Status: StatusMissing, AppliedAt: &record.AppliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// Pending lista as migrações ainda não aplicadas
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	return m.pending(m.db.WithContext(ctx))
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Funções privadas
+
+func newMigrator(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if len(migrations) == 0 {
+		return nil, errors.New("no migrations found")
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// load lê as migrações dos arquivos, exigindo os arquivos up e down de cada versão
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := fileNameRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("%s: invalid version", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := found[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			found[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("%s: version %d is already used by %q", entry.Name(), version, migration.Name)
+		}
+
+		if match[3] == "up" {
+			sum := sha256.Sum256(content)
+			migration.Up = string(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(found))
+	for _, migration := range found {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s: both up and down files are required", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// locked executa fn em uma única conexão, criando antes a tabela schema_migrations. No Postgres, a
+// conexão segura um advisory lock durante a execução, e outras instâncias aguardam a liberação.
+func (m *Migrator) locked(ctx context.Context, fn func(db *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		// A instância recebida não é uma nova sessão: sem ela, cada consulta herdaria as condições
+		// das anteriores
+		db := conn.Session(&gorm.Session{})
+
+		if db.Dialector.Name() == "postgres" {
+			if err := db.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+				return fmt.Errorf("acquire migration lock: %w", err)
+			}
+			defer db.Exec("SELECT pg_advisory_unlock(?)", lockKey)
+		}
+
+		if err := createTable(db); err != nil {
+			return err
+		}
+		return fn(db)
+	})
+}
+
+func (m *Migrator) pending(db *gorm.DB) ([]Migration, error) {
+	records, err := appliedRecords(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if !ok {
+			pending = append(pending, migration)
+			continue
+		}
+		if record.Checksum != migration.Checksum {
+			return nil, fmt.Errorf("migration %s: %w", migration, ErrModified)
+		}
+	}
+
+	return pending, nil
+}
+
+// appliedRecords lista as migrações aplicadas, em ordem de versão
+func appliedRecords(db *gorm.DB) ([]Record, error) {
+	if !db.Migrator().HasTable(&Record{}) {
+		return nil, nil
+	}
+
+	var records []Record
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func createTable(db *gorm.DB) error {
+	// O driver do SQLite só converte para data as colunas declaradas como datetime
+	timeType := "timestamptz"
+	if db.Dialector.Name() != "postgres" {
+		timeType = "datetime"
+	}
+
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		checksum text NOT NULL,
+		applied_at ` + timeType + ` NOT NULL
+	)`).Error
+}
+
+func (m *Migrator) byVersion() map[uint]Migration {
+	known := make(map[uint]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	return known
+}
+
+// baselineLegacySchema atualiza, pelo AutoMigrate, os bancos criados antes das migrações
+// versionadas (com tabelas, mas sem migrações registradas) até o esquema da migração inicial, que é
+// então marcada como aplicada
+func baselineLegacySchema(db *gorm.DB, initial Migration) error {
+	var count int64
+	if err := db.Model(&Record{}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	migrator := db.Migrator()
+	if count > 0 || !migrator.HasTable(&models.User{}) {
+		return nil
+	}
+
+	// Receitas anteriores aos slugs: a coluna é criada e preenchida antes do índice único por autor
+	if migrator.HasTable(&models.Recipe{}) && !migrator.HasColumn(&models.Recipe{}, "Slug") {
+		if err := migrator.AddColumn(&models.Recipe{}, "Slug"); err != nil {
+			return err
+		}
+		if err := migrator.AutoMigrate(&models.RecipeSlugRedirect{}); err != nil {
+			return err
+		}
+		if err := catalog.BackfillSlugs(db); err != nil {
+			return err
+		}
+	}
+
+	err := db.AutoMigrate(&models.User{}, &models.Ingredient{}, &models.Recipe{}, &models.IngredientsRecipes{}, &models.RecipeImage{}, &models.RecipeImageThumbnail{}, &models.CookbookExport{}, &models.RecipeRevision{}, &models.RecipeSlugRedirect{}, &models.IngredientAlias{}, &models.IngredientTranslation{}, &models.Substitution{}, &models.SubstitutionItem{})
+	if err != nil {
+		return err
+	}
+
+	return db.Create(&Record{
+		Version:   initial.Version,
+		Name:      initial.Name,
+		Checksum:  initial.Checksum,
+		AppliedAt: time.Now(),
+	}).Error
+}
@@ -0,0 +1,177 @@
+-- Esquema criado pelo AutoMigrate até a adoção das migrações versionadas. Bancos criados antes
+-- delas são marcados com esta versão sem executá-la (ver migrations.baselineLegacySchema).
+
+CREATE TABLE "users" (
+    "id" bigserial,
+    "username" text NOT NULL,
+    "email" text NOT NULL,
+    "password" text NOT NULL,
+    "role" text NOT NULL DEFAULT 'user',
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_users_username" UNIQUE ("username"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+CREATE INDEX "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE "ingredients" (
+    "id" bigserial,
+    "name" text NOT NULL,
+    "deleted_at" timestamptz,
+    "deleted_by" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_ingredients_name" UNIQUE ("name")
+);
+CREATE INDEX "idx_ingredients_deleted_at" ON "ingredients" ("deleted_at");
+
+CREATE TABLE "recipes" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    "slug" text NOT NULL DEFAULT '',
+    "instructions" text NOT NULL,
+    "servings" bigint,
+    "prep_time" bigint,
+    "cook_time" bigint,
+    "total_time" bigint,
+    "visibility" text NOT NULL DEFAULT 'published',
+    "publish_at" timestamptz,
+    "deleted_at" timestamptz,
+    "deleted_by" bigint NOT NULL DEFAULT 0,
+    "parent_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_recipes_forks" FOREIGN KEY ("parent_id") REFERENCES "recipes" ("id") ON DELETE SET NULL
+);
+CREATE INDEX "idx_recipes_publish_at" ON "recipes" ("publish_at");
+CREATE INDEX "idx_recipes_visibility" ON "recipes" ("visibility");
+CREATE UNIQUE INDEX "idx_recipe_user_slug" ON "recipes" ("user_id", "slug");
+CREATE UNIQUE INDEX "idx_recipe_user_name" ON "recipes" ("user_id", "name");
+CREATE INDEX "idx_recipes_parent_id" ON "recipes" ("parent_id");
+CREATE INDEX "idx_recipes_deleted_at" ON "recipes" ("deleted_at");
+
+CREATE TABLE "ingredients_recipes" (
+    "recipe_id" bigint,
+    "ingredient_id" bigint,
+    "quantity" text NOT NULL,
+    "position" bigint NOT NULL DEFAULT 0,
+    "group_name" text,
+    "note" text,
+    "optional" boolean NOT NULL DEFAULT false,
+    "to_taste" boolean NOT NULL DEFAULT false,
+    PRIMARY KEY ("recipe_id", "ingredient_id"),
+    CONSTRAINT "fk_ingredients_recipes_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_recipes_ingredients_recipes" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE "recipe_images" (
+    "id" bigserial,
+    "recipe_id" bigint NOT NULL,
+    "step" bigint,
+    "key" text NOT NULL,
+    "content_type" text NOT NULL,
+    "size" bigint NOT NULL,
+    "width" bigint NOT NULL,
+    "height" bigint NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_recipes_images" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_recipe_images_recipe_id" ON "recipe_images" ("recipe_id");
+
+CREATE TABLE "recipe_image_thumbnails" (
+    "id" bigserial,
+    "image_id" bigint NOT NULL,
+    "size" bigint NOT NULL,
+    "key" text NOT NULL,
+    "width" bigint NOT NULL,
+    "height" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_recipe_images_thumbnails" FOREIGN KEY ("image_id") REFERENCES "recipe_images" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_recipe_image_thumbnails_image_id" ON "recipe_image_thumbnails" ("image_id");
+
+CREATE TABLE "cookbook_exports" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "title" text NOT NULL,
+    "author_id" bigint,
+    "recipe_ids" text,
+    "status" text NOT NULL,
+    "error" text,
+    "epub_key" text,
+    "pdf_key" text,
+    "created_at" timestamptz,
+    "finished_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_cookbook_exports_user_id" ON "cookbook_exports" ("user_id");
+CREATE INDEX "idx_cookbook_exports_status" ON "cookbook_exports" ("status");
+
+CREATE TABLE "recipe_revisions" (
+    "id" bigserial,
+    "recipe_id" bigint NOT NULL,
+    "number" bigint NOT NULL,
+    "author_id" bigint NOT NULL DEFAULT 0,
+    "summary" text NOT NULL,
+    "snapshot" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_recipes_revisions" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_recipe_revision" ON "recipe_revisions" ("recipe_id", "number");
+
+CREATE TABLE "recipe_slug_redirects" (
+    "id" bigserial,
+    "recipe_id" bigint NOT NULL,
+    "user_id" bigint NOT NULL,
+    "slug" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_recipes_slug_redirects" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_recipe_slug_redirect" ON "recipe_slug_redirects" ("user_id", "slug");
+CREATE INDEX "idx_recipe_slug_redirects_recipe_id" ON "recipe_slug_redirects" ("recipe_id");
+
+CREATE TABLE "ingredient_aliases" (
+    "id" bigserial,
+    "ingredient_id" bigint NOT NULL,
+    "name" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_ingredients_aliases" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_ingredient_aliases_name" ON "ingredient_aliases" ("name");
+CREATE INDEX "idx_ingredient_aliases_ingredient_id" ON "ingredient_aliases" ("ingredient_id");
+
+CREATE TABLE "ingredient_translations" (
+    "id" bigserial,
+    "ingredient_id" bigint NOT NULL,
+    "locale" text NOT NULL,
+    "name" text NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_ingredients_translations" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_ingredient_translation" ON "ingredient_translations" ("ingredient_id", "locale");
+
+CREATE TABLE "substitutions" (
+    "id" bigserial,
+    "ingredient_id" bigint NOT NULL,
+    "reason" text NOT NULL DEFAULT '',
+    "notes" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_substitutions_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_substitutions_ingredient_id" ON "substitutions" ("ingredient_id");
+CREATE INDEX "idx_substitutions_reason" ON "substitutions" ("reason");
+
+CREATE TABLE "substitution_items" (
+    "id" bigserial,
+    "substitution_id" bigint NOT NULL,
+    "ingredient_id" bigint NOT NULL,
+    "ratio" decimal NOT NULL DEFAULT 1,
+    "note" text,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_substitution_items_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_substitutions_replacements" FOREIGN KEY ("substitution_id") REFERENCES "substitutions" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_substitution_items_ingredient_id" ON "substitution_items" ("ingredient_id");
+CREATE INDEX "idx_substitution_items_substitution_id" ON "substitution_items" ("substitution_id");
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "idx_ingredients_recipes_ingredient_id";
+DROP INDEX IF EXISTS "idx_ingredient_translations_lower_name";
+DROP INDEX IF EXISTS "idx_ingredient_aliases_lower_name";
+DROP INDEX IF EXISTS "idx_ingredients_lower_name";
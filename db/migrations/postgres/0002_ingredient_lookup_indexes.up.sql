@@ -0,0 +1,8 @@
+-- Buscas de ingredientes sem diferenciar maiúsculas e minúsculas (catalog.FindIngredient) e pelas
+-- receitas que usam um ingrediente (remoção, unificação e substituições). Os nomes alternativos
+-- passam a ser únicos também sem diferenciar maiúsculas e minúsculas.
+
+CREATE INDEX "idx_ingredients_lower_name" ON "ingredients" (LOWER("name"));
+CREATE UNIQUE INDEX "idx_ingredient_aliases_lower_name" ON "ingredient_aliases" (LOWER("name"));
+CREATE INDEX "idx_ingredient_translations_lower_name" ON "ingredient_translations" (LOWER("name"));
+CREATE INDEX "idx_ingredients_recipes_ingredient_id" ON "ingredients_recipes" ("ingredient_id");
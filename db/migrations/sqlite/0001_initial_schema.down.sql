@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS "substitution_items";
+DROP TABLE IF EXISTS "substitutions";
+DROP TABLE IF EXISTS "ingredient_translations";
+DROP TABLE IF EXISTS "ingredient_aliases";
+DROP TABLE IF EXISTS "recipe_slug_redirects";
+DROP TABLE IF EXISTS "recipe_revisions";
+DROP TABLE IF EXISTS "cookbook_exports";
+DROP TABLE IF EXISTS "recipe_image_thumbnails";
+DROP TABLE IF EXISTS "recipe_images";
+DROP TABLE IF EXISTS "ingredients_recipes";
+DROP TABLE IF EXISTS "recipes";
+DROP TABLE IF EXISTS "ingredients";
+DROP TABLE IF EXISTS "users";
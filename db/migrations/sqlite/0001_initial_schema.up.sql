@@ -0,0 +1,165 @@
+-- Versão para o SQLite de postgres/0001_initial_schema.up.sql, com os tipos que o AutoMigrate usa
+-- nesse banco (integer, datetime, numeric e real).
+
+CREATE TABLE "users" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "username" text NOT NULL,
+    "email" text NOT NULL,
+    "password" text NOT NULL,
+    "role" text NOT NULL DEFAULT 'user',
+    "deleted_at" datetime,
+    CONSTRAINT "uni_users_username" UNIQUE ("username"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+CREATE INDEX "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE "ingredients" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "name" text NOT NULL,
+    "deleted_at" datetime,
+    "deleted_by" integer NOT NULL DEFAULT 0,
+    CONSTRAINT "uni_ingredients_name" UNIQUE ("name")
+);
+CREATE INDEX "idx_ingredients_deleted_at" ON "ingredients" ("deleted_at");
+
+CREATE TABLE "recipes" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "user_id" integer NOT NULL,
+    "name" text NOT NULL,
+    "slug" text NOT NULL DEFAULT '',
+    "instructions" text NOT NULL,
+    "servings" integer,
+    "prep_time" integer,
+    "cook_time" integer,
+    "total_time" integer,
+    "visibility" text NOT NULL DEFAULT 'published',
+    "publish_at" datetime,
+    "deleted_at" datetime,
+    "deleted_by" integer NOT NULL DEFAULT 0,
+    "parent_id" integer,
+    CONSTRAINT "fk_recipes_forks" FOREIGN KEY ("parent_id") REFERENCES "recipes" ("id") ON DELETE SET NULL
+);
+CREATE INDEX "idx_recipes_publish_at" ON "recipes" ("publish_at");
+CREATE INDEX "idx_recipes_visibility" ON "recipes" ("visibility");
+CREATE UNIQUE INDEX "idx_recipe_user_slug" ON "recipes" ("user_id", "slug");
+CREATE UNIQUE INDEX "idx_recipe_user_name" ON "recipes" ("user_id", "name");
+CREATE INDEX "idx_recipes_parent_id" ON "recipes" ("parent_id");
+CREATE INDEX "idx_recipes_deleted_at" ON "recipes" ("deleted_at");
+
+CREATE TABLE "ingredients_recipes" (
+    "recipe_id" integer,
+    "ingredient_id" integer,
+    "quantity" text NOT NULL,
+    "position" integer NOT NULL DEFAULT 0,
+    "group_name" text,
+    "note" text,
+    "optional" numeric NOT NULL DEFAULT false,
+    "to_taste" numeric NOT NULL DEFAULT false,
+    PRIMARY KEY ("recipe_id", "ingredient_id"),
+    CONSTRAINT "fk_ingredients_recipes_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_recipes_ingredients_recipes" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE "recipe_images" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "recipe_id" integer NOT NULL,
+    "step" integer,
+    "key" text NOT NULL,
+    "content_type" text NOT NULL,
+    "size" integer NOT NULL,
+    "width" integer NOT NULL,
+    "height" integer NOT NULL,
+    "created_at" datetime,
+    CONSTRAINT "fk_recipes_images" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_recipe_images_recipe_id" ON "recipe_images" ("recipe_id");
+
+CREATE TABLE "recipe_image_thumbnails" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "image_id" integer NOT NULL,
+    "size" integer NOT NULL,
+    "key" text NOT NULL,
+    "width" integer NOT NULL,
+    "height" integer NOT NULL,
+    CONSTRAINT "fk_recipe_images_thumbnails" FOREIGN KEY ("image_id") REFERENCES "recipe_images" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_recipe_image_thumbnails_image_id" ON "recipe_image_thumbnails" ("image_id");
+
+CREATE TABLE "cookbook_exports" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "user_id" integer NOT NULL,
+    "title" text NOT NULL,
+    "author_id" integer,
+    "recipe_ids" text,
+    "status" text NOT NULL,
+    "error" text,
+    "epub_key" text,
+    "pdf_key" text,
+    "created_at" datetime,
+    "finished_at" datetime
+);
+CREATE INDEX "idx_cookbook_exports_user_id" ON "cookbook_exports" ("user_id");
+CREATE INDEX "idx_cookbook_exports_status" ON "cookbook_exports" ("status");
+
+CREATE TABLE "recipe_revisions" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "recipe_id" integer NOT NULL,
+    "number" integer NOT NULL,
+    "author_id" integer NOT NULL DEFAULT 0,
+    "summary" text NOT NULL,
+    "snapshot" text NOT NULL,
+    "created_at" datetime,
+    CONSTRAINT "fk_recipes_revisions" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_recipe_revision" ON "recipe_revisions" ("recipe_id", "number");
+
+CREATE TABLE "recipe_slug_redirects" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "recipe_id" integer NOT NULL,
+    "user_id" integer NOT NULL,
+    "slug" text NOT NULL,
+    "created_at" datetime,
+    CONSTRAINT "fk_recipes_slug_redirects" FOREIGN KEY ("recipe_id") REFERENCES "recipes" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_recipe_slug_redirect" ON "recipe_slug_redirects" ("user_id", "slug");
+CREATE INDEX "idx_recipe_slug_redirects_recipe_id" ON "recipe_slug_redirects" ("recipe_id");
+
+CREATE TABLE "ingredient_aliases" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "ingredient_id" integer NOT NULL,
+    "name" text NOT NULL,
+    CONSTRAINT "fk_ingredients_aliases" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_ingredient_aliases_name" ON "ingredient_aliases" ("name");
+CREATE INDEX "idx_ingredient_aliases_ingredient_id" ON "ingredient_aliases" ("ingredient_id");
+
+CREATE TABLE "ingredient_translations" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "ingredient_id" integer NOT NULL,
+    "locale" text NOT NULL,
+    "name" text NOT NULL,
+    CONSTRAINT "fk_ingredients_translations" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_ingredient_translation" ON "ingredient_translations" ("ingredient_id", "locale");
+
+CREATE TABLE "substitutions" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "ingredient_id" integer NOT NULL,
+    "reason" text NOT NULL DEFAULT '',
+    "notes" text,
+    CONSTRAINT "fk_substitutions_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_substitutions_ingredient_id" ON "substitutions" ("ingredient_id");
+CREATE INDEX "idx_substitutions_reason" ON "substitutions" ("reason");
+
+CREATE TABLE "substitution_items" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "substitution_id" integer NOT NULL,
+    "ingredient_id" integer NOT NULL,
+    "ratio" real NOT NULL DEFAULT 1,
+    "note" text,
+    CONSTRAINT "fk_substitution_items_ingredient" FOREIGN KEY ("ingredient_id") REFERENCES "ingredients" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_substitutions_replacements" FOREIGN KEY ("substitution_id") REFERENCES "substitutions" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_substitution_items_ingredient_id" ON "substitution_items" ("ingredient_id");
+CREATE INDEX "idx_substitution_items_substitution_id" ON "substitution_items" ("substitution_id");
@@ -0,0 +1,7 @@
+-- Mesmos índices de postgres/0002_ingredient_lookup_indexes.up.sql. O LOWER é a versão registrada
+-- em db.OpenSQLite, que converte também as letras fora do ASCII.
+
+CREATE INDEX "idx_ingredients_lower_name" ON "ingredients" (LOWER("name"));
+CREATE UNIQUE INDEX "idx_ingredient_aliases_lower_name" ON "ingredient_aliases" (LOWER("name"));
+CREATE INDEX "idx_ingredient_translations_lower_name" ON "ingredient_translations" (LOWER("name"));
+CREATE INDEX "idx_ingredients_recipes_ingredient_id" ON "ingredients_recipes" ("ingredient_id");
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	sqlitedriver "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// memoryDatabase é o nome que abre o SQLite em memória
+const memoryDatabase = ":memory:"
+
+func init() {
+	// O LOWER do SQLite só converte letras ASCII; a versão registrada aqui segue a do Postgres, para
+	// que as buscas sem diferenciar maiúsculas e minúsculas (ex.: "ÁGUA" e "água") funcionem nos dois
+	sqlitedriver.MustRegisterDeterministicScalarFunction("lower", 1, lower)
+}
+
+// OpenSQLite abre o banco SQLite do arquivo, criando-o se não existir. As chaves estrangeiras são
+// ativadas em cada conexão, e as datas gravadas pelo GORM ficam em UTC, já que o SQLite as guarda
+// como texto e as compara como tal.
+func OpenSQLite(path string) (*gorm.DB, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing SQLite database file (use %s for an in-memory database)", memoryDatabase)
+	}
+
+	// As transações começam com o bloqueio de escrita (BEGIN IMMEDIATE) e esperam, em vez de falhar,
+	// quando outra estiver escrevendo: como o SQLite não bloqueia linhas, é assim que as alterações
+	// simultâneas da mesma receita são executadas uma de cada vez
+	pragmas := []string{"_pragma=foreign_keys(1)", "_pragma=busy_timeout(5000)", "_txlock=immediate"}
+	if path != memoryDatabase {
+		pragmas = append(pragmas, "_pragma=journal_mode(WAL)")
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	dsn := path + separator + strings.Join(pragmas, "&")
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		Logger:  Logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Cada conexão com ":memory:" teria o seu próprio banco; uma única conexão, mantida aberta,
+	// garante que todas as consultas vejam os mesmos dados
+	if path == memoryDatabase {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+	}
+
+	return db, nil
+}
+
+// Funções privadas
+
+func lower(ctx *sqlitedriver.FunctionContext, args []driver.Value) (driver.Value, error) {
+	switch value := args[0].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.ToLower(value), nil
+	case []byte:
+		return strings.ToLower(string(value)), nil
+	default:
+		return strings.ToLower(fmt.Sprint(value)), nil
+	}
+}
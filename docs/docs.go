@@ -22,221 +22,322 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/ingredient": {
+        "/admin/catalog/{kind}/export": {
             "get": {
-                "description": "Buscar todos os ingredientes cadastrados",
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Exportar todos os ingredientes ou receitas (com seus ingredientes) em CSV ou NDJSON, no mesmo formato aceito pela importação",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "ingredient"
+                    "admin"
+                ],
+                "summary": "Exportar catálogo",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ingredients",
+                            "recipes"
+                        ],
+                        "type": "string",
+                        "description": "Tipo de registro",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Formato do arquivo",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Buscar todos os ingredientes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Ingredient"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Invalid kind",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/catalog/{kind}/import": {
             "post": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Criar novo ingrediente",
+                "description": "Importar ingredientes ou receitas (com seus ingredientes) em lote, a partir de um arquivo CSV ou NDJSON. Registros com o mesmo nome de um existente o atualizam (receitas, entre as do mesmo autor). Todo o arquivo é gravado em uma única transação: se algum registro for rejeitado nada é gravado, e o relatório traz o erro de cada linha. Em CSV de receitas, cada linha traz um ingrediente (colunas name, user, instructions, servings, prep_time, cook_time, total_time, visibility, ingredient, quantity). Receitas novas sem visibility são publicadas.",
                 "consumes": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "ingredient"
+                    "admin"
                 ],
-                "summary": "Criar novo ingrediente",
+                "summary": "Importar catálogo",
                 "parameters": [
                     {
-                        "description": "Novo ingrediente",
-                        "name": "ingredient",
+                        "enum": [
+                            "ingredients",
+                            "recipes"
+                        ],
+                        "type": "string",
+                        "description": "Tipo de registro",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Formato do arquivo (padrão pelo Content-Type)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas validar, sem gravar",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Conteúdo do arquivo",
+                        "name": "file",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Ingredient"
+                            "type": "string"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Ingredient created!",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/catalog.Report"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON"
-                    }
-                }
-            }
-        },
-        "/ingredient/name/{name}": {
-            "get": {
-                "description": "Buscar ingrediente pelo nome sem case sensitive e convertendo '-' para espaços",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "ingredient"
-                ],
-                "summary": "Buscar ingrediente pelo nome",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Nome do ingrediente",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                        "description": "Invalid file",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Ingredient"
-                            }
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Invalid kind",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "File is too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/catalog.Report"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/ingredient/{id}": {
-            "get": {
-                "description": "Buscar ingrediente pelo ID",
+        "/admin/ingredients/merge": {
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Unificar ingredientes duplicados (ex.: \"Açúcar\" e \"acucar refinado\") a um ingrediente canônico, em uma única transação: as receitas passam a usar o canônico, ganhando uma revisão, os nomes dos duplicados viram nomes alternativos dele e os duplicados são removidos. Se a receita já usa o canônico, a linha dele prevalece.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "ingredient"
+                    "admin"
                 ],
-                "summary": "Buscar ingrediente pelo ID",
+                "summary": "Unificar ingredientes duplicados",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID do ingrediente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Ingrediente canônico e duplicados",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IngredientMergeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Ingredient"
-                            }
+                            "$ref": "#/definitions/models.IngredientMergeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Ingredient not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/trash/purge": {
+            "post": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Atualizar ingrediente pelo ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remover definitivamente as receitas (com suas imagens), ingredientes e usuários que estão na lixeira há mais de older_than_days dias. Sem o parâmetro, usa o tempo de retenção configurado; com 0, esvazia toda a lixeira.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "ingredient"
+                    "admin"
                 ],
-                "summary": "Atualizar ingrediente",
+                "summary": "Esvaziar lixeira",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID do ingrediente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Ingrediente atualizado",
-                        "name": "ingredient",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Ingredient"
-                        }
+                        "description": "Idade mínima, em dias, dos itens removidos (padrão: o tempo de retenção)",
+                        "name": "older_than_days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Ingredient updated!",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.TrashPurgeReport"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON"
+                        "description": "Invalid older_than_days",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/users/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Deletar ingrediente pelo ID",
+                "description": "Restaurar da lixeira um usuário, que volta a conseguir entrar no sistema, junto com as receitas que foram para a lixeira com ele",
                 "produces": [
                     "text/plain"
                 ],
                 "tags": [
-                    "ingredient"
+                    "admin"
                 ],
-                "summary": "Deletar ingrediente",
+                "summary": "Restaurar usuário",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID do ingrediente",
+                        "description": "ID do usuário",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -244,106 +345,115 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Ingredient deleted!",
+                        "description": "User restored!",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "User not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/recipe": {
+        "/cookbook/export": {
             "post": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Criar nova receita",
+                "description": "Pedir a exportação assíncrona das receitas de um usuário (ou de uma coleção de receitas) como livro em EPUB e PDF, com sumário, índice de ingredientes e imagens. Acompanhe a situação pela rota retornada no cabeçalho Location.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "recipe"
+                    "cookbook"
                 ],
-                "summary": "Criar nova receita",
+                "summary": "Exportar livro de receitas",
                 "parameters": [
                     {
-                        "description": "Nova receita",
-                        "name": "recipe",
+                        "description": "Pedido de exportação",
+                        "name": "export",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "$ref": "#/definitions/models.CookbookExportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Recipe created!",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.CookbookExport"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON"
-                    }
-                }
-            }
-        },
-        "/recipe/": {
-            "get": {
-                "description": "Buscar todas as receitas cadastradas",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "recipe"
-                ],
-                "summary": "Buscar todas as receitas",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                        "description": "Invalid JSON",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Recipe"
-                            }
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "No recipes found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Export queue is full",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/recipe/name/{name}": {
+        "/cookbook/export/{id}": {
             "get": {
-                "description": "Buscar receita pelo nome sem case sensitive e convertendo '-' para espaços",
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar a situação de uma exportação de livro de receitas e, quando concluída, os endereços para baixar o EPUB e o PDF",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "recipe"
+                    "cookbook"
                 ],
-                "summary": "Buscar receita pelo nome",
+                "summary": "Situação da exportação",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Nome da receita",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "ID da exportação",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -352,38 +462,130 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Recipe"
-                            }
+                            "$ref": "#/definitions/models.CookbookExport"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/recipe/{id}": {
+        "/cookbook/export/{id}/download": {
             "get": {
-                "description": "Buscar receita pelo ID",
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Baixar o livro de receitas gerado, em EPUB ou PDF",
                 "produces": [
-                    "application/json"
+                    "application/epub+zip",
+                    "application/pdf"
                 ],
                 "tags": [
-                    "recipe"
+                    "cookbook"
                 ],
-                "summary": "Buscar receita pelo ID",
+                "summary": "Baixar livro de receitas",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID da receita",
+                        "description": "ID da exportação",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "epub",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "Formato do arquivo",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Export is not finished",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Responder enquanto o processo estiver no ar, sem verificar as dependências. Usado pelo orquestrador para reiniciar o processo travado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Processo no ar",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/ingredient": {
+            "get": {
+                "description": "Buscar todos os ingredientes cadastrados. Os nomes da resposta seguem o cabeçalho Accept-Language.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ingredient"
+                ],
+                "summary": "Buscar todos os ingredientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -392,25 +594,31 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Recipe"
+                                "$ref": "#/definitions/models.Ingredient"
                             }
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Atualizar receita pelo ID",
+                "description": "Criar novo ingrediente, opcionalmente com nomes alternativos e traduções",
                 "consumes": [
                     "application/json"
                 ],
@@ -418,92 +626,154 @@ const docTemplate = `{
                     "text/plain"
                 ],
                 "tags": [
-                    "recipe"
+                    "ingredient"
                 ],
-                "summary": "Atualizar receita",
+                "summary": "Criar novo ingrediente",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID da receita",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Receita atualizada",
-                        "name": "recipe",
+                        "description": "Novo ingrediente",
+                        "name": "ingredient",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Recipe"
+                            "$ref": "#/definitions/models.Ingredient"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Recipe updated!",
+                    "201": {
+                        "description": "Ingredient created!",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON"
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found"
+                    "409": {
+                        "description": "Ingredient already exists or data is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
-            },
-            "delete": {
-                "security": [
+            }
+        },
+        "/ingredient/name/{name}": {
+            "get": {
+                "description": "Buscar ingrediente pelo nome, pelos nomes alternativos (ex.: \"aipim\" encontra \"mandioca\") ou pelas traduções, sem case sensitive. Os nomes da resposta seguem o cabeçalho Accept-Language.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ingredient"
+                ],
+                "summary": "Buscar ingrediente pelo nome",
+                "parameters": [
                     {
-                        "Token": []
+                        "type": "string",
+                        "description": "Nome do ingrediente",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
-                "description": "Deletar receita pelo ID",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Ingredient"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/ingredient/{id}": {
+            "get": {
+                "description": "Buscar ingrediente pelo ID. O nome da resposta segue o cabeçalho Accept-Language.",
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "recipe"
+                    "ingredient"
                 ],
-                "summary": "Deletar receita",
+                "summary": "Buscar ingrediente pelo ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID da receita",
+                        "description": "ID do ingrediente",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma do nome (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Recipe deleted!",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Ingredient"
+                            }
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
-            }
-        },
-        "/recipe/{id}/ingredients": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Adicionar ingrediente cadastrado à uma receita criada, passando ambos IDs",
+                "description": "Atualizar ingrediente pelo ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -511,199 +781,284 @@ const docTemplate = `{
                     "text/plain"
                 ],
                 "tags": [
-                    "ingredients_recipes"
+                    "ingredient"
                 ],
-                "summary": "Adicionar ingrediente à receita",
+                "summary": "Atualizar ingrediente",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID da receita",
+                        "description": "ID do ingrediente",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Ingrediente adicionado",
-                        "name": "reqIngredientRecipe",
+                        "description": "Ingrediente atualizado",
+                        "name": "ingredient",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.IngredientsRecipes"
+                            "$ref": "#/definitions/models.Ingredient"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Ingredient added!",
+                    "200": {
+                        "description": "Ingredient updated!",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Error adding ingredient to recipe"
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Ingredient already exists or data is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
-            }
-        },
-        "/recipe/{id}/ingredients/{ingredient_id}": {
+            },
             "delete": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Remover ingrediente cadastrado em uma receita, passando ambos IDs",
+                "description": "Mover o ingrediente para a lixeira, de onde pode ser restaurado por um administrador até ser removido definitivamente após o tempo de retenção. Até lá, as receitas que o usam continuam a exibi-lo. Ingredientes usados em receitas só são removidos com force=true; sem ele, a resposta lista as receitas afetadas. Apenas editores e administradores removem ingredientes, que são compartilhados por todas as receitas.",
                 "produces": [
-                    "text/plain"
+                    "text/plain",
+                    "application/json"
                 ],
                 "tags": [
-                    "ingredients_recipes"
+                    "ingredient"
                 ],
-                "summary": "Remover ingrediente da receita",
+                "summary": "Deletar ingrediente",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID da receita",
+                        "description": "ID do ingrediente",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID do ingrediente",
-                        "name": "ingredient_id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Remover mesmo que o ingrediente seja usado em receitas",
+                        "name": "force",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Ingredient removed from recipe!",
+                        "description": "Ingredient deleted!",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "400": {
+                        "description": "Invalid force",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.IngredientInUse"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/user": {
-            "get": {
+        "/ingredient/{id}/aliases": {
+            "post": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Buscar todos os usuários cadastrados",
-                "produces": [
+                "description": "Adicionar um nome alternativo (sinônimo) ao ingrediente, como \"aipim\" e \"macaxeira\" para \"mandioca\". Os nomes alternativos são considerados na busca pelo nome e na importação de receitas. Apenas editores e administradores alteram os nomes do catálogo.",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "text/plain"
+                ],
                 "tags": [
-                    "user"
+                    "ingredient"
                 ],
-                "summary": "Buscar todos os usuários",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found"
+                "summary": "Adicionar nome alternativo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do ingrediente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    {
+                        "description": "Nome alternativo",
+                        "name": "alias",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IngredientAlias"
+                        }
                     }
-                }
-            },
-            "post": {
-                "description": "Criar novo usuário",
-                "consumes": [
-                    "application/json"
                 ],
-                "produces": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "user"
-                ],
-                "summary": "Criar novo usuário",
                 "responses": {
                     "201": {
-                        "description": "User created!",
+                        "description": "Alias created!",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON"
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Ingredient not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/user/login": {
-            "post": {
-                "description": "Autentica o usuário e retorna um token JWT de acesso",
-                "consumes": [
-                    "application/json"
+        "/ingredient/{id}/aliases/{name}": {
+            "delete": {
+                "security": [
+                    {
+                        "Token": []
+                    }
                 ],
+                "description": "Remover um nome alternativo do ingrediente. Apenas editores e administradores alteram os nomes do catálogo.",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "user"
+                    "ingredient"
+                ],
+                "summary": "Remover nome alternativo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do ingrediente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome alternativo",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Realizar login do usuário",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Alias deleted!",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid JSON"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
-                    "401": {
-                        "description": "Unauthorized"
+                    "404": {
+                        "description": "Alias not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/user/{id}": {
-            "get": {
+        "/ingredient/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Buscar usuário pelo ID",
+                "description": "Restaurar da lixeira um ingrediente. Como os ingredientes não têm dono, apenas administradores podem restaurá-los.",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "user"
+                    "trash"
                 ],
-                "summary": "Buscar usuário pelo ID",
+                "summary": "Restaurar ingrediente",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID do usuário",
+                        "description": "ID do ingrediente",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -711,29 +1066,40 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Ingredient restored!",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Ingredient not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/ingredient/{id}/translations/{locale}": {
             "put": {
                 "security": [
                     {
                         "Token": []
                     }
                 ],
-                "description": "Atualizar usuário pelo ID",
+                "description": "Definir o nome do ingrediente em um idioma (pt-BR, pt-PT, en ou es). As respostas usam o nome do idioma pedido no cabeçalho Accept-Language, e as traduções também são consideradas na busca pelo nome e na importação de receitas. Apenas editores e administradores alteram os nomes do catálogo.",
                 "consumes": [
                     "application/json"
                 ],
@@ -741,33 +1107,82 @@ const docTemplate = `{
                     "text/plain"
                 ],
                 "tags": [
-                    "user"
+                    "ingredient"
                 ],
-                "summary": "Atualizar usuário",
+                "summary": "Definir tradução",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID do usuário",
+                        "description": "ID do ingrediente",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "pt-BR",
+                            "pt-PT",
+                            "en",
+                            "es"
+                        ],
+                        "type": "string",
+                        "description": "Idioma",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nome no idioma",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IngredientTranslation"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User updated!",
+                        "description": "Translation saved!",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON"
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Ingredient not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             },
@@ -777,154 +1192,3883 @@ const docTemplate = `{
                         "Token": []
                     }
                 ],
-                "description": "Deletar usuário pelo ID",
+                "description": "Remover o nome do ingrediente em um idioma. Apenas editores e administradores alteram os nomes do catálogo.",
                 "produces": [
                     "text/plain"
                 ],
                 "tags": [
-                    "user"
+                    "ingredient"
                 ],
-                "summary": "Deletar usuário",
+                "summary": "Remover tradução",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID do usuário",
+                        "description": "ID do ingrediente",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "pt-BR",
+                            "pt-PT",
+                            "en",
+                            "es"
+                        ],
+                        "type": "string",
+                        "description": "Idioma",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted!",
+                        "description": "Translation deleted!",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Translation not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
         },
-        "/user/{id}/recipes": {
+        "/media/{key}": {
             "get": {
-                "security": [
-                    {
-                        "Token": []
-                    }
-                ],
-                "description": "Buscar receitas criadas pelo usuário",
+                "description": "Servir arquivo guardado (imagem ou miniatura) através de uma URL assinada e temporária",
                 "produces": [
-                    "application/json"
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "image/webp"
                 ],
                 "tags": [
-                    "user"
+                    "recipe_images"
                 ],
-                "summary": "Buscar receitas criadas pelo usuário",
+                "summary": "Acessar arquivo de mídia",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID do usuário",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Chave do arquivo",
+                        "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Validade da URL (Unix timestamp)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Assinatura da URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Recipe"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid or expired signature",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found"
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     },
                     "500": {
-                        "description": "Internal Server Error"
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Ingredient": {
-            "description": "Modelo para gerenciamento de ingredientes.",
-            "type": "object",
-            "properties": {
-                "id": {
-                    "description": "ID é o identificador único do ingrediente.",
-                    "type": "integer"
-                },
-                "name": {
-                    "description": "Name é o nome do ingrediente.",
-                    "type": "string",
-                    "example": "Farinha de trigo."
-                }
-            }
         },
-        "models.IngredientsRecipes": {
-            "description": "Modelo para relacionar um ingrediente da tabela ingredients a uma receita.",
-            "type": "object",
-            "properties": {
-                "ingredient": {
-                    "description": "Ingredient é o objeto do ingrediente adicionado.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/models.Ingredient"
+        "/readyz": {
+            "get": {
+                "description": "Verificar a conexão com o banco, as migrações pendentes e as demais dependências registradas. Usado pelo orquestrador para decidir se o processo recebe requisições.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Pronto para atender",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Readiness"
                         }
-                    ]
-                },
-                "ingredient_id": {
-                    "description": "IngredientID é o ID do ingrediente adicionado.",
-                    "type": "integer"
-                },
-                "quantity": {
-                    "description": "Quantity é a quantidade do ingrediente adicionado.",
-                    "type": "string",
-                    "example": "200g"
-                },
-                "recipeID": {
-                    "description": "RecipeID é o ID da receita à qual o ingrediente foi adicionado.",
-                    "type": "integer"
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Readiness"
+                        }
+                    }
                 }
             }
         },
-        "models.Recipe": {
-            "description": "Modelo para gerenciamento de receitas.",
-            "type": "object",
-            "properties": {
-                "id": {
+        "/recipe": {
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Criar nova receita junto com seus ingredientes, informados pelo ID ou pelo nome do ingrediente. A receita pertence ao usuário autenticado; só administradores podem informar outro dono (user_id). Com create_missing_ingredients, os ingredientes informados pelo nome que ainda não existem são cadastrados. Sem visibility, a receita é publicada; use draft para criá-la como rascunho. A receita e seus ingredientes são gravados em uma única transação; se algum item for inválido nada é gravado e os erros de cada item são retornados.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Criar nova receita",
+                "parameters": [
+                    {
+                        "description": "Nova receita",
+                        "name": "recipe",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Recipe created!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Recipe already exists or data is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar as receitas publicadas, junto com todas as receitas do usuário autenticado (se houver token). Os nomes dos ingredientes seguem o cabeçalho Accept-Language.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Buscar todas as receitas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Recipe"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/import": {
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Importar receita a partir de um documento schema.org Recipe em JSON-LD ou do HTML de uma página (sem buscar URLs). Os ingredientes são associados aos já cadastrados, e é retornado um rascunho da receita, ainda não salvo, com as linhas que não puderam ser interpretadas. Nada é cadastrado: os ingredientes sem correspondência ficam no rascunho sem ID, listados em unmatched_ingredients, e podem ser cadastrados ao criar a receita com create_missing_ingredients.",
+                "consumes": [
+                    "application/json",
+                    "text/html"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Importar receita",
+                "parameters": [
+                    {
+                        "description": "Documento JSON-LD ou página HTML",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid document",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Document is too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "No schema.org Recipe found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/ingredients/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Adicionar ingrediente cadastrado à uma receita criada, passando ambos IDs. O ingrediente é colocado no fim da lista; use a rota de ordenação para movê-lo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "ingredients_recipes"
+                ],
+                "summary": "Adicionar ingrediente à receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ingrediente adicionado",
+                        "name": "reqIngredientRecipe",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.IngredientsRecipes"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Ingredient added!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Ingredient is already in the recipe, or the recipe or ingredient does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/ingredients/{id}/order": {
+            "put": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Definir a ordem de exibição dos ingredientes da receita, informando os IDs de todos os ingredientes na ordem desejada",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "ingredients_recipes"
+                ],
+                "summary": "Ordenar ingredientes da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nova ordem dos ingredientes",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeIngredientOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ingredients reordered!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/ingredients/{id}/{ingredient_id}": {
+            "put": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Atualizar a quantidade, o grupo, a observação e as marcações de opcional e \"a gosto\" de um ingrediente da receita",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "ingredients_recipes"
+                ],
+                "summary": "Atualizar ingrediente da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID do ingrediente",
+                        "name": "ingredient_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novos dados do ingrediente",
+                        "name": "ingredient",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeIngredientUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ingredient updated!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Remover ingrediente cadastrado em uma receita, passando ambos IDs",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "ingredients_recipes"
+                ],
+                "summary": "Remover ingrediente da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID do ingrediente",
+                        "name": "ingredient_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ingredient removed from recipe!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/name/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar receita pelo slug ou pelo nome sem case sensitive e convertendo '-' para espaços. Como os nomes são únicos apenas entre as receitas de cada autor, retorna a mais antiga entre as visíveis para o usuário; para um endereço estável use /user/{username}/recipe/{slug}. Aceita os mesmos formatos de resposta da busca pelo ID.",
+                "produces": [
+                    "application/json",
+                    "application/ld+json",
+                    "text/markdown",
+                    "text/html"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Buscar receita pelo nome",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome da receita",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "jsonld",
+                            "markdown",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Recipe"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar receita pelo ID. Receitas em rascunho, privadas ou com publicação agendada só são encontradas pelo autor. O formato da resposta é escolhido pelo parâmetro format ou pelo cabeçalho Accept, respeitando os pesos (q) informados: JSON, schema.org JSON-LD, Markdown ou HTML para impressão.",
+                "produces": [
+                    "application/json",
+                    "application/ld+json",
+                    "text/markdown",
+                    "text/html"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Buscar receita pelo ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "jsonld",
+                            "markdown",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Recipe"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Atualizar receita pelo ID; apenas o autor ou um administrador podem alterá-la, e só administradores trocam o dono (user_id). Quando a lista ingredients é informada, ela substitui todos os ingredientes da receita na mesma transação; quando omitida, os ingredientes atuais são mantidos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Atualizar receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Receita atualizada",
+                        "name": "recipe",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recipe updated!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Recipe already exists or data is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Mover a receita para a lixeira, de onde pode ser restaurada até ser removida definitivamente após o tempo de retenção. Apenas o autor ou um administrador podem removê-la.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Deletar receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recipe deleted!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/fork": {
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Copiar a receita e seus ingredientes para o usuário autenticado, mantendo a referência à receita original para atribuição. A cópia começa como rascunho. Sem nome informado, a cópia usa o nome da original, com um sufixo numérico se o usuário já tiver uma receita com esse nome. Com substitute (ex.: vegan), os ingredientes que têm substituição para o motivo são trocados, gerando uma versão da receita (ex.: \"Bolo (versão vegana)\").",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Copiar receita (fork)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita original",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Opções da cópia",
+                        "name": "fork",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeForkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Recipe name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/forks": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Listar as cópias (forks) feitas a partir da receita e, recursivamente, as cópias dessas cópias. De outros usuários, aparecem apenas as cópias publicadas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Árvore de cópias da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeForkNode"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/images": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar imagens da receita e dos passos, com URLs assinadas e temporárias",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe_images"
+                ],
+                "summary": "Buscar imagens da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecipeImage"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Enviar imagem (JPEG, PNG, GIF ou WebP, até 5MB e 40 megapixels) para a receita ou para um passo do modo de preparo. Miniaturas são geradas automaticamente.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe_images"
+                ],
+                "summary": "Enviar imagem da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Arquivo da imagem",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número do passo do modo de preparo ilustrado pela imagem",
+                        "name": "step",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeImage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid image",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Image or its dimensions are too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported image type",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/images/{image_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Deletar imagem da receita e suas miniaturas",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "recipe_images"
+                ],
+                "summary": "Deletar imagem da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID da imagem",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Image deleted!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/images/{image_id}/file": {
+            "get": {
+                "description": "Servir o arquivo original da imagem por uma URL estável, usada nos documentos exportados (ex.: JSON-LD). A imagem segue a visibilidade da receita.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "image/webp"
+                ],
+                "tags": [
+                    "recipe_images"
+                ],
+                "summary": "Acessar arquivo da imagem da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID da imagem",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/parent/diff": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Comparar o estado atual da cópia (fork) com o estado atual da receita original: campos alterados, modo de preparo linha a linha e ingredientes adicionados, removidos ou alterados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Comparar cópia com a receita original",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da cópia",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeForkDiff"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Restaurar da lixeira uma receita do usuário (administradores podem restaurar qualquer receita), com seus ingredientes, imagens e histórico",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restaurar receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recipe restored!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found in trash",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Listar as revisões da receita, da mais recente para a mais antiga, com autor, data e resumo da alteração. Apenas o autor da receita ou um administrador veem o histórico, que guarda também estados que não foram publicados.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Histórico da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RecipeRevision"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/revisions/diff": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Comparar duas revisões da receita: campos alterados, modo de preparo linha a linha e ingredientes adicionados, removidos ou alterados. Sem parâmetros, compara a última revisão com a anterior. Apenas o autor da receita ou um administrador veem o histórico.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Comparar revisões da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número da revisão de origem (padrão: a anterior à de destino)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número da revisão de destino (padrão: a última)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeRevisionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid revision number",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/revisions/{number}": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar uma revisão da receita pelo número, com o estado completo da receita e de seus ingredientes naquela revisão. Apenas o autor da receita ou um administrador veem o histórico.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Buscar revisão da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número da revisão",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeRevision"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/revisions/{number}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Restaurar a receita, com a visibilidade e o agendamento da publicação, e seus ingredientes para o estado de uma revisão antiga. Apenas o autor da receita ou um administrador podem restaurá-la. A restauração é registrada como uma nova revisão, preservando o histórico; ingredientes removidos do catálogo desde então são cadastrados novamente.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Restaurar revisão da receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número da revisão a restaurar",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeRevision"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Recipe name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipe/{id}/substitutions": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Listar, para cada ingrediente da receita, as substituições cadastradas, com as quantidades calculadas a partir da quantidade usada na receita. Para gerar uma versão da receita com as substituições aplicadas, use a cópia (fork) com o campo substitute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Sugerir substituições para a receita",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da receita",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "vegan",
+                            "vegetarian",
+                            "dairy-free",
+                            "gluten-free",
+                            "egg-free"
+                        ],
+                        "type": "string",
+                        "description": "Motivo alimentar",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecipeSubstitutions"
+                        }
+                    },
+                    "404": {
+                        "description": "Recipe not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/substitution": {
+            "get": {
+                "description": "Buscar as substituições de ingredientes cadastradas, opcionalmente filtradas pelo ingrediente substituído e pelo motivo alimentar",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "substitution"
+                ],
+                "summary": "Buscar substituições",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do ingrediente substituído",
+                        "name": "ingredient_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "vegan",
+                            "vegetarian",
+                            "dairy-free",
+                            "gluten-free",
+                            "egg-free"
+                        ],
+                        "type": "string",
+                        "description": "Motivo alimentar",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Substitution"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ingredient_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Cadastrar uma substituição de ingrediente (ex.: leitelho por leite e suco de limão), com a proporção de cada ingrediente em relação à quantidade do substituído. Restrito a editores e administradores.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "substitution"
+                ],
+                "summary": "Cadastrar substituição",
+                "parameters": [
+                    {
+                        "description": "Nova substituição",
+                        "name": "substitution",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubstitutionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Substitution"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/substitution/{id}": {
+            "get": {
+                "description": "Buscar substituição de ingrediente pelo ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "substitution"
+                ],
+                "summary": "Buscar substituição pelo ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da substituição",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Substitution"
+                        }
+                    },
+                    "404": {
+                        "description": "Substitution not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Substituir os dados e os ingredientes de uma substituição. Restrito a editores e administradores.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "substitution"
+                ],
+                "summary": "Atualizar substituição",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da substituição",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Substituição atualizada",
+                        "name": "substitution",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SubstitutionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Substitution"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Substitution not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Remover uma substituição de ingrediente. Restrito a editores e administradores.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "substitution"
+                ],
+                "summary": "Deletar substituição",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID da substituição",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Substitution deleted!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Substitution not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Listar as receitas do usuário que estão na lixeira, com a data em que serão removidas definitivamente. Administradores veem todas as receitas e também os ingredientes na lixeira.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Lixeira do usuário",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Trash"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar todos os usuários cadastrados",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Buscar todos os usuários",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Criar novo usuário",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Criar novo usuário",
+                "responses": {
+                    "201": {
+                        "description": "User created!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists or data is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login": {
+            "post": {
+                "description": "Autentica o usuário e retorna um token JWT de acesso",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Realizar login do usuário",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar usuário pelo ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Buscar usuário pelo ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Atualizar usuário pelo ID; apenas o próprio usuário ou um administrador podem alterá-lo",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Atualizar usuário",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists or data is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Mover o usuário para a lixeira, junto com as receitas dele: ele deixa de conseguir entrar no sistema e pode ser restaurado por um administrador até ser removido definitivamente após o tempo de retenção. Apenas o próprio usuário ou um administrador podem removê-lo.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Deletar usuário",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted!",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/recipes": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar receitas criadas pelo usuário. O próprio usuário vê todas as suas receitas; os demais, apenas as publicadas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Buscar receitas criadas pelo usuário",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do usuário",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Recipe"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/recipe/{slug}": {
+            "get": {
+                "security": [
+                    {
+                        "Token": []
+                    }
+                ],
+                "description": "Buscar a receita pelo nome de usuário do autor e pelo slug da receita. Slugs antigos, de antes de uma renomeação, redirecionam para o endereço atual. Receitas em rascunho, privadas ou com publicação agendada só são encontradas pelo autor. Aceita os mesmos formatos de resposta da busca pelo ID.",
+                "produces": [
+                    "application/json",
+                    "application/ld+json",
+                    "text/markdown",
+                    "text/html"
+                ],
+                "tags": [
+                    "recipe"
+                ],
+                "summary": "Buscar receita do usuário pelo slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Nome de usuário do autor",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Slug da receita",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "jsonld",
+                            "markdown",
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Formato da resposta",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipe"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Mostrar o commit, a data da compilação e a versão do Go do executável em execução",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Versão",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BuildInfo"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "catalog.Report": {
+            "description": "Relatório da importação em lote, com os erros encontrados por linha.",
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "Committed indica se as alterações foram gravadas no banco.",
+                    "type": "boolean"
+                },
+                "created": {
+                    "description": "Created é o número de registros novos.",
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "description": "DryRun indica se a importação foi apenas simulada.",
+                    "type": "boolean"
+                },
+                "errors": {
+                    "description": "Errors lista os registros rejeitados; qualquer erro impede a gravação do lote.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/catalog.RowError"
+                    }
+                },
+                "ingredients_created": {
+                    "description": "IngredientsCreated é o número de ingredientes criados pelas receitas importadas.",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total é o número de registros lidos do arquivo.",
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "description": "Unchanged é o número de registros existentes que já estavam iguais ao arquivo.",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "Updated é o número de registros existentes que foram alterados.",
+                    "type": "integer"
+                }
+            }
+        },
+        "catalog.RowError": {
+            "description": "Erro de um registro do arquivo importado.",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error descreve o problema encontrado.",
+                    "type": "string",
+                    "example": "ingredient 2: quantity is required"
+                },
+                "line": {
+                    "description": "Line é a linha do arquivo onde o registro começa.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "description": "Name é o nome do registro, quando informado.",
+                    "type": "string",
+                    "example": "bolo de chocolate"
+                }
+            }
+        },
+        "models.BuildInfo": {
+            "description": "Versão do executável, com o commit de origem e a versão do Go.",
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "description": "BuildTime é a data da compilação, definida no build com -ldflags (make build); ausente quando\nnão informada.",
+                    "type": "string",
+                    "example": "2026-10-19T06:10:00Z"
+                },
+                "commit": {
+                    "description": "Commit é o commit do código compilado.",
+                    "type": "string",
+                    "example": "14d17c5a9e0f3b8c2d7e6f1a4b5c8d9e0f1a2b3c"
+                },
+                "commit_time": {
+                    "description": "CommitTime é a data do commit.",
+                    "type": "string",
+                    "example": "2026-10-19T06:06:24Z"
+                },
+                "go_version": {
+                    "description": "GoVersion é a versão do Go usada na compilação.",
+                    "type": "string",
+                    "example": "go1.23.2"
+                },
+                "modified": {
+                    "description": "Modified indica que o código tinha alterações não commitadas.",
+                    "type": "boolean"
+                },
+                "version": {
+                    "description": "Version é a versão do módulo, \"(devel)\" quando compilado a partir do código.",
+                    "type": "string",
+                    "example": "(devel)"
+                }
+            }
+        },
+        "models.CookbookExport": {
+            "description": "Modelo para acompanhar a exportação de um livro de receitas.",
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "description": "AuthorID é o ID do usuário cujas receitas compõem o livro, quando não for informada uma coleção.",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "CreatedAt é a data do pedido de exportação.",
+                    "type": "string"
+                },
+                "epub_url": {
+                    "description": "EpubURL e PdfURL são os endereços para baixar os arquivos, quando a exportação terminar.",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error é a mensagem de erro, quando a exportação falhar.",
+                    "type": "string"
+                },
+                "finished_at": {
+                    "description": "FinishedAt é a data de término da exportação.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID é o identificador único da exportação.",
+                    "type": "integer"
+                },
+                "pdf_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status é a situação da exportação: pending, running, done ou failed.",
+                    "type": "string",
+                    "example": "done"
+                },
+                "title": {
+                    "description": "Title é o título do livro.",
+                    "type": "string",
+                    "example": "Receitas da vovó"
+                },
+                "user_id": {
+                    "description": "UserID é o ID do usuário que pediu a exportação.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CookbookExportRequest": {
+            "description": "Modelo para pedir a exportação de um livro de receitas.",
+            "type": "object",
+            "properties": {
+                "recipe_ids": {
+                    "description": "RecipeIDs é uma coleção de receitas escolhidas; quando informada, substitui as receitas do usuário.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "title": {
+                    "description": "Title é o título do livro (opcional).",
+                    "type": "string",
+                    "example": "Receitas da vovó"
+                },
+                "user_id": {
+                    "description": "UserID é o ID do usuário cujas receitas compõem o livro (padrão: o usuário autenticado).",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.FieldChange": {
+            "description": "Campo alterado entre duas revisões.",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "servings"
+                },
+                "new": {
+                    "type": "string",
+                    "example": "12"
+                },
+                "old": {
+                    "type": "string",
+                    "example": "8"
+                }
+            }
+        },
+        "models.FieldError": {
+            "description": "Erro de validação de um campo ou de um item de lista da requisição.",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field é o caminho do campo inválido.",
+                    "type": "string",
+                    "example": "ingredients[1].quantity"
+                },
+                "message": {
+                    "description": "Message descreve o problema encontrado.",
+                    "type": "string",
+                    "example": "quantity is required"
+                }
+            }
+        },
+        "models.Health": {
+            "description": "O processo está no ar; não verifica as dependências.",
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "Status é sempre \"ok\".",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "models.Ingredient": {
+            "description": "Modelo para gerenciamento de ingredientes.",
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Aliases são os nomes alternativos (sinônimos) do ingrediente, como os nomes de ingredientes duplicados unificados a ele.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IngredientAlias"
+                    }
+                },
+                "id": {
+                    "description": "ID é o identificador único do ingrediente.",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name é o nome do ingrediente em português do Brasil. Nas respostas, é substituído pela tradução\ndo idioma pedido no cabeçalho Accept-Language, quando houver.",
+                    "type": "string",
+                    "example": "Farinha de trigo."
+                },
+                "translations": {
+                    "description": "Translations são os nomes do ingrediente em outros idiomas.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IngredientTranslation"
+                    }
+                }
+            }
+        },
+        "models.IngredientAlias": {
+            "description": "Nome alternativo (sinônimo) de um ingrediente, como \"aipim\" para \"mandioca\" ou o nome de um ingrediente duplicado que foi unificado a ele.",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name é o nome alternativo, único entre todos os nomes alternativos.",
+                    "type": "string",
+                    "example": "aipim"
+                }
+            }
+        },
+        "models.IngredientChange": {
+            "description": "Ingrediente adicionado (added), removido (removed) ou alterado (changed) entre duas revisões.",
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "ingredient_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Farinha de trigo"
+                },
+                "op": {
+                    "type": "string",
+                    "example": "changed"
+                }
+            }
+        },
+        "models.IngredientInUse": {
+            "description": "Erro (RFC 7807) com as receitas que usam o ingrediente e impedem sua remoção sem o parâmetro force.",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code é o código estável do erro (ex.: recipe_not_found, validation_failed, invalid_token).",
+                    "type": "string",
+                    "example": "recipe_not_found"
+                },
+                "count": {
+                    "description": "Count é o número de receitas que usam o ingrediente, inclusive as que o usuário não vê.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "detail": {
+                    "description": "Detail explica o erro desta requisição.",
+                    "type": "string",
+                    "example": "Recipe not found"
+                },
+                "errors": {
+                    "description": "Errors são os campos inválidos, nos erros de validação.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "ingredient_id": {
+                    "description": "IngredientID é o ID do ingrediente.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "instance": {
+                    "description": "Instance é o caminho da requisição.",
+                    "type": "string",
+                    "example": "/recipe/42"
+                },
+                "recipes": {
+                    "description": "Recipes são as receitas que usam o ingrediente e são visíveis para o usuário; rascunhos e\nreceitas privadas de outros autores entram apenas na contagem.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IngredientUsageRecipe"
+                    }
+                },
+                "request_id": {
+                    "description": "RequestID é o ID da requisição, o mesmo do cabeçalho X-Request-ID e do log.",
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                },
+                "status": {
+                    "description": "Status é o status HTTP da resposta.",
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "Title é o texto do status HTTP.",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "description": "Type identifica o tipo do problema; \"about:blank\" indica que o status HTTP basta, e o código\nem Code distingue os erros.",
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "models.IngredientMergeReport": {
+            "description": "Ingrediente canônico, com os nomes dos duplicados como nomes alternativos, e as receitas alteradas.",
+            "type": "object",
+            "properties": {
+                "ingredient": {
+                    "description": "Ingredient é o ingrediente canônico após a unificação.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Ingredient"
+                        }
+                    ]
+                },
+                "recipe_ids": {
+                    "description": "RecipeIDs são as receitas cujos ingredientes passaram a apontar para o canônico.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        4,
+                        7
+                    ]
+                }
+            }
+        },
+        "models.IngredientMergeRequest": {
+            "description": "Ingrediente canônico e os duplicados que serão unificados a ele.",
+            "type": "object",
+            "properties": {
+                "canonical_id": {
+                    "description": "CanonicalID é o ID do ingrediente que será mantido.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "duplicate_ids": {
+                    "description": "DuplicateIDs são os IDs dos ingredientes que serão unificados ao canônico e removidos.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "models.IngredientTranslation": {
+            "description": "Nome do ingrediente em um idioma, usado nas respostas conforme o cabeçalho Accept-Language.",
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "description": "Locale é o idioma do nome (pt-BR, pt-PT, en ou es).",
+                    "type": "string",
+                    "example": "en"
+                },
+                "name": {
+                    "description": "Name é o nome do ingrediente no idioma.",
+                    "type": "string",
+                    "example": "cassava"
+                }
+            }
+        },
+        "models.IngredientUsageRecipe": {
+            "description": "Receita que usa o ingrediente.",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "name": {
+                    "type": "string",
+                    "example": "bolo de cenoura"
+                }
+            }
+        },
+        "models.IngredientsRecipes": {
+            "description": "Modelo para relacionar um ingrediente da tabela ingredients a uma receita.",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "description": "Group é o título do grupo de ingredientes ao qual o ingrediente pertence (ex.: \"Massa\", \"Cobertura\").",
+                    "type": "string",
+                    "example": "Massa"
+                },
+                "ingredient": {
+                    "description": "Ingredient é o objeto do ingrediente adicionado.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Ingredient"
+                        }
+                    ]
+                },
+                "ingredient_id": {
+                    "description": "IngredientID é o ID do ingrediente adicionado.",
+                    "type": "integer"
+                },
+                "note": {
+                    "description": "Note é uma observação sobre o ingrediente (ex.: \"peneirada\").",
+                    "type": "string",
+                    "example": "peneirada"
+                },
+                "optional": {
+                    "description": "Optional indica que o ingrediente é opcional.",
+                    "type": "boolean"
+                },
+                "position": {
+                    "description": "Position é a posição do ingrediente na lista da receita, começando em 0.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "quantity": {
+                    "description": "Quantity é a quantidade do ingrediente adicionado.",
+                    "type": "string",
+                    "example": "200g"
+                },
+                "recipeID": {
+                    "description": "RecipeID é o ID da receita à qual o ingrediente foi adicionado.",
+                    "type": "integer"
+                },
+                "to_taste": {
+                    "description": "ToTaste indica que o ingrediente é usado a gosto; nesse caso a quantidade pode ficar vazia.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LineChange": {
+            "description": "Linha da comparação: equal (sem mudança), insert (adicionada) ou delete (removida).",
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string",
+                    "example": "insert"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Asse por 40 minutos."
+                }
+            }
+        },
+        "models.Problem": {
+            "description": "Erro da requisição, com um código estável para ser tratado pelos clientes e, nos erros de validação, a lista dos campos inválidos.",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code é o código estável do erro (ex.: recipe_not_found, validation_failed, invalid_token).",
+                    "type": "string",
+                    "example": "recipe_not_found"
+                },
+                "detail": {
+                    "description": "Detail explica o erro desta requisição.",
+                    "type": "string",
+                    "example": "Recipe not found"
+                },
+                "errors": {
+                    "description": "Errors são os campos inválidos, nos erros de validação.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "instance": {
+                    "description": "Instance é o caminho da requisição.",
+                    "type": "string",
+                    "example": "/recipe/42"
+                },
+                "request_id": {
+                    "description": "RequestID é o ID da requisição, o mesmo do cabeçalho X-Request-ID e do log.",
+                    "type": "string",
+                    "example": "4bf92f3577b34da6a3ce929d0e0e4736"
+                },
+                "status": {
+                    "description": "Status é o status HTTP da resposta.",
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "Title é o texto do status HTTP.",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "description": "Type identifica o tipo do problema; \"about:blank\" indica que o status HTTP basta, e o código\nem Code distingue os erros.",
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "models.Readiness": {
+            "description": "Resultado das verificações das dependências da API. O status é \"ok\" apenas quando todas passam.",
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "description": "Checks são os resultados de cada verificação, na ordem em que foram registradas.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReadinessCheck"
+                    }
+                },
+                "status": {
+                    "description": "Status é \"ok\" ou \"unavailable\".",
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "unavailable"
+                    ],
+                    "example": "ok"
+                }
+            }
+        },
+        "models.ReadinessCheck": {
+            "description": "Resultado de uma verificação, com o erro quando ela falha.",
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "description": "DurationMs é o tempo da verificação em milissegundos.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "error": {
+                    "description": "Error é o motivo da falha.",
+                    "type": "string",
+                    "example": "2 pending migrations, starting with 0003_recipe_ratings"
+                },
+                "name": {
+                    "description": "Name identifica a verificação.",
+                    "type": "string",
+                    "example": "database"
+                },
+                "status": {
+                    "description": "Status é \"ok\" ou \"unavailable\".",
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "unavailable"
+                    ],
+                    "example": "ok"
+                }
+            }
+        },
+        "models.Recipe": {
+            "description": "Modelo para gerenciamento de receitas.",
+            "type": "object",
+            "properties": {
+                "cook_time": {
+                    "description": "CookTime é o tempo de cozimento, em minutos.",
+                    "type": "integer",
+                    "example": 40
+                },
+                "fork_count": {
+                    "description": "ForkCount é o número de cópias (forks) publicadas feitas diretamente a partir desta receita.",
+                    "type": "integer"
+                },
+                "id": {
                     "description": "ID é o identificador único da receita.",
                     "type": "integer"
                 },
+                "images": {
+                    "description": "Images representa as imagens da receita e dos passos do modo de preparo.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeImage"
+                    }
+                },
+                "ingredients": {
+                    "description": "IngredientsRecipes representa o conjunto de ingredientes que pertence à receita.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IngredientsRecipes"
+                    }
+                },
+                "instructions": {
+                    "description": "Instructions representa as instruções sobre o modo de preparo da receita.",
+                    "type": "string",
+                    "example": "Em uma tigela adicione a farinha, o açucar e o cacau em pó."
+                },
+                "name": {
+                    "description": "Name é o nome da sua receita, único entre as receitas do autor.",
+                    "type": "string",
+                    "example": "bolo de chocolate"
+                },
+                "parent_id": {
+                    "description": "ParentID é a receita da qual esta receita foi copiada (fork), para atribuição ao original.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "prep_time": {
+                    "description": "PrepTime é o tempo de preparo, em minutos.",
+                    "type": "integer",
+                    "example": 20
+                },
+                "publish_at": {
+                    "description": "PublishAt agenda a publicação: receitas published ou unlisted só ficam visíveis para outros usuários a partir dessa data.",
+                    "type": "string"
+                },
+                "servings": {
+                    "description": "Servings é o número de porções que a receita rende.",
+                    "type": "integer",
+                    "example": 8
+                },
+                "slug": {
+                    "description": "Slug identifica a receita na URL, único entre as receitas do autor (ex.: /user/seunome/recipe/bolo-de-chocolate).",
+                    "type": "string",
+                    "example": "bolo-de-chocolate"
+                },
+                "total_time": {
+                    "description": "TotalTime é o tempo total da receita, em minutos.",
+                    "type": "integer",
+                    "example": 60
+                },
+                "user_id": {
+                    "description": "UserID é o identificador do usuário que criou a receita.",
+                    "type": "integer"
+                },
+                "visibility": {
+                    "description": "Visibility é o estado da receita: draft, private, unlisted ou published (padrão: published;\ncópias de outras receitas começam como draft).",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "private",
+                        "unlisted",
+                        "published"
+                    ],
+                    "example": "published"
+                }
+            }
+        },
+        "models.RecipeForkDiff": {
+            "description": "Diferenças da cópia em relação à receita original, no estado atual das duas.",
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "Fields são os campos simples alterados na cópia.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "ingredients": {
+                    "description": "Ingredients são os ingredientes adicionados, removidos ou alterados na cópia.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IngredientChange"
+                    }
+                },
+                "instructions": {
+                    "description": "Instructions é a comparação linha a linha do modo de preparo; vazia quando não mudou.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LineChange"
+                    }
+                },
+                "parent_id": {
+                    "description": "ParentID é o identificador da receita original.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "recipe_id": {
+                    "description": "RecipeID é o identificador da cópia.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "reordered": {
+                    "description": "Reordered indica que a ordem dos ingredientes mudou.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RecipeForkNode": {
+            "description": "Receita da árvore de cópias, com as cópias feitas a partir dela.",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Author é o nome do autor da receita.",
+                    "type": "string",
+                    "example": "seunome"
+                },
+                "forks": {
+                    "description": "Forks são as cópias feitas diretamente a partir desta receita.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeForkNode"
+                    }
+                },
+                "id": {
+                    "description": "ID é o identificador da receita.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "description": "Name é o nome da receita.",
+                    "type": "string",
+                    "example": "bolo de chocolate"
+                },
+                "user_id": {
+                    "description": "UserID é o identificador do autor da receita.",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.RecipeForkRequest": {
+            "description": "Opções da cópia de uma receita.",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Name é o nome da cópia (padrão: o nome da receita original).",
+                    "type": "string",
+                    "example": "bolo de chocolate sem glúten"
+                },
+                "substitute": {
+                    "description": "Substitute é o motivo alimentar (ex.: vegan) cujas substituições são aplicadas aos ingredientes\nda cópia, gerando, por exemplo, a versão vegana da receita.",
+                    "type": "string",
+                    "example": "vegan"
+                }
+            }
+        },
+        "models.RecipeImage": {
+            "description": "Modelo para gerenciamento das imagens de receitas.",
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "description": "ContentType é o tipo MIME da imagem original.",
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "created_at": {
+                    "description": "CreatedAt é a data de envio da imagem.",
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID é o identificador único da imagem.",
+                    "type": "integer"
+                },
+                "recipe_id": {
+                    "description": "RecipeID é o ID da receita à qual a imagem pertence.",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Size é o tamanho da imagem original em bytes.",
+                    "type": "integer"
+                },
+                "step": {
+                    "description": "Step é o número do passo do modo de preparo ilustrado pela imagem (nulo para a imagem da receita).",
+                    "type": "integer",
+                    "example": 2
+                },
+                "thumbnails": {
+                    "description": "Thumbnails são as miniaturas geradas automaticamente a partir da imagem original.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeImageThumbnail"
+                    }
+                },
+                "url": {
+                    "description": "URL é a URL assinada e temporária para acessar a imagem original.",
+                    "type": "string"
+                },
+                "width": {
+                    "description": "Width e Height são as dimensões da imagem original em pixels.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecipeImageThumbnail": {
+            "description": "Modelo das miniaturas das imagens de receitas.",
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Size é a largura máxima usada para gerar a miniatura.",
+                    "type": "integer",
+                    "example": 480
+                },
+                "url": {
+                    "description": "URL é a URL assinada e temporária para acessar a miniatura.",
+                    "type": "string"
+                },
+                "width": {
+                    "description": "Width e Height são as dimensões da miniatura em pixels.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RecipeImportIssue": {
+            "description": "Linha não interpretada durante a importação de uma receita.",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field é o campo de origem da linha (name, ingredients, instructions, servings, prep_time, cook_time, total_time).",
+                    "type": "string",
+                    "example": "ingredients"
+                },
+                "line": {
+                    "description": "Line é o texto original da linha.",
+                    "type": "string",
+                    "example": "Recheio a seu gosto"
+                },
+                "reason": {
+                    "description": "Reason é o motivo pelo qual a linha não foi interpretada.",
+                    "type": "string",
+                    "example": "quantity not recognized"
+                }
+            }
+        },
+        "models.RecipeImportResult": {
+            "description": "Rascunho da receita importada e relatório das linhas que não puderam ser interpretadas.",
+            "type": "object",
+            "properties": {
+                "recipe": {
+                    "description": "Recipe é o rascunho da receita, ainda não salvo, pronto para ser revisado e criado.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Recipe"
+                        }
+                    ]
+                },
+                "unmatched_ingredients": {
+                    "description": "UnmatchedIngredients são os nomes dos ingredientes que não correspondem a nenhum cadastrado. Eles\nficam no rascunho sem ID e são cadastrados ao criar a receita com create_missing_ingredients.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "fava tonka"
+                    ]
+                },
+                "unparsed": {
+                    "description": "Unparsed são as linhas que não puderam ser interpretadas.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeImportIssue"
+                    }
+                }
+            }
+        },
+        "models.RecipeIngredientOrder": {
+            "description": "IDs de todos os ingredientes da receita, na ordem desejada.",
+            "type": "object",
+            "properties": {
+                "ingredient_ids": {
+                    "description": "IngredientIDs são os IDs dos ingredientes da receita, cada um uma única vez.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "models.RecipeIngredientRequest": {
+            "description": "Ingrediente da receita, informado pelo ID ou pelo nome do ingrediente cadastrado.",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "description": "Group é o título do grupo de ingredientes (ex.: \"Massa\", \"Cobertura\").",
+                    "type": "string",
+                    "example": "Massa"
+                },
+                "ingredient_id": {
+                    "description": "IngredientID é o ID do ingrediente cadastrado.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "description": "Name é o nome do ingrediente, usado quando o ID não é informado (sem diferenciar maiúsculas e minúsculas).",
+                    "type": "string",
+                    "example": "Farinha de trigo"
+                },
+                "note": {
+                    "description": "Note é uma observação sobre o ingrediente (ex.: \"peneirada\").",
+                    "type": "string",
+                    "example": "peneirada"
+                },
+                "optional": {
+                    "description": "Optional indica que o ingrediente é opcional.",
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "description": "Quantity é a quantidade do ingrediente; pode ficar vazia quando o ingrediente é usado a gosto.",
+                    "type": "string",
+                    "example": "200g"
+                },
+                "to_taste": {
+                    "description": "ToTaste indica que o ingrediente é usado a gosto.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RecipeIngredientSubstitutions": {
+            "description": "Ingrediente da receita e as substituições possíveis para ele.",
+            "type": "object",
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "type": "string",
+                    "example": "leitelho"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "1 xícara"
+                },
+                "substitutions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubstitutionSuggestion"
+                    }
+                }
+            }
+        },
+        "models.RecipeIngredientUpdate": {
+            "description": "Dados de um ingrediente da receita que podem ser alterados.",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "description": "Group é o título do grupo de ingredientes (ex.: \"Massa\", \"Cobertura\").",
+                    "type": "string",
+                    "example": "Massa"
+                },
+                "note": {
+                    "description": "Note é uma observação sobre o ingrediente (ex.: \"peneirada\").",
+                    "type": "string",
+                    "example": "peneirada"
+                },
+                "optional": {
+                    "description": "Optional indica que o ingrediente é opcional.",
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "description": "Quantity é a quantidade do ingrediente; pode ficar vazia quando o ingrediente é usado a gosto.",
+                    "type": "string",
+                    "example": "250g"
+                },
+                "to_taste": {
+                    "description": "ToTaste indica que o ingrediente é usado a gosto.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RecipeRequest": {
+            "description": "Receita com a lista de ingredientes, gravadas juntas em uma única transação.",
+            "type": "object",
+            "properties": {
+                "cook_time": {
+                    "description": "CookTime é o tempo de cozimento, em minutos.",
+                    "type": "integer",
+                    "example": 40
+                },
+                "create_missing_ingredients": {
+                    "description": "CreateMissingIngredients cadastra os ingredientes informados pelo nome que ainda não existem.",
+                    "type": "boolean",
+                    "example": false
+                },
                 "ingredients": {
-                    "description": "IngredientsRecipes representa o conjunto de ingredientes que pertence à receita.",
+                    "description": "Ingredients é a lista completa de ingredientes da receita. Na atualização, quando omitida,\nos ingredientes atuais são mantidos.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.IngredientsRecipes"
+                        "$ref": "#/definitions/models.RecipeIngredientRequest"
                     }
                 },
                 "instructions": {
-                    "description": "Instructions representa as instruções sobre o modo de preparo da receita.",
+                    "description": "Instructions são as instruções do modo de preparo, um passo por linha.",
                     "type": "string",
                     "example": "Em uma tigela adicione a farinha, o açucar e o cacau em pó."
                 },
                 "name": {
-                    "description": "Name é o nome da sua receita.",
+                    "description": "Name é o nome da receita.",
                     "type": "string",
                     "example": "bolo de chocolate"
                 },
+                "prep_time": {
+                    "description": "PrepTime é o tempo de preparo, em minutos.",
+                    "type": "integer",
+                    "example": 20
+                },
+                "publish_at": {
+                    "description": "PublishAt agenda a publicação de receitas published ou unlisted para uma data futura. Na\natualização, quando omitido, o agendamento atual é mantido; null o cancela.",
+                    "type": "string",
+                    "example": "2025-12-24T09:00:00Z"
+                },
+                "servings": {
+                    "description": "Servings é o número de porções que a receita rende.",
+                    "type": "integer",
+                    "example": 8
+                },
+                "summary": {
+                    "description": "Summary é o resumo da alteração registrado no histórico (padrão: gerado a partir das diferenças).",
+                    "type": "string",
+                    "example": "menos açúcar na massa"
+                },
+                "total_time": {
+                    "description": "TotalTime é o tempo total da receita, em minutos.",
+                    "type": "integer",
+                    "example": 60
+                },
                 "user_id": {
-                    "description": "UserID é o identificador do usuário que criou a receita.",
+                    "description": "UserID é o identificador do dono da receita, informado apenas por administradores (padrão: o\nusuário autenticado, na criação, e o dono atual, na atualização).",
+                    "type": "integer",
+                    "example": 1
+                },
+                "visibility": {
+                    "description": "Visibility é o estado da receita: draft, private, unlisted ou published (padrão: published na\ncriação; na atualização, quando omitido, o estado atual é mantido).",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "private",
+                        "unlisted",
+                        "published"
+                    ],
+                    "example": "published"
+                }
+            }
+        },
+        "models.RecipeRevision": {
+            "description": "Revisão do histórico de uma receita, registrada a cada alteração.",
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Author é o nome do usuário que fez a alteração.",
+                    "type": "string",
+                    "example": "seunome"
+                },
+                "author_id": {
+                    "description": "AuthorID é o usuário que fez a alteração (0 para alterações feitas pela linha de comando).",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "CreatedAt é a data da alteração.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID é o identificador único da revisão.",
+                    "type": "integer"
+                },
+                "number": {
+                    "description": "Number é o número sequencial da revisão dentro da receita, começando em 1.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "recipe": {
+                    "description": "Recipe é o estado da receita nesta revisão, retornado apenas na consulta de uma revisão.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RecipeSnapshot"
+                        }
+                    ]
+                },
+                "recipe_id": {
+                    "description": "RecipeID é o identificador da receita.",
+                    "type": "integer"
+                },
+                "summary": {
+                    "description": "Summary é o resumo da alteração.",
+                    "type": "string",
+                    "example": "changed quantity of Farinha"
+                }
+            }
+        },
+        "models.RecipeRevisionDiff": {
+            "description": "Diferenças entre duas revisões: campos alterados, linhas do modo de preparo e ingredientes.",
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "Fields são os campos simples alterados.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "from": {
+                    "description": "From é o número da revisão de origem.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "ingredients": {
+                    "description": "Ingredients são os ingredientes adicionados, removidos ou alterados.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IngredientChange"
+                    }
+                },
+                "instructions": {
+                    "description": "Instructions é a comparação linha a linha do modo de preparo; vazia quando não mudou.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LineChange"
+                    }
+                },
+                "reordered": {
+                    "description": "Reordered indica que a ordem dos ingredientes mudou.",
+                    "type": "boolean"
+                },
+                "to": {
+                    "description": "To é o número da revisão de destino.",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.RecipeSnapshot": {
+            "description": "Estado da receita e de seus ingredientes em uma revisão.",
+            "type": "object",
+            "properties": {
+                "cook_time": {
+                    "type": "integer"
+                },
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeSnapshotIngredient"
+                    }
+                },
+                "instructions": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prep_time": {
+                    "type": "integer"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "servings": {
+                    "type": "integer"
+                },
+                "total_time": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecipeSnapshotIngredient": {
+            "description": "Ingrediente da receita em uma revisão; o nome permite restaurar ingredientes removidos do catálogo.",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "ingredient_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "optional": {
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "to_taste": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RecipeSubstitutions": {
+            "description": "Substituições sugeridas para cada ingrediente da receita, com as quantidades já calculadas.",
+            "type": "object",
+            "properties": {
+                "ingredients": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecipeIngredientSubstitutions"
+                    }
+                },
+                "recipe_id": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.Substitution": {
+            "description": "Substituição de um ingrediente por um ou mais ingredientes, com a proporção de cada um (ex.: leitelho por leite e suco de limão).",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID é o identificador único da substituição.",
                     "type": "integer"
+                },
+                "ingredient": {
+                    "description": "Ingredient é o ingrediente substituído.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Ingredient"
+                        }
+                    ]
+                },
+                "ingredient_id": {
+                    "description": "IngredientID é o ID do ingrediente substituído.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "notes": {
+                    "description": "Notes são observações sobre a substituição.",
+                    "type": "string",
+                    "example": "Misture e deixe descansar por 10 minutos antes de usar."
+                },
+                "reason": {
+                    "description": "Reason é o motivo alimentar da substituição (vegan, vegetarian, dairy-free, gluten-free ou egg-free); vazio vale para qualquer caso.",
+                    "type": "string",
+                    "example": "vegan"
+                },
+                "replacements": {
+                    "description": "Replacements são os ingredientes que entram no lugar do substituído.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubstitutionItem"
+                    }
+                }
+            }
+        },
+        "models.SubstitutionItem": {
+            "description": "Ingrediente usado na substituição, com a proporção em relação à quantidade do substituído.",
+            "type": "object",
+            "properties": {
+                "ingredient": {
+                    "description": "Ingredient é o ingrediente usado na substituição.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Ingredient"
+                        }
+                    ]
+                },
+                "ingredient_id": {
+                    "description": "IngredientID é o ID do ingrediente usado na substituição.",
+                    "type": "integer",
+                    "example": 7
+                },
+                "note": {
+                    "description": "Note é uma observação sobre o item.",
+                    "type": "string",
+                    "example": "integral"
+                },
+                "ratio": {
+                    "description": "Ratio é a quantidade do ingrediente para cada unidade do substituído (ex.: 0.95 de leite para 1 de leitelho).",
+                    "type": "number",
+                    "example": 0.95
+                }
+            }
+        },
+        "models.SubstitutionItemRequest": {
+            "description": "Ingrediente usado na substituição; sem proporção, é usada a mesma quantidade do substituído.",
+            "type": "object",
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "note": {
+                    "type": "string",
+                    "example": "integral"
+                },
+                "ratio": {
+                    "type": "number",
+                    "example": 0.95
+                }
+            }
+        },
+        "models.SubstitutionRequest": {
+            "description": "Ingrediente substituído, motivo, observações e ingredientes que entram no lugar dele.",
+            "type": "object",
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "Misture e deixe descansar por 10 minutos antes de usar."
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "vegan"
+                },
+                "replacements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubstitutionItemRequest"
+                    }
+                }
+            }
+        },
+        "models.SubstitutionSuggestion": {
+            "description": "Substituição com as quantidades calculadas a partir da quantidade do ingrediente na receita.",
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "vegan"
+                },
+                "replacements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SuggestedReplacement"
+                    }
+                },
+                "substitution_id": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.SuggestedReplacement": {
+            "description": "Ingrediente da substituição e a quantidade a usar na receita.",
+            "type": "object",
+            "properties": {
+                "ingredient_id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "name": {
+                    "type": "string",
+                    "example": "leite"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "string",
+                    "example": "0,95 xícara"
+                }
+            }
+        },
+        "models.Trash": {
+            "description": "Receitas e ingredientes na lixeira, que podem ser restaurados até serem removidos definitivamente.",
+            "type": "object",
+            "properties": {
+                "ingredients": {
+                    "description": "Ingredients são os ingredientes na lixeira, listados apenas para administradores.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrashItem"
+                    }
+                },
+                "recipes": {
+                    "description": "Recipes são as receitas do usuário (para administradores, todas) que estão na lixeira.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrashItem"
+                    }
+                }
+            }
+        },
+        "models.TrashItem": {
+            "description": "Item da lixeira, com a data em que será removido definitivamente.",
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "description": "DeletedAt é a data em que o item foi para a lixeira.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID é o identificador do item.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "description": "Name é o nome do item.",
+                    "type": "string",
+                    "example": "bolo de chocolate"
+                },
+                "purge_at": {
+                    "description": "PurgeAt é a data a partir da qual o item é removido definitivamente.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TrashPurgeReport": {
+            "description": "Quantidade de itens removidos definitivamente da lixeira.",
+            "type": "object",
+            "properties": {
+                "ingredients": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "recipes": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 0
                 }
             }
         },
@@ -945,6 +5089,11 @@ const docTemplate = `{
                     "description": "Password é a senha de entrada do usuário no sistema.",
                     "type": "string"
                 },
+                "role": {
+                    "description": "Role é o papel do usuário, que libera as rotas administrativas (user, editor ou admin). Editores\ncuidam do conteúdo de referência, como as substituições de ingredientes.",
+                    "type": "string",
+                    "example": "user"
+                },
                 "username": {
                     "description": "Username é o nome único do usuário no sistema.",
                     "type": "string",
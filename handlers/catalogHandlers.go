@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"main.go/app"
+	"main.go/catalog"
+	"main.go/logging"
+	"main.go/middlewares"
+	"main.go/problem"
+)
+
+// Tamanho máximo do arquivo aceito na importação em lote
+const maxCatalogImportSize = 32 << 20
+
+// @Summary      Importar catálogo
+// @Description  Importar ingredientes ou receitas (com seus ingredientes) em lote, a partir de um arquivo CSV ou NDJSON. Registros com o mesmo nome de um existente o atualizam (receitas, entre as do mesmo autor). Todo o arquivo é gravado em uma única transação: se algum registro for rejeitado nada é gravado, e o relatório traz o erro de cada linha. Em CSV de receitas, cada linha traz um ingrediente (colunas name, user, instructions, servings, prep_time, cook_time, total_time, visibility, ingredient, quantity). Receitas novas sem visibility são publicadas.
+// @Tags         admin
+// @Accept       text/csv,application/x-ndjson
+// @Produce      json
+// @Security Token
+// @Param		 kind path string true "Tipo de registro" Enums(ingredients, recipes)
+// @Param		 format query string false "Formato do arquivo (padrão pelo Content-Type)" Enums(csv, ndjson)
+// @Param		 dry_run query bool false "Apenas validar, sem gravar"
+// @Param		 file body string true "Conteúdo do arquivo"
+// @Success      200  {object}  catalog.Report
+// @Failure      400  {object}  models.Problem  "Invalid file"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Invalid kind"
+// @Failure      413  {object}  models.Problem  "File is too large"
+// @Failure      422  {object}  catalog.Report
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /admin/catalog/{kind}/import [post]
+func ImportCatalogHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middlewares.GetUserID(r)
+		if !ok {
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+			return
+		}
+
+		format := catalogFormat(r)
+		if format == "" {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid format")
+			return
+		}
+
+		dryRun := false
+		if value := r.URL.Query().Get("dry_run"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid dry_run")
+				return
+			}
+			dryRun = parsed
+		}
+
+		// Lê o arquivo inteiro antes de abrir a transação
+		var body bytes.Buffer
+		if _, err := body.ReadFrom(http.MaxBytesReader(w, r.Body, maxCatalogImportSize)); err != nil {
+			problem.Write(w, r, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, "File is too large")
+			return
+		}
+
+		report, err := app.Catalog.Import(r.Context(), chi.URLParam(r, "kind"), &body, catalog.Options{
+			Format:        format,
+			DryRun:        dryRun,
+			DefaultUserID: userID,
+		})
+		if err != nil {
+			if errors.Is(err, catalog.ErrInvalidKind) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "Invalid kind")
+				return
+			}
+			if errors.Is(err, catalog.ErrInvalidFormat) {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid format")
+				return
+			}
+			// Erros de leitura do arquivo (ex.: cabeçalho CSV incompleto)
+			var parseErr *catalog.ParseError
+			if errors.As(err, &parseErr) {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidRequest, fmt.Sprintf("Invalid file: %v", parseErr.Err))
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error importing catalog", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		reportJson, err := json.Marshal(report)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if len(report.Errors) > 0 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		w.Write(reportJson)
+	}
+}
+
+// @Summary      Exportar catálogo
+// @Description  Exportar todos os ingredientes ou receitas (com seus ingredientes) em CSV ou NDJSON, no mesmo formato aceito pela importação
+// @Tags         admin
+// @Produce      text/csv,application/x-ndjson
+// @Security Token
+// @Param		 kind path string true "Tipo de registro" Enums(ingredients, recipes)
+// @Param		 format query string false "Formato do arquivo" Enums(csv, ndjson) default(csv)
+// @Success      200  {file}  file
+// @Failure      400  {object}  models.Problem  "Invalid format"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Invalid kind"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /admin/catalog/{kind}/export [get]
+func ExportCatalogHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		kind := chi.URLParam(r, "kind")
+		if kind != catalog.KindIngredients && kind != catalog.KindRecipes {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "Invalid kind")
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = catalog.FormatCSV
+		}
+
+		// Gera o arquivo em memória para poder responder com erro se a consulta falhar
+		var body bytes.Buffer
+		if err := app.Catalog.Export(r.Context(), kind, &body, format); err != nil {
+			if errors.Is(err, catalog.ErrInvalidFormat) {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid format")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error exporting catalog", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		contentType := "text/csv; charset=utf-8"
+		if format == catalog.FormatNDJSON {
+			contentType = "application/x-ndjson"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.%s\"", kind, format))
+		w.Write(body.Bytes())
+	}
+}
+
+// Funções privadas
+
+// catalogFormat escolhe o formato do arquivo pelo parâmetro format ou pelo Content-Type
+func catalogFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if format == catalog.FormatCSV || format == catalog.FormatNDJSON {
+			return format
+		}
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/json":
+		return catalog.FormatNDJSON
+	case "text/csv", "":
+		return catalog.FormatCSV
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main.go/catalog"
+	"main.go/models"
+	"main.go/problem"
+)
+
+// failingCatalog é um CatalogRepository cuja importação sempre falha com o erro informado
+type failingCatalog struct {
+	err error
+}
+
+func (c failingCatalog) Import(ctx context.Context, kind string, r io.Reader, opts catalog.Options) (*catalog.Report, error) {
+	return nil, c.err
+}
+
+func (c failingCatalog) Export(ctx context.Context, kind string, w io.Writer, format string) error {
+	return c.err
+}
+
+func TestImportCatalogErrors(t *testing.T) {
+	s := newTestServer(t)
+	root := s.user(t, "root", models.RoleAdmin)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"unreadable file", &catalog.ParseError{Err: errors.New("CSV header is missing column \"name\"")}, http.StatusBadRequest, problem.CodeInvalidRequest},
+		{"database failure", errors.New("connection refused"), http.StatusInternalServerError, problem.CodeInternal},
+	}
+	for _, tt := range tests {
+		s.app.Catalog = failingCatalog{err: tt.err}
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/catalog/ingredients/import?format=csv", strings.NewReader("name\nSal\n"))
+		got := expectProblem(t, s.serve(t, req, root), tt.status, tt.code)
+		if strings.Contains(got.Detail, "connection refused") {
+			t.Errorf("%s: detail %q exposes the internal error", tt.name, got.Detail)
+		}
+	}
+}
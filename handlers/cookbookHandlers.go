@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"main.go/app"
+	"main.go/logging"
+	"main.go/middlewares"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+	"main.go/storage"
+)
+
+// @Summary      Exportar livro de receitas
+// @Description  Pedir a exportação assíncrona das receitas de um usuário (ou de uma coleção de receitas) como livro em EPUB e PDF, com sumário, índice de ingredientes e imagens. Acompanhe a situação pela rota retornada no cabeçalho Location.
+// @Tags         cookbook
+// @Accept       json
+// @Produce      json
+// @Security Token
+// @Param		 export body models.CookbookExportRequest true "Pedido de exportação"
+// @Success      202  {object}  models.CookbookExport
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      404  {object}  models.Problem  "No recipes found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Failure      503  {object}  models.Problem  "Export queue is full"
+// @Router       /cookbook/export [post]
+func CreateCookbookExportHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middlewares.GetUserID(r)
+		if !ok {
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+			return
+		}
+
+		var req models.CookbookExportRequest
+
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(&req)
+		if err != nil && err != io.EOF {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+			return
+		}
+
+		export := models.CookbookExport{
+			UserID: userID,
+			Title:  strings.TrimSpace(req.Title),
+			Status: models.ExportPending,
+		}
+
+		// Confere se há receitas para compor o livro, entre as visíveis para o usuário
+		var found bool
+		if len(req.RecipeIDs) > 0 {
+			ids := make([]string, len(req.RecipeIDs))
+			for i, id := range req.RecipeIDs {
+				ids[i] = strconv.FormatUint(uint64(id), 10)
+			}
+			export.RecipeIDs = strings.Join(ids, ",")
+			for _, id := range req.RecipeIDs {
+				if found, err = app.Recipes.Visible(r.Context(), id, userID); found || err != nil {
+					break
+				}
+			}
+		} else {
+			export.AuthorID = req.UserID
+			if export.AuthorID == 0 {
+				export.AuthorID = userID
+			}
+			var recipes []models.Recipe
+			recipes, err = app.Recipes.ListByUser(r.Context(), export.AuthorID, userID)
+			found = len(recipes) > 0
+		}
+
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying recipes", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+		if !found {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "No recipes found")
+			return
+		}
+
+		if export.Title == "" {
+			export.Title = defaultCookbookTitle(r.Context(), app, export.AuthorID)
+		}
+
+		if err := app.Exports.Create(r.Context(), &export); err != nil {
+			logging.FromContext(r.Context()).Error("Error creating cookbook export", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		// Com a fila cheia, a exportação é registrada como falha e o cliente tenta de novo mais tarde
+		if err := app.Cookbooks.Enqueue(export.ID); err != nil {
+			logging.FromContext(r.Context()).Warn("Cookbook export queue is full", "export_id", export.ID)
+			if err := app.Exports.Fail(r.Context(), &export, err.Error()); err != nil {
+				logging.FromContext(r.Context()).Error("Error updating cookbook export", "export_id", export.ID, "error", err)
+			}
+			w.Header().Set("Retry-After", "60")
+			problem.Write(w, r, http.StatusServiceUnavailable, problem.CodeExportQueueFull, "Too many cookbook exports in progress, try again later")
+			return
+		}
+
+		exportJson, err := json.Marshal(export)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/cookbook/export/%d", export.ID))
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(exportJson)
+	}
+}
+
+// @Summary      Situação da exportação
+// @Description  Buscar a situação de uma exportação de livro de receitas e, quando concluída, os endereços para baixar o EPUB e o PDF
+// @Tags         cookbook
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da exportação"
+// @Success      200  {object}  models.CookbookExport
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /cookbook/export/{id} [get]
+func GetCookbookExportHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		export, ok := getCookbookExport(app, w, r)
+		if !ok {
+			return
+		}
+
+		if export.Status == models.ExportDone {
+			export.EpubURL = fmt.Sprintf("/cookbook/export/%d/download?format=epub", export.ID)
+			export.PdfURL = fmt.Sprintf("/cookbook/export/%d/download?format=pdf", export.ID)
+		}
+
+		exportJson, err := json.Marshal(export)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(exportJson)
+	}
+}
+
+// @Summary      Baixar livro de receitas
+// @Description  Baixar o livro de receitas gerado, em EPUB ou PDF
+// @Tags         cookbook
+// @Produce      application/epub+zip,application/pdf
+// @Security Token
+// @Param		 id path int true "ID da exportação"
+// @Param		 format query string true "Formato do arquivo" Enums(epub, pdf)
+// @Success      200  {file}  file
+// @Failure      400  {object}  models.Problem  "Invalid format"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      409  {object}  models.Problem  "Export is not finished"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /cookbook/export/{id}/download [get]
+func DownloadCookbookExportHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		export, ok := getCookbookExport(app, w, r)
+		if !ok {
+			return
+		}
+
+		if export.Status != models.ExportDone {
+			problem.Write(w, r, http.StatusConflict, problem.CodeExportNotFinished, "Export is not finished")
+			return
+		}
+
+		var key, contentType, extension string
+		switch r.URL.Query().Get("format") {
+		case "epub":
+			key, contentType, extension = export.EpubKey, "application/epub+zip", "epub"
+		case "pdf":
+			key, contentType, extension = export.PdfKey, "application/pdf", "pdf"
+		default:
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid format")
+			return
+		}
+
+		reader, info, err := app.Storage.Get(r.Context(), key)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeMediaNotFound, "Media not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error reading cookbook", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+		defer reader.Close()
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"cookbook-%d.%s\"", export.ID, extension))
+		if info.Size > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+		}
+		io.Copy(w, reader)
+	}
+}
+
+// Funções privadas
+
+// getCookbookExport busca a exportação do parâmetro id pedida pelo usuário autenticado
+func getCookbookExport(app *app.App, w http.ResponseWriter, r *http.Request) (*models.CookbookExport, bool) {
+	id, _ := idParam(r, "id")
+
+	userID, ok := middlewares.GetUserID(r)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+		return nil, false
+	}
+
+	export, err := app.Exports.Get(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeExportNotFound, "Export not found")
+		} else {
+			logging.FromContext(r.Context()).Error("Error querying cookbook export", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		}
+		return nil, false
+	}
+
+	return export, true
+}
+
+// defaultCookbookTitle gera o título do livro a partir do nome do autor das receitas
+func defaultCookbookTitle(ctx context.Context, app *app.App, authorID uint) string {
+	if authorID != 0 {
+		if user, err := app.Users.Get(ctx, authorID); err == nil {
+			return "Receitas de " + user.Username
+		}
+	}
+	return "Livro de receitas"
+}
@@ -0,0 +1,36 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestCreateCookbookExport(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+
+	expectProblem(t, s.do(t, http.MethodPost, "/cookbook/export", bob, nil), http.StatusNotFound, problem.CodeNotFound)
+
+	rec := s.do(t, http.MethodPost, "/cookbook/export", alice, nil)
+	expectStatus(t, rec, http.StatusAccepted)
+	export := decode[models.CookbookExport](t, s.do(t, http.MethodGet, rec.Header().Get("Location"), alice, nil))
+	if export.Title != "Receitas de alice" || export.Status != models.ExportPending {
+		t.Fatalf("export = %+v, want a pending export titled after alice", export)
+	}
+	expectProblem(t, s.do(t, http.MethodGet, rec.Header().Get("Location"), bob, nil), http.StatusNotFound, problem.CodeExportNotFound)
+
+	// Sem workers, a fila enche com 100 pedidos e o seguinte é registrado como falha
+	for i := 2; i <= 100; i++ {
+		expectStatus(t, s.do(t, http.MethodPost, "/cookbook/export", alice, nil), http.StatusAccepted)
+	}
+	expectProblem(t, s.do(t, http.MethodPost, "/cookbook/export", alice, nil), http.StatusServiceUnavailable, problem.CodeExportQueueFull)
+	export = decode[models.CookbookExport](t, s.do(t, http.MethodGet, "/cookbook/export/101", alice, nil))
+	if export.Status != models.ExportFailed || export.Error == "" || export.FinishedAt == nil {
+		t.Fatalf("export = %+v, want a failed export", export)
+	}
+}
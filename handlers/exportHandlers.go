@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"main.go/app"
+	"main.go/logging"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/render"
+)
+
+// Formatos de exportação de receitas e seus tipos de conteúdo
+var recipeFormats = map[string]string{
+	"json":     "application/json",
+	"jsonld":   "application/ld+json",
+	"markdown": "text/markdown; charset=utf-8",
+	"html":     "text/html; charset=utf-8",
+}
+
+// Apelidos aceitos no parâmetro ?format=
+var recipeFormatAliases = map[string]string{
+	"json-ld": "jsonld",
+	"md":      "markdown",
+	"print":   "html",
+}
+
+// Tipos de conteúdo aceitos no cabeçalho Accept para cada formato, na ordem de preferência do
+// servidor usada no desempate (ex.: */* resulta em JSON e text/* em HTML)
+var recipeFormatMediaTypes = []struct {
+	format     string
+	mediaTypes []string
+}{
+	{"json", []string{"application/json"}},
+	{"jsonld", []string{"application/ld+json"}},
+	{"html", []string{"text/html"}},
+	{"markdown", []string{"text/markdown", "text/x-markdown"}},
+}
+
+// acceptRange é um tipo listado no cabeçalho Accept, com seu peso (q)
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiateRecipeFormat escolhe o formato da resposta pelo parâmetro ?format= ou, na sua ausência,
+// pelo cabeçalho Accept. Retorna ok falso quando o formato pedido não é suportado.
+func negotiateRecipeFormat(r *http.Request) (string, bool) {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		if alias, ok := recipeFormatAliases[format]; ok {
+			format = alias
+		}
+		_, ok := recipeFormats[format]
+		return format, ok
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return "json", true
+	}
+	ranges := parseAccept(accept)
+
+	// O formato de maior peso vence; no empate, vale a ordem em que o cliente listou os tipos
+	// e, por fim, a preferência do servidor. Peso 0 exclui o formato.
+	best, bestQuality, bestIndex := "", 0.0, 0
+	for _, candidate := range recipeFormatMediaTypes {
+		quality, index := 0.0, -1
+		for _, mediaType := range candidate.mediaTypes {
+			q, i := acceptQuality(ranges, mediaType)
+			if i >= 0 && (index < 0 || q > quality) {
+				quality, index = q, i
+			}
+		}
+		if index < 0 || quality <= 0 {
+			continue
+		}
+		if quality > bestQuality || (quality == bestQuality && index < bestIndex) {
+			best, bestQuality, bestIndex = candidate.format, quality, index
+		}
+	}
+
+	return best, best != ""
+}
+
+// parseAccept lê os tipos do cabeçalho Accept, ignorando os inválidos
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// acceptQuality retorna o peso do tipo de conteúdo e a posição, no cabeçalho, do tipo listado que
+// o define: o mais específico entre os que o aceitam (text/html antes de text/* e de */*).
+// A posição é -1 quando nenhum tipo listado aceita o conteúdo.
+func acceptQuality(ranges []acceptRange, mediaType string) (float64, int) {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	quality, index, specificity := 0.0, -1, -1
+	for i, ar := range ranges {
+		var s int
+		switch ar.mediaType {
+		case mediaType:
+			s = 2
+		case mainType + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			quality, index, specificity = ar.quality, i, s
+		}
+	}
+	return quality, index
+}
+
+// writeRecipe escreve a receita no formato negociado com o cliente
+func writeRecipe(app *app.App, w http.ResponseWriter, r *http.Request, recipe *models.Recipe) {
+	format, ok := negotiateRecipeFormat(r)
+	if !ok {
+		problem.Write(w, r, http.StatusNotAcceptable, problem.CodeNotAcceptable, "Supported formats are json, jsonld, markdown and html")
+		return
+	}
+
+	var body []byte
+	var err error
+
+	localizeRecipes(app, w, r, recipe)
+
+	if format == "json" {
+		signRecipeImages(app, recipe)
+		setForkCounts(app, r, recipe)
+		body, err = json.Marshal(recipe)
+	} else {
+		// Os documentos exportados são guardados e indexados: as imagens apontam para a rota estável da
+		// receita, e não para as URLs assinadas, que expiram
+		imageIDs := make(map[string]uint, len(recipe.Images))
+		for _, image := range recipe.Images {
+			imageIDs[image.Key] = image.ID
+		}
+		view := render.NewRecipeView(*recipe, recipeAuthor(app, r, recipe), func(key string) string {
+			return absoluteURL(app, r, fmt.Sprintf("/recipe/%d/images/%d/file", recipe.ID, imageIDs[key]))
+		})
+
+		switch format {
+		case "jsonld":
+			body, err = render.JSONLD(view)
+		case "markdown":
+			body, err = app.Renderer.Markdown(view)
+		case "html":
+			body, err = app.Renderer.HTML(view)
+		}
+	}
+
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error rendering recipe", "format", format, "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", recipeFormats[format])
+	w.Header().Add("Vary", "Accept")
+	w.Write(body)
+}
+
+// recipeAuthor retorna o nome de usuário do autor da receita, ou vazio se não for encontrado
+func recipeAuthor(app *app.App, r *http.Request, recipe *models.Recipe) string {
+	user, err := app.Users.Get(r.Context(), recipe.UserID)
+	if err != nil {
+		return ""
+	}
+	return user.Username
+}
+
+// absoluteURL converte um caminho da API em URL absoluta. Usa o endereço público configurado ou,
+// sem ele, o da requisição; os cabeçalhos X-Forwarded-* só valem atrás de um proxy confiável
+func absoluteURL(app *app.App, r *http.Request, path string) string {
+	if app.Config.Server.PublicURL != "" {
+		return strings.TrimRight(app.Config.Server.PublicURL, "/") + path
+	}
+
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if app.Config.Server.TrustProxy {
+		if proto := strings.ToLower(r.Header.Get("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+			host, _, _ = strings.Cut(forwarded, ",")
+			host = strings.TrimSpace(host)
+		}
+	}
+	return scheme + "://" + host + path
+}
@@ -0,0 +1,113 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestRecipeFormatNegotiation(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"text/*", "text/html"},
+		{"text/html, application/json", "text/html"},
+		{"text/html;q=0.5, application/json", "application/json"},
+		{"text/markdown;q=0.9, text/html;q=0.8, */*;q=0.1", "text/markdown"},
+		{"application/ld+json;q=0.8, application/*;q=0.9", "application/json"},
+		{"text/html;q=0, */*", "application/json"},
+		{"application/json;q=0, application/ld+json;q=0, */*;q=0.5", "text/html"},
+		{"text/x-markdown;q=invalid, text/x-markdown;q=0.3, image/png", "text/markdown"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/recipe/%d", cake), nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+
+		if contentType := rec.Header().Get("Content-Type"); rec.Code != http.StatusOK || !strings.HasPrefix(contentType, tt.want) {
+			t.Errorf("Accept %q: %d %s, want %s", tt.accept, rec.Code, contentType, tt.want)
+		}
+	}
+
+	for _, accept := range []string{"image/png", "*/*;q=0", "application/json;q=0, text/*;q=0, application/ld+json;q=0"} {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/recipe/%d", cake), nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+
+		expectProblem(t, rec, http.StatusNotAcceptable, problem.CodeNotAcceptable)
+	}
+}
+
+func TestRecipeJSONLDImageURLs(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+	draft := s.recipe(t, alice, "Rascunho", models.VisibilityDraft)
+
+	image := decode[models.RecipeImage](t, s.upload(t, cake, alice, 10, 10))
+	path := fmt.Sprintf("/recipe/%d/images/%d/file", cake, image.ID)
+
+	jsonld := func() string {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/recipe/%d", cake), nil)
+		req.Header.Set("Accept", "application/ld+json")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "evil.example")
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		expectStatus(t, rec, http.StatusOK)
+
+		var doc struct {
+			Image []string `json:"image"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("decoding JSON-LD: %v", err)
+		}
+		if len(doc.Image) != 1 {
+			t.Fatalf("image = %v, want one URL", doc.Image)
+		}
+		return doc.Image[0]
+	}
+
+	// Sem proxy confiável, os cabeçalhos X-Forwarded-* são ignorados
+	if url := jsonld(); url != "http://example.com"+path {
+		t.Errorf("image URL = %q, want the request host and the stable image route", url)
+	}
+
+	s.app.Config.Server.TrustProxy = true
+	if url := jsonld(); url != "https://evil.example"+path {
+		t.Errorf("image URL behind a trusted proxy = %q, want the forwarded host", url)
+	}
+
+	s.app.Config.Server.PublicURL = "https://api.example.com/"
+	if url := jsonld(); url != "https://api.example.com"+path {
+		t.Errorf("image URL with PUBLIC_URL = %q, want the configured base", url)
+	}
+
+	// A rota estável dispensa a assinatura e segue a visibilidade da receita
+	rec := s.do(t, http.MethodGet, path, 0, nil)
+	expectStatus(t, rec, http.StatusOK)
+	if contentType, cache := rec.Header().Get("Content-Type"), rec.Header().Get("Cache-Control"); contentType != "image/png" || cache != "public, max-age=3600" {
+		t.Errorf("image file: Content-Type %q, Cache-Control %q", contentType, cache)
+	}
+	expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/images/%d/file", draft, image.ID), 0, nil), http.StatusNotFound, problem.CodeRecipeNotFound)
+	expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/images/%d/file", draft, image.ID), alice, nil), http.StatusNotFound, problem.CodeImageNotFound)
+}
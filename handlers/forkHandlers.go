@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"main.go/app"
+	"main.go/history"
+	"main.go/logging"
+	"main.go/metrics"
+	"main.go/middlewares"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+)
+
+// @Summary      Copiar receita (fork)
+// @Description  Copiar a receita e seus ingredientes para o usuário autenticado, mantendo a referência à receita original para atribuição. A cópia começa como rascunho. Sem nome informado, a cópia usa o nome da original, com um sufixo numérico se o usuário já tiver uma receita com esse nome. Com substitute (ex.: vegan), os ingredientes que têm substituição para o motivo são trocados, gerando uma versão da receita (ex.: "Bolo (versão vegana)").
+// @Tags         recipe
+// @Accept       json
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita original"
+// @Param		 fork body models.RecipeForkRequest false "Opções da cópia"
+// @Success      201  {object}  models.Recipe
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      404  {object}  models.Problem  "Recipe not found"
+// @Failure      409  {object}  models.Problem  "Recipe name is already in use"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/fork [post]
+func ForkRecipeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		// O corpo é opcional
+		var req models.RecipeForkRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil && err != io.EOF {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+			return
+		}
+
+		if req.Substitute != "" && !slices.Contains(models.SubstitutionReasons, req.Substitute) {
+			problem.WriteValidation(w, r, []models.FieldError{substitutionReasonError("substitute")})
+			return
+		}
+
+		userID, _ := middlewares.GetUserID(r)
+
+		opts := repository.ForkRecipeOptions{
+			UserID:     userID,
+			Name:       strings.TrimSpace(req.Name),
+			Substitute: req.Substitute,
+		}
+		if req.Substitute != "" {
+			opts.NameSuffix = fmt.Sprintf(" (versão %s)", substitutionLabels[req.Substitute])
+		}
+
+		fork, err := app.Recipes.Fork(r.Context(), id, opts)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
+			case errors.Is(err, repository.ErrConflict):
+				problem.Write(w, r, http.StatusConflict, problem.CodeNameInUse, "Recipe name is already in use")
+			default:
+				logging.FromContext(r.Context()).Error("Error forking recipe", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+		metrics.RecipesCreated.WithLabelValues(metrics.RecipeForked).Inc()
+
+		forkJson, err := json.Marshal(fork)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/recipe/%d", fork.ID))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(forkJson)
+	}
+}
+
+// @Summary      Árvore de cópias da receita
+// @Description  Listar as cópias (forks) feitas a partir da receita e, recursivamente, as cópias dessas cópias. De outros usuários, aparecem apenas as cópias publicadas.
+// @Tags         recipe
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Success      200  {object}  models.RecipeForkNode
+// @Failure      404  {object}  models.Problem  "Recipe not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/forks [get]
+func GetRecipeForksHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+		userID, _ := middlewares.GetUserID(r)
+
+		recipe, ok := getVisibleRecipe(app, w, r, id, "Recipe not found")
+		if !ok {
+			return
+		}
+
+		tree, err := forkTree(r.Context(), app, *recipe, userID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying recipe forks", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		treeJson, err := json.Marshal(tree)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(treeJson)
+	}
+}
+
+// @Summary      Comparar cópia com a receita original
+// @Description  Comparar o estado atual da cópia (fork) com o estado atual da receita original: campos alterados, modo de preparo linha a linha e ingredientes adicionados, removidos ou alterados
+// @Tags         recipe
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da cópia"
+// @Success      200  {object}  models.RecipeForkDiff
+// @Failure      404  {object}  models.Problem  "Recipe not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/parent/diff [get]
+func DiffRecipeParentHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		recipe, ok := getVisibleRecipe(app, w, r, id, "Recipe not found")
+		if !ok {
+			return
+		}
+
+		// Receitas que não são cópias, ou cuja original foi removida, não têm com o que comparar
+		if recipe.ParentID == nil {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe is not a fork")
+			return
+		}
+
+		parent, ok := getVisibleRecipe(app, w, r, *recipe.ParentID, "Parent recipe not found")
+		if !ok {
+			return
+		}
+
+		// O autor sempre difere entre a original e a cópia e não faz parte da comparação
+		snapshot := history.NewSnapshot(*recipe)
+		snapshot.UserID = parent.UserID
+
+		diff := history.Diff(history.NewSnapshot(*parent), snapshot)
+
+		diffJson, err := json.Marshal(models.RecipeForkDiff{
+			ParentID:     *recipe.ParentID,
+			RecipeID:     recipe.ID,
+			Fields:       diff.Fields,
+			Instructions: diff.Instructions,
+			Ingredients:  diff.Ingredients,
+			Reordered:    diff.Reordered,
+		})
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(diffJson)
+	}
+}
+
+// Funções privadas
+
+// substitutionLabels são os nomes, usados no nome das versões das receitas, dos motivos das
+// substituições
+var substitutionLabels = map[string]string{
+	models.ReasonVegan:      "vegana",
+	models.ReasonVegetarian: "vegetariana",
+	models.ReasonDairyFree:  "sem lactose",
+	models.ReasonGlutenFree: "sem glúten",
+	models.ReasonEggFree:    "sem ovo",
+}
+
+// forkTree monta a árvore de cópias a partir da receita, buscando um nível da árvore por consulta.
+// Apenas as cópias listadas para o usuário entram na árvore.
+func forkTree(ctx context.Context, app *app.App, root models.Recipe, userID uint) (*models.RecipeForkNode, error) {
+	children := map[uint][]models.Recipe{}
+	userIDs := []uint{root.UserID}
+
+	for level := []uint{root.ID}; len(level) > 0; {
+		forks, err := app.Recipes.Forks(ctx, level, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		level = nil
+		for _, fork := range forks {
+			children[*fork.ParentID] = append(children[*fork.ParentID], fork)
+			userIDs = append(userIDs, fork.UserID)
+			level = append(level, fork.ID)
+		}
+	}
+
+	names, err := app.Users.Usernames(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := forkNode(root, children, names)
+	return &tree, nil
+}
+
+func forkNode(recipe models.Recipe, children map[uint][]models.Recipe, names map[uint]string) models.RecipeForkNode {
+	node := models.RecipeForkNode{
+		ID:     recipe.ID,
+		Name:   recipe.Name,
+		UserID: recipe.UserID,
+		Author: names[recipe.UserID],
+		Forks:  []models.RecipeForkNode{},
+	}
+	for _, fork := range children[recipe.ID] {
+		node.Forks = append(node.Forks, forkNode(fork, children, names))
+	}
+	return node
+}
+
+// setForkCounts preenche o número de cópias publicadas feitas diretamente a partir de cada receita
+func setForkCounts(app *app.App, r *http.Request, recipes ...*models.Recipe) {
+	var ids []uint
+	for _, recipe := range recipes {
+		ids = append(ids, recipe.ID)
+	}
+
+	forks, err := app.Recipes.ForkCounts(r.Context(), ids)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error querying recipe forks", "error", err)
+		return
+	}
+
+	for _, recipe := range recipes {
+		recipe.ForkCount = forks[recipe.ID]
+	}
+}
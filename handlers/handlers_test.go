@@ -0,0 +1,179 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"main.go/app"
+	"main.go/config"
+	"main.go/cookbook"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/render"
+	"main.go/repository"
+	"main.go/routes"
+	"main.go/storage"
+	"main.go/trash"
+)
+
+const testSecret = "handlers-test-secret-0123456789abcdef"
+
+// testServer monta as rotas da API sobre os repositórios em memória
+type testServer struct {
+	app    *app.App
+	router http.Handler
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	renderer, err := render.NewRenderer("")
+	if err != nil {
+		t.Fatalf("loading templates: %v", err)
+	}
+
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("creating store: %v", err)
+	}
+
+	memory := repository.NewMemory()
+	app := &app.App{
+		Config:        &config.Config{Secret: testSecret},
+		Users:         memory.Users(),
+		Recipes:       memory.Recipes(),
+		Ingredients:   memory.Ingredients(),
+		Substitutions: memory.Substitutions(),
+		Images:        memory.Images(),
+		Exports:       memory.Exports(),
+		Storage:       store,
+		Renderer:      renderer,
+		Signer:        storage.NewURLSigner(testSecret, "http://localhost", time.Hour),
+		Cookbooks:     cookbook.NewExporter(nil, store, 1),
+		Trash:         trash.NewPurger(nil, nil, 0),
+	}
+
+	router := chi.NewRouter()
+	routes.RegisterRoutes(router, app)
+	return &testServer{app: app, router: router}
+}
+
+// user cadastra o usuário com o papel informado e retorna o ID
+func (s *testServer) user(t *testing.T, username string, role string) uint {
+	t.Helper()
+
+	user := models.User{Username: username, Email: username + "@example.com", Password: "secret", Role: role}
+	if err := s.app.Users.Create(context.Background(), &user); err != nil {
+		t.Fatalf("creating user %s: %v", username, err)
+	}
+	return user.ID
+}
+
+// ingredient cadastra o ingrediente e retorna o ID
+func (s *testServer) ingredient(t *testing.T, name string) uint {
+	t.Helper()
+
+	ingredient := models.Ingredient{Name: name}
+	if err := s.app.Ingredients.Create(context.Background(), &ingredient); err != nil {
+		t.Fatalf("creating ingredient %s: %v", name, err)
+	}
+	return ingredient.ID
+}
+
+// recipe cadastra a receita do usuário com os ingredientes informados
+func (s *testServer) recipe(t *testing.T, userID uint, name string, visibility string, ingredientIDs ...uint) uint {
+	t.Helper()
+
+	var ingredients []models.RecipeIngredientRequest
+	for _, id := range ingredientIDs {
+		ingredients = append(ingredients, models.RecipeIngredientRequest{IngredientID: id, Quantity: "1"})
+	}
+
+	recipe := models.Recipe{UserID: userID, Name: name, Instructions: "Misture tudo.", Visibility: visibility}
+	opts := repository.SaveRecipeOptions{Ingredients: ingredients, AuthorID: userID}
+	if err := s.app.Recipes.Save(context.Background(), &recipe, opts); err != nil {
+		t.Fatalf("creating recipe %s: %v", name, err)
+	}
+	return recipe.ID
+}
+
+// do envia a requisição autenticada como o usuário (sem autenticação com userID 0), com o corpo
+// em JSON quando informado
+func (s *testServer) do(t *testing.T, method string, path string, userID uint, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			t.Fatalf("encoding body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, path, &reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return s.serve(t, req, userID)
+}
+
+// serve envia a requisição autenticada como o usuário (sem autenticação com userID 0)
+func (s *testServer) serve(t *testing.T, req *http.Request, userID uint) *httptest.ResponseRecorder {
+	t.Helper()
+
+	if userID != 0 {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": userID,
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		signed, err := token.SignedString([]byte(testSecret))
+		if err != nil {
+			t.Fatalf("signing token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+signed)
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// expectStatus falha o teste quando a resposta não tem o status esperado
+func expectStatus(t *testing.T, rec *httptest.ResponseRecorder, status int) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, status, rec.Body.String())
+	}
+}
+
+// expectProblem confere o status e o código da resposta de erro
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) models.Problem {
+	t.Helper()
+
+	expectStatus(t, rec, status)
+	if contentType := rec.Header().Get("Content-Type"); contentType != problem.ContentType {
+		t.Fatalf("Content-Type = %q, want %s", contentType, problem.ContentType)
+	}
+	got := decode[models.Problem](t, rec)
+	if got.Code != code {
+		t.Fatalf("code = %q, want %q", got.Code, code)
+	}
+	return got
+}
+
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+
+	var value T
+	if err := json.Unmarshal(rec.Body.Bytes(), &value); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body.String(), err)
+	}
+	return value
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"main.go/app"
+	"main.go/health"
+	"main.go/models"
+	"main.go/problem"
+)
+
+// @Summary      Processo no ar
+// @Description  Responder enquanto o processo estiver no ar, sem verificar as dependências. Usado pelo orquestrador para reiniciar o processo travado.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  models.Health
+// @Router       /healthz [get]
+func HealthzHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealthJSON(w, r, http.StatusOK, models.Health{Status: models.HealthOK})
+	}
+}
+
+// @Summary      Pronto para atender
+// @Description  Verificar a conexão com o banco, as migrações pendentes e as demais dependências registradas. Usado pelo orquestrador para decidir se o processo recebe requisições.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  models.Readiness
+// @Failure      503  {object}  models.Readiness
+// @Router       /readyz [get]
+func ReadyzHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		readiness := app.Health.Run(r.Context())
+
+		status := http.StatusOK
+		if readiness.Status != models.HealthOK {
+			status = http.StatusServiceUnavailable
+		}
+		writeHealthJSON(w, r, status, readiness)
+	}
+}
+
+// @Summary      Versão
+// @Description  Mostrar o commit, a data da compilação e a versão do Go do executável em execução
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  models.BuildInfo
+// @Router       /version [get]
+func VersionHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeHealthJSON(w, r, http.StatusOK, health.Build())
+	}
+}
+
+// Funções privadas
+
+// writeHealthJSON escreve a resposta das verificações, que não deve ser guardada em cache
+func writeHealthJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	healthJson, err := json.Marshal(v)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(healthJson)
+}
@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"main.go/app"
+	"main.go/logging"
+	"main.go/media"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+	"main.go/storage"
+)
+
+// @Summary      Enviar imagem da receita
+// @Description  Enviar imagem (JPEG, PNG, GIF ou WebP, até 5MB e 40 megapixels) para a receita ou para um passo do modo de preparo. Miniaturas são geradas automaticamente.
+// @Tags         recipe_images
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 image formData file true "Arquivo da imagem"
+// @Param		 step formData int false "Número do passo do modo de preparo ilustrado pela imagem"
+// @Success      201  {object}  models.RecipeImage
+// @Failure      400  {object}  models.Problem  "Invalid image"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      413  {object}  models.Problem  "Image or its dimensions are too large"
+// @Failure      415  {object}  models.Problem  "Unsupported image type"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/images [post]
+func UploadRecipeImageHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipe, ok := getEditableRecipe(app, w, r)
+		if !ok {
+			return
+		}
+
+		// Limita o tamanho do corpo da requisição, com folga para os demais campos do formulário
+		r.Body = http.MaxBytesReader(w, r.Body, media.MaxImageSize+1<<20)
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				problem.Write(w, r, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, "Image is too large")
+				return
+			}
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidRequest, "Invalid multipart form")
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		// Passo do modo de preparo ilustrado pela imagem (opcional)
+		var step *int
+		if stepStr := r.FormValue("step"); stepStr != "" {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 || n > len(recipe.Steps()) {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid step")
+				return
+			}
+			step = &n
+		}
+
+		file, _, err := r.FormFile("image")
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidRequest, "Missing image file")
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, media.MaxImageSize+1))
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidRequest, "Invalid image")
+			return
+		}
+
+		// Valida o conteúdo real do arquivo e gera as miniaturas
+		img, err := media.Validate(data)
+		if err != nil {
+			switch {
+			case errors.Is(err, media.ErrTooLarge):
+				problem.Write(w, r, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, "Image is too large")
+			case errors.Is(err, media.ErrTooManyPixels):
+				problem.Write(w, r, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, "Image dimensions are too large")
+			case errors.Is(err, media.ErrUnsupportedType):
+				problem.Write(w, r, http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, "Unsupported image type")
+			default:
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidRequest, "Invalid image")
+			}
+			return
+		}
+
+		thumbnails, err := media.Thumbnails(img)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error generating thumbnails", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		image, err := storeRecipeImage(app, r, recipe.ID, step, img, thumbnails)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error storing image", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		signImageURLs(app, image)
+
+		imageJson, err := json.Marshal(image)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write(imageJson)
+	}
+}
+
+// @Summary      Buscar imagens da receita
+// @Description  Buscar imagens da receita e dos passos, com URLs assinadas e temporárias
+// @Tags         recipe_images
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Success      200  {array}   models.RecipeImage
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/images [get]
+func GetRecipeImagesHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		recipe, ok := getVisibleRecipe(app, w, r, id, "Recipe not found")
+		if !ok {
+			return
+		}
+
+		signRecipeImages(app, recipe)
+
+		imagesJson, err := json.Marshal(recipe.Images)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(imagesJson)
+	}
+}
+
+// @Summary      Deletar imagem da receita
+// @Description  Deletar imagem da receita e suas miniaturas
+// @Tags         recipe_images
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 image_id path int true "ID da imagem"
+// @Success      200  {string}   string "Image deleted!"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/images/{image_id} [delete]
+func DeleteRecipeImageHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipe, ok := getEditableRecipe(app, w, r)
+		if !ok {
+			return
+		}
+
+		imageID, _ := idParam(r, "image_id")
+
+		image, err := app.Images.Get(r.Context(), recipe.ID, imageID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeImageNotFound, "Image not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error querying image", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		if err := app.Images.Delete(r.Context(), image.ID); err != nil {
+			logging.FromContext(r.Context()).Error("Error deleting image", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		deleteImageBlobs(app, r, []models.RecipeImage{*image})
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Image deleted!"))
+	}
+}
+
+// @Summary      Acessar arquivo de mídia
+// @Description  Servir arquivo guardado (imagem ou miniatura) através de uma URL assinada e temporária
+// @Tags         recipe_images
+// @Produce      image/jpeg,image/png,image/gif,image/webp
+// @Param		 key path string true "Chave do arquivo"
+// @Param		 expires query int true "Validade da URL (Unix timestamp)"
+// @Param		 signature query string true "Assinatura da URL"
+// @Success      200  {file}  file
+// @Failure      403  {object}  models.Problem  "Invalid or expired signature"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /media/{key} [get]
+func ServeMediaHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := chi.URLParam(r, "*")
+
+		query := r.URL.Query()
+		if !app.Signer.Verify(key, query.Get("expires"), query.Get("signature")) {
+			problem.Write(w, r, http.StatusForbidden, problem.CodeInvalidSignature, "Invalid or expired signature")
+			return
+		}
+
+		// Arquivos privados: somente o navegador que recebeu a URL pode guardar em cache
+		serveBlob(app, w, r, key, "private, max-age=300")
+	}
+}
+
+// @Summary      Acessar arquivo da imagem da receita
+// @Description  Servir o arquivo original da imagem por uma URL estável, usada nos documentos exportados (ex.: JSON-LD). A imagem segue a visibilidade da receita.
+// @Tags         recipe_images
+// @Produce      image/jpeg,image/png,image/gif,image/webp
+// @Param		 id path int true "ID da receita"
+// @Param		 image_id path int true "ID da imagem"
+// @Success      200  {file}  file
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/images/{image_id}/file [get]
+func ServeRecipeImageHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		recipe, ok := getVisibleRecipe(app, w, r, id, "Recipe not found")
+		if !ok {
+			return
+		}
+
+		imageID, _ := idParam(r, "image_id")
+
+		image, err := app.Images.Get(r.Context(), recipe.ID, imageID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeImageNotFound, "Image not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error querying image", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		// Somente as imagens das receitas publicadas podem ficar em caches compartilhados
+		cacheControl := "private, max-age=300"
+		if recipe.Visibility == models.VisibilityPublished {
+			cacheControl = "public, max-age=3600"
+		}
+		serveBlob(app, w, r, image.Key, cacheControl)
+	}
+}
+
+// Funções privadas
+
+// serveBlob escreve o arquivo guardado com a chave informada, ou o problema correspondente
+func serveBlob(app *app.App, w http.ResponseWriter, r *http.Request, key string, cacheControl string) {
+	reader, info, err := app.Storage.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeMediaNotFound, "Media not found")
+			return
+		}
+		logging.FromContext(r.Context()).Error("Error reading media", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return
+	}
+	defer reader.Close()
+
+	if info.ContentType != "" {
+		w.Header().Set("Content-Type", info.ContentType)
+	}
+	if info.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	io.Copy(w, reader)
+}
+
+// storeRecipeImage guarda a imagem original e as miniaturas no armazenamento e registra no banco.
+// Se alguma etapa falhar, os arquivos já enviados são removidos.
+func storeRecipeImage(app *app.App, r *http.Request, recipeID uint, step *int, img *media.Image, thumbnails []media.Thumbnail) (*models.RecipeImage, error) {
+	ctx := r.Context()
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("recipes/%d/%s", recipeID, name)
+
+	image := models.RecipeImage{
+		RecipeID:    recipeID,
+		Step:        step,
+		Key:         prefix + img.Extension,
+		ContentType: img.ContentType,
+		Size:        int64(len(img.Data)),
+		Width:       img.Width,
+		Height:      img.Height,
+	}
+
+	var stored []string
+	cleanup := func() {
+		for _, key := range stored {
+			app.Storage.Delete(ctx, key)
+		}
+	}
+
+	if err := app.Storage.Put(ctx, image.Key, bytes.NewReader(img.Data), image.Size, image.ContentType); err != nil {
+		return nil, err
+	}
+	stored = append(stored, image.Key)
+
+	for _, thumbnail := range thumbnails {
+		key := fmt.Sprintf("%s_%d%s", prefix, thumbnail.Size, thumbnail.Extension)
+		if err := app.Storage.Put(ctx, key, bytes.NewReader(thumbnail.Data), int64(len(thumbnail.Data)), thumbnail.ContentType); err != nil {
+			cleanup()
+			return nil, err
+		}
+		stored = append(stored, key)
+
+		image.Thumbnails = append(image.Thumbnails, models.RecipeImageThumbnail{
+			Size:   thumbnail.Size,
+			Key:    key,
+			Width:  thumbnail.Width,
+			Height: thumbnail.Height,
+		})
+	}
+
+	if err := app.Images.Create(ctx, &image); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// deleteImageBlobs remove do armazenamento os arquivos das imagens informadas e de suas miniaturas
+func deleteImageBlobs(app *app.App, r *http.Request, images []models.RecipeImage) {
+	for _, image := range images {
+		if err := app.Storage.Delete(r.Context(), image.Key); err != nil {
+			logging.FromContext(r.Context()).Error("Error deleting media", "key", image.Key, "error", err)
+		}
+		for _, thumbnail := range image.Thumbnails {
+			if err := app.Storage.Delete(r.Context(), thumbnail.Key); err != nil {
+				logging.FromContext(r.Context()).Error("Error deleting media", "key", thumbnail.Key, "error", err)
+			}
+		}
+	}
+}
+
+// signRecipeImages preenche as URLs assinadas de todas as imagens carregadas da receita
+func signRecipeImages(app *app.App, recipe *models.Recipe) {
+	for i := range recipe.Images {
+		signImageURLs(app, &recipe.Images[i])
+	}
+}
+
+// signImageURLs preenche as URLs assinadas da imagem e de suas miniaturas
+func signImageURLs(app *app.App, image *models.RecipeImage) {
+	image.URL = app.Signer.SignedURL(image.Key)
+	for i := range image.Thumbnails {
+		image.Thumbnails[i].URL = app.Signer.SignedURL(image.Thumbnails[i].Key)
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
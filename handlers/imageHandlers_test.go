@@ -0,0 +1,67 @@
+package handlers_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+// upload envia a imagem PNG com as dimensões informadas para a receita
+func (s *testServer) upload(t *testing.T, recipeID uint, userID uint, width, height int) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("image", "bolo.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, image.NewNRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/recipe/%d/images", recipeID), &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return s.serve(t, req, userID)
+}
+
+func TestRecipeImages(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+	pie := s.recipe(t, alice, "Torta", models.VisibilityPublished)
+
+	expectProblem(t, s.upload(t, cake, bob, 10, 10), http.StatusForbidden, problem.CodeForbidden)
+
+	rec := s.upload(t, cake, alice, 600, 300)
+	expectStatus(t, rec, http.StatusCreated)
+	uploaded := decode[models.RecipeImage](t, rec)
+	if uploaded.Width != 600 || len(uploaded.Thumbnails) != 2 || uploaded.URL == "" {
+		t.Fatalf("image = %+v, want 600px wide with 2 signed thumbnails", uploaded)
+	}
+
+	images := decode[[]models.RecipeImage](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/images", cake), bob, nil))
+	if len(images) != 1 || images[0].ID != uploaded.ID || len(images[0].Thumbnails) != 2 {
+		t.Fatalf("images = %+v, want the uploaded image", images)
+	}
+
+	// A imagem só é encontrada pela receita à qual pertence
+	expectProblem(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/%d/images/%d", pie, uploaded.ID), alice, nil), http.StatusNotFound, problem.CodeImageNotFound)
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/%d/images/%d", cake, uploaded.ID), alice, nil), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/%d/images/%d", cake, uploaded.ID), alice, nil), http.StatusNotFound, problem.CodeImageNotFound)
+
+	images = decode[[]models.RecipeImage](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/images", cake), alice, nil))
+	if len(images) != 0 {
+		t.Fatalf("images = %+v, want none", images)
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"main.go/app"
+	"main.go/importer"
+	"main.go/logging"
+	"main.go/middlewares"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+)
+
+// Tamanho máximo do documento (JSON-LD ou HTML) aceito na importação
+const maxImportSize = 2 << 20
+
+// @Summary      Importar receita
+// @Description  Importar receita a partir de um documento schema.org Recipe em JSON-LD ou do HTML de uma página (sem buscar URLs). Os ingredientes são associados aos já cadastrados, e é retornado um rascunho da receita, ainda não salvo, com as linhas que não puderam ser interpretadas. Nada é cadastrado: os ingredientes sem correspondência ficam no rascunho sem ID, listados em unmatched_ingredients, e podem ser cadastrados ao criar a receita com create_missing_ingredients.
+// @Tags         recipe
+// @Accept       json,html
+// @Produce      json
+// @Security Token
+// @Param		 document body string true "Documento JSON-LD ou página HTML"
+// @Success      200  {object}  models.RecipeImportResult
+// @Failure      400  {object}  models.Problem  "Invalid document"
+// @Failure      413  {object}  models.Problem  "Document is too large"
+// @Failure      422  {object}  models.Problem  "No schema.org Recipe found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/import [post]
+func ImportRecipeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middlewares.GetUserID(r)
+		if !ok {
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+		if err != nil {
+			problem.Write(w, r, http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge, "Document is too large")
+			return
+		}
+
+		// Escolhe o formato pelo Content-Type, ou pelo conteúdo quando ele não for informado
+		var draft *importer.Draft
+		contentType := r.Header.Get("Content-Type")
+		trimmed := bytes.TrimSpace(body)
+		if strings.Contains(contentType, "json") || (!strings.Contains(contentType, "html") && len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')) {
+			draft, err = importer.ParseJSONLD(body)
+		} else {
+			draft, err = importer.ParseHTML(body)
+		}
+
+		if err != nil {
+			if errors.Is(err, importer.ErrNoRecipe) {
+				problem.Write(w, r, http.StatusUnprocessableEntity, problem.CodeNoRecipeFound, "No schema.org Recipe found")
+				return
+			}
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidRequest, "Invalid document")
+			return
+		}
+
+		result, err := buildImportedRecipe(r.Context(), app, draft, userID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error importing recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		resultJson, err := json.Marshal(result)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(resultJson)
+	}
+}
+
+// Funções privadas
+
+// buildImportedRecipe converte os dados extraídos em um rascunho de receita, associando cada linha
+// de ingrediente a um ingrediente cadastrado e registrando o que não foi encontrado ou interpretado
+func buildImportedRecipe(ctx context.Context, app *app.App, draft *importer.Draft, userID uint) (*models.RecipeImportResult, error) {
+	result := &models.RecipeImportResult{
+		Recipe: models.Recipe{
+			UserID:       userID,
+			Name:         draft.Name,
+			Instructions: strings.Join(draft.Instructions, "\n"),
+		},
+		UnmatchedIngredients: []string{},
+		Unparsed:             []models.RecipeImportIssue{},
+	}
+
+	report := func(field string, line string, reason string) {
+		result.Unparsed = append(result.Unparsed, models.RecipeImportIssue{Field: field, Line: line, Reason: reason})
+	}
+
+	if draft.Name == "" {
+		report("name", "", "recipe name is missing")
+	}
+	if len(draft.Instructions) == 0 {
+		report("instructions", "", "recipe instructions are missing")
+	}
+
+	if draft.Yield != "" {
+		if servings, ok := importer.ParseServings(draft.Yield); ok {
+			result.Recipe.Servings = servings
+		} else {
+			report("servings", draft.Yield, "number of servings not recognized")
+		}
+	}
+
+	times := []struct {
+		field  string
+		value  string
+		target *int
+	}{
+		{"prep_time", draft.PrepTime, &result.Recipe.PrepTime},
+		{"cook_time", draft.CookTime, &result.Recipe.CookTime},
+		{"total_time", draft.TotalTime, &result.Recipe.TotalTime},
+	}
+	for _, t := range times {
+		if t.value == "" {
+			continue
+		}
+		if minutes, ok := importer.ParseDuration(t.value); ok {
+			*t.target = minutes
+		} else {
+			report(t.field, t.value, "ISO 8601 duration not recognized")
+		}
+	}
+
+	// Posição de cada ingrediente no rascunho, para juntar linhas repetidas do mesmo ingrediente; os
+	// não cadastrados são identificados pelo nome
+	positions := map[uint]int{}
+	unmatched := map[string]int{}
+
+	for _, line := range draft.Ingredients {
+		parsed, ok := importer.ParseIngredientLine(line)
+		if !ok {
+			report("ingredients", line, "quantity not recognized")
+			continue
+		}
+
+		ingredient, err := app.Ingredients.Find(ctx, parsed.Name)
+		if errors.Is(err, repository.ErrNotFound) {
+			ingredient, err = &models.Ingredient{Name: parsed.Name}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		i, ok := positions[ingredient.ID]
+		if ingredient.ID == 0 {
+			i, ok = unmatched[strings.ToLower(ingredient.Name)]
+		}
+		if ok {
+			item := &result.Recipe.IngredientsRecipes[i]
+			if parsed.Quantity != "" {
+				item.Quantity = strings.TrimPrefix(item.Quantity+" + "+parsed.Quantity, " + ")
+			}
+			continue
+		}
+
+		if ingredient.ID == 0 {
+			unmatched[strings.ToLower(ingredient.Name)] = len(result.Recipe.IngredientsRecipes)
+			result.UnmatchedIngredients = append(result.UnmatchedIngredients, ingredient.Name)
+		} else {
+			positions[ingredient.ID] = len(result.Recipe.IngredientsRecipes)
+		}
+		result.Recipe.IngredientsRecipes = append(result.Recipe.IngredientsRecipes, models.IngredientsRecipes{
+			IngredientID: ingredient.ID,
+			Quantity:     parsed.Quantity,
+			Position:     len(result.Recipe.IngredientsRecipes),
+			ToTaste:      parsed.ToTaste,
+			Ingredient:   *ingredient,
+		})
+	}
+
+	return result, nil
+}
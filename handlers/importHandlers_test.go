@@ -0,0 +1,39 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main.go/models"
+)
+
+func TestImportRecipeDoesNotCreateIngredients(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	flour := s.ingredient(t, "Farinha")
+
+	document := `{"@context": "https://schema.org", "@type": "Recipe", "name": "Bolo",
+		"recipeIngredient": ["2 xícaras de farinha", "1 fava tonka", "2 Fava Tonka"],
+		"recipeInstructions": "Misture tudo."}`
+	req := httptest.NewRequest(http.MethodPost, "/recipe/import", strings.NewReader(document))
+	req.Header.Set("Content-Type", "application/ld+json")
+	rec := s.serve(t, req, alice)
+	expectStatus(t, rec, http.StatusOK)
+
+	result := decode[models.RecipeImportResult](t, rec)
+	lines := result.Recipe.IngredientsRecipes
+	if len(lines) != 2 || lines[0].IngredientID != flour || lines[1].IngredientID != 0 || lines[1].Quantity != "1 + 2" {
+		t.Fatalf("ingredients = %+v, want the flour and one unmatched line", lines)
+	}
+	if len(result.UnmatchedIngredients) != 1 {
+		t.Fatalf("unmatched = %q, want one name", result.UnmatchedIngredients)
+	}
+
+	// A prévia não cadastra nada no catálogo
+	ingredients := decode[[]models.Ingredient](t, s.do(t, http.MethodGet, "/ingredient", alice, nil))
+	if len(ingredients) != 1 {
+		t.Fatalf("ingredients = %+v, want only the flour", ingredients)
+	}
+}
@@ -2,51 +2,55 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"gorm.io/gorm"
 	"main.go/app"
+	"main.go/logging"
+	"main.go/middlewares"
 	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
 )
 
 // @Summary      Buscar todos os ingredientes
-// @Description  Buscar todos os ingredientes cadastrados
+// @Description  Buscar todos os ingredientes cadastrados. Os nomes da resposta seguem o cabeçalho Accept-Language.
 // @Tags         ingredient
 // @Produce      json
+// @Param		 Accept-Language header string false "Idioma dos nomes (pt-BR, pt-PT, en ou es)"
 // @Success      200  {array}   models.Ingredient
-// @Failure      404    "Not Found"
-// @Failure      500    "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /ingredient [get]
 func GetAllIngredientsHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var ingredients []models.Ingredient
-
-		result := app.DB.Find(&ingredients)
-
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				http.Error(w, "Not Found", http.StatusNotFound)
-				return
-			} else {
-				fmt.Printf("Error querying ingredients: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+		ingredients, err := app.Ingredients.List(r.Context())
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying ingredients", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
 		}
 
 		if len(ingredients) == 0 {
-			http.Error(w, "No ingredients found", http.StatusNotFound)
+			problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "No ingredients found")
 			return
 		}
 
+		pointers := make([]*models.Ingredient, len(ingredients))
+		for i := range ingredients {
+			pointers[i] = &ingredients[i]
+		}
+		localizeIngredients(app, w, r, pointers...)
+
 		ingredientsJson, err := json.Marshal(ingredients)
 
 		if err != nil {
-			http.Error(w, "Error encoding ingredients to JSON", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -56,41 +60,40 @@ func GetAllIngredientsHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Buscar ingrediente pelo ID
-// @Description  Buscar ingrediente pelo ID
+// @Description  Buscar ingrediente pelo ID. O nome da resposta segue o cabeçalho Accept-Language.
 // @Tags         ingredient
 // @Produce      json
 // @Param		 id path int true "ID do ingrediente"
+// @Param		 Accept-Language header string false "Idioma do nome (pt-BR, pt-PT, en ou es)"
 // @Success      200  {array}   models.Ingredient
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /ingredient/{id} [get]
 func GetIngredientByIdHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
+		id, _ := idParam(r, "id")
 
-		var ingredient models.Ingredient
-
-		result := app.DB.Where("id = ?", id).First(&ingredient)
-
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				http.Error(w, "Ingredient not Found", http.StatusNotFound)
+		ingredient, err := app.Ingredients.Get(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeIngredientNotFound, "Ingredient not found")
 				return
 			} else {
-				fmt.Printf("Error querying ingredients: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				logging.FromContext(r.Context()).Error("Error querying ingredients", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 				return
 			}
 		}
 
+		localizeIngredients(app, w, r, ingredient)
+
 		ingredientJson, err := json.Marshal(ingredient)
 
 		if err != nil {
-			http.Error(w, "Error encoding ingredient to JSON", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
-		log.Println(ingredientJson)
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(ingredientJson)
 
@@ -98,38 +101,36 @@ func GetIngredientByIdHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Buscar ingrediente pelo nome
-// @Description  Buscar ingrediente pelo nome sem case sensitive e convertendo '-' para espaços
+// @Description  Buscar ingrediente pelo nome, pelos nomes alternativos (ex.: "aipim" encontra "mandioca") ou pelas traduções, sem case sensitive. Os nomes da resposta seguem o cabeçalho Accept-Language.
 // @Tags         ingredient
 // @Produce      json
 // @Param		 name path string true "Nome do ingrediente"
+// @Param		 Accept-Language header string false "Idioma dos nomes (pt-BR, pt-PT, en ou es)"
 // @Success      200  {array}   models.Ingredient
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /ingredient/name/{name} [get]
 func GetIngredientByNameHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		name := chi.URLParam(r, "name")
-		name = "%" + name + "%"
-
-		var ingredient []models.Ingredient
 
-		result := app.DB.Where("LOWER(name) LIKE ?", strings.ToLower(name)).Find(&ingredient)
+		ingredient, err := app.Ingredients.Search(r.Context(), name)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying ingredients", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
 
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				http.Error(w, "Ingredient not Found", http.StatusNotFound)
-				return
-			} else {
-				fmt.Printf("Error querying ingredients: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+		pointers := make([]*models.Ingredient, len(ingredient))
+		for i := range ingredient {
+			pointers[i] = &ingredient[i]
 		}
+		localizeIngredients(app, w, r, pointers...)
 
 		ingredientJson, err := json.Marshal(ingredient)
 
 		if err != nil {
-			http.Error(w, "Error encoding ingredient to JSON", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -140,14 +141,16 @@ func GetIngredientByNameHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Criar novo ingrediente
-// @Description  Criar novo ingrediente
+// @Description  Criar novo ingrediente, opcionalmente com nomes alternativos e traduções
 // @Tags         ingredient
 // @Security Token 
 // @Accept       json
 // @Produce      text/plain
 // @Param		 ingredient body models.Ingredient true "Novo ingrediente"
 // @Success      201  {string}   string "Ingredient created!"
-// @Failure      400  "Invalid JSON"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      409  {object}  models.Problem  "Ingredient already exists or data is incorrect"
+// @Failure      422  {object}  models.Problem  "Validation failed"
 // @Router       /ingredient [post]
 func CreateIngredientHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -158,14 +161,23 @@ func CreateIngredientHandler(app *app.App) http.HandlerFunc {
 
 		err := decoder.Decode(&ingredient)
 		if err != nil || ingredient.Name == "" {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
-		result := app.DB.Create(&ingredient)
+		var errs []models.FieldError
+		for i, translation := range ingredient.Translations {
+			if !slices.Contains(models.Locales, translation.Locale) {
+				errs = append(errs, models.FieldError{Field: fmt.Sprintf("translations[%d].locale", i), Message: "locale must be one of " + strings.Join(models.Locales, ", ")})
+			}
+		}
+		if len(errs) > 0 {
+			problem.WriteValidation(w, r, errs)
+			return
+		}
 
-		if result.Error != nil {
-			http.Error(w, "Ingredient already exists or data is incorrect", http.StatusBadRequest)
+		if err := app.Ingredients.Create(r.Context(), &ingredient); err != nil {
+			problem.Write(w, r, http.StatusConflict, problem.CodeConflict, "Ingredient already exists or data is incorrect")
 			return
 		}
 
@@ -184,13 +196,14 @@ func CreateIngredientHandler(app *app.App) http.HandlerFunc {
 // @Param		 id path int true "ID do ingrediente"
 // @Param		 ingredient body models.Ingredient true "Ingrediente atualizado"
 // @Success      200  {string}   string "Ingredient updated!"
-// @Failure      400  "Invalid JSON"
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      409  {object}  models.Problem  "Ingredient already exists or data is incorrect"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /ingredient/{id} [put]
 func UpdateIngredientHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
+		id, _ := idParam(r, "id")
 		var reqIngredient models.Ingredient
 
 		// Transforma body da request para uma struct, sem o ID
@@ -199,63 +212,82 @@ func UpdateIngredientHandler(app *app.App) http.HandlerFunc {
 
 		err := decoder.Decode(&reqIngredient)
 		if err != nil || reqIngredient.Name == "" {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
-		// Cria struct para armazenar dados do atual ingrediente
-		var ingredient models.Ingredient
-
-		result := app.DB.Where("id = ?", id).First(&ingredient)
-
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				fmt.Println("Ingredient not found")
-				http.Error(w, "Not Found", http.StatusNotFound)
-				return
-			} else {
-				fmt.Printf("Error querying ingredient: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
+		err = app.Ingredients.Rename(r.Context(), id, reqIngredient.Name)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrNotFound):
+				problem.Write(w, r, http.StatusNotFound, problem.CodeIngredientNotFound, "Ingredient not found")
+			case errors.Is(err, repository.ErrConflict):
+				problem.Write(w, r, http.StatusConflict, problem.CodeConflict, "Ingredient already exists or data is incorrect")
+			default:
+				logging.FromContext(r.Context()).Error("Error querying ingredient", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			}
+			return
 		}
 
-		// Struct com o ingrediente recebe nome da struct da request
-		ingredient.Name = reqIngredient.Name
-		app.DB.Save(&ingredient)
-
 		w.Header().Set("Content-Type", "text/plain")
 		w.Write([]byte("Ingredient updated!"))
 	}
 }
 
 // @Summary      Deletar ingrediente
-// @Description  Deletar ingrediente pelo ID
+// @Description  Mover o ingrediente para a lixeira, de onde pode ser restaurado por um administrador até ser removido definitivamente após o tempo de retenção. Até lá, as receitas que o usam continuam a exibi-lo. Ingredientes usados em receitas só são removidos com force=true; sem ele, a resposta lista as receitas afetadas. Apenas editores e administradores removem ingredientes, que são compartilhados por todas as receitas.
 // @Tags         ingredient
 // @Produce      text/plain
+// @Produce      json
 // @Security Token 
 // @Param		 id path int true "ID do ingrediente"
+// @Param		 force query bool false "Remover mesmo que o ingrediente seja usado em receitas"
 // @Success      200  {string}   string "Ingredient deleted!"
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      400  {object}  models.Problem  "Invalid force"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      409  {object}  models.IngredientInUse
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /ingredient/{id} [delete]
 func DeleteIngredientHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-
-		var ingredient models.Ingredient
+		id, _ := idParam(r, "id")
+		userID, _ := middlewares.GetUserID(r)
+
+		force := false
+		if value := r.URL.Query().Get("force"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid force")
+				return
+			}
+			force = parsed
+		}
 
-		result := app.DB.Where("id = ?", id).Delete(&ingredient)
+		if !force {
+			// Só as receitas visíveis para o usuário são listadas; as demais entram apenas na contagem
+			usage, err := app.Ingredients.Usage(r.Context(), id, userID)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Error querying recipes", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+				return
+			}
 
-		if result.Error != nil {
-			fmt.Printf("Error querying user: %v\n", result.Error)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
+			if usage.Count > 0 {
+				detail := fmt.Sprintf("Ingredient is used by %d recipes; use force=true to delete it anyway", usage.Count)
+				problem.WriteExtended(w, r, http.StatusConflict, problem.CodeIngredientInUse, detail, usage)
+				return
+			}
 		}
 
-		if result.RowsAffected == 0 {
-			fmt.Println("User not found")
-			http.Error(w, "Not Found", http.StatusNotFound)
+		if err := app.Ingredients.Delete(r.Context(), id, userID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeIngredientNotFound, "Ingredient not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error querying ingredient", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -263,3 +295,71 @@ func DeleteIngredientHandler(app *app.App) http.HandlerFunc {
 		w.Write([]byte("Ingredient deleted!"))
 	}
 }
+
+// @Summary      Unificar ingredientes duplicados
+// @Description  Unificar ingredientes duplicados (ex.: "Açúcar" e "acucar refinado") a um ingrediente canônico, em uma única transação: as receitas passam a usar o canônico, ganhando uma revisão, os nomes dos duplicados viram nomes alternativos dele e os duplicados são removidos. Se a receita já usa o canônico, a linha dele prevalece.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security Token
+// @Param		 merge body models.IngredientMergeRequest true "Ingrediente canônico e duplicados"
+// @Success      200  {object}  models.IngredientMergeReport
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Ingredient not found"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /admin/ingredients/merge [post]
+func MergeIngredientsHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := middlewares.GetUserID(r)
+
+		var req models.IngredientMergeRequest
+
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(&req); err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+			return
+		}
+
+		var errs []models.FieldError
+		if req.CanonicalID == 0 {
+			errs = append(errs, models.FieldError{Field: "canonical_id", Message: "canonical_id is required"})
+		}
+		if len(req.DuplicateIDs) == 0 {
+			errs = append(errs, models.FieldError{Field: "duplicate_ids", Message: "duplicate_ids is required"})
+		}
+		if slices.Contains(req.DuplicateIDs, req.CanonicalID) {
+			errs = append(errs, models.FieldError{Field: "duplicate_ids", Message: "duplicate_ids must not contain canonical_id"})
+		}
+		if len(errs) > 0 {
+			problem.WriteValidation(w, r, errs)
+			return
+		}
+
+		slices.Sort(req.DuplicateIDs)
+		duplicateIDs := slices.Compact(req.DuplicateIDs)
+
+		report, err := app.Ingredients.Merge(r.Context(), req.CanonicalID, duplicateIDs, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeIngredientNotFound, "Ingredient not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error merging ingredients", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		reportJson, err := json.Marshal(report)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(reportJson)
+	}
+}
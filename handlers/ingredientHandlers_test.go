@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestDeleteIngredientRequiresEditor(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	editor := s.user(t, "editor", models.RoleEditor)
+	egg := s.ingredient(t, "Ovo")
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished, egg)
+
+	// Ingredientes são compartilhados: usuários comuns não os removem, nem com force
+	for _, path := range []string{"/ingredient/%d", "/ingredient/%d?force=true"} {
+		expectProblem(t, s.do(t, http.MethodDelete, fmt.Sprintf(path, egg), alice, nil), http.StatusForbidden, problem.CodeForbidden)
+	}
+	recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), alice, nil))
+	if len(recipe.IngredientsRecipes) != 1 {
+		t.Fatalf("ingredients = %+v, want the egg kept", recipe.IngredientsRecipes)
+	}
+
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/ingredient/%d?force=true", egg), editor, nil), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodPost, fmt.Sprintf("/ingredient/%d/restore", egg), editor, nil), http.StatusForbidden, problem.CodeForbidden)
+}
+
+func TestDeleteIngredientInUse(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleEditor)
+	bob := s.user(t, "bob", models.RoleEditor)
+	egg := s.ingredient(t, "Ovo")
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished, egg)
+	s.recipe(t, bob, "Omelete", models.VisibilityDraft, egg)
+
+	// O rascunho de bob conta, mas não aparece para alice
+	rec := s.do(t, http.MethodDelete, fmt.Sprintf("/ingredient/%d", egg), alice, nil)
+	expectProblem(t, rec, http.StatusConflict, problem.CodeIngredientInUse)
+
+	usage := decode[models.IngredientUsage](t, rec)
+	if usage.Count != 2 || len(usage.Recipes) != 1 || usage.Recipes[0].ID != cake {
+		t.Fatalf("usage = %+v, want count 2 listing only recipe %d", usage, cake)
+	}
+
+	usage = decode[models.IngredientUsage](t, s.do(t, http.MethodDelete, fmt.Sprintf("/ingredient/%d", egg), bob, nil))
+	if usage.Count != 2 || len(usage.Recipes) != 2 {
+		t.Fatalf("usage = %+v, want both recipes for bob", usage)
+	}
+}
+
+func TestIngredientNames(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleEditor)
+	bob := s.user(t, "bob", models.RoleUser)
+	cassava := s.ingredient(t, "Mandioca")
+	s.ingredient(t, "Batata")
+
+	aliases := fmt.Sprintf("/ingredient/%d/aliases", cassava)
+
+	// Os nomes do catálogo decidem a qual ingrediente cada nome importado se refere
+	forbidden := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodPost, aliases, models.IngredientAlias{Name: "Aipim"}},
+		{http.MethodDelete, aliases + "/Aipim", nil},
+		{http.MethodPut, fmt.Sprintf("/ingredient/%d/translations/en", cassava), models.IngredientTranslation{Name: "Cassava"}},
+		{http.MethodDelete, fmt.Sprintf("/ingredient/%d/translations/en", cassava), nil},
+	}
+	for _, req := range forbidden {
+		expectProblem(t, s.do(t, req.method, req.path, bob, req.body), http.StatusForbidden, problem.CodeForbidden)
+	}
+	expectStatus(t, s.do(t, http.MethodPost, aliases, alice, models.IngredientAlias{Name: "Aipim"}), http.StatusCreated)
+	expectProblem(t, s.do(t, http.MethodPost, aliases, alice, models.IngredientAlias{Name: "aipim"}), http.StatusConflict, problem.CodeNameInUse)
+	expectProblem(t, s.do(t, http.MethodPost, aliases, alice, models.IngredientAlias{Name: "Batata"}), http.StatusConflict, problem.CodeNameInUse)
+	expectProblem(t, s.do(t, http.MethodPost, "/ingredient/99/aliases", alice, models.IngredientAlias{Name: "Macaxeira"}), http.StatusNotFound, problem.CodeIngredientNotFound)
+
+	translation := fmt.Sprintf("/ingredient/%d/translations/en", cassava)
+	expectStatus(t, s.do(t, http.MethodPut, translation, alice, models.IngredientTranslation{Name: "Cassava"}), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodPut, translation, alice, models.IngredientTranslation{Name: "Yuca"}), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodPut, translation, alice, models.IngredientTranslation{Name: "Batata"}), http.StatusConflict, problem.CodeNameInUse)
+	expectProblem(t, s.do(t, http.MethodPut, fmt.Sprintf("/ingredient/%d/translations/fr", cassava), alice, models.IngredientTranslation{Name: "Manioc"}), http.StatusUnprocessableEntity, problem.CodeValidationFailed)
+
+	ingredient := decode[models.Ingredient](t, s.do(t, http.MethodGet, fmt.Sprintf("/ingredient/%d", cassava), 0, nil))
+	if len(ingredient.Aliases) != 1 || len(ingredient.Translations) != 1 || ingredient.Translations[0].Name != "Yuca" {
+		t.Fatalf("ingredient = %+v, want alias Aipim and translation Yuca", ingredient)
+	}
+
+	expectStatus(t, s.do(t, http.MethodDelete, aliases+"/AIPIM", alice, nil), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodDelete, aliases+"/AIPIM", alice, nil), http.StatusNotFound, problem.CodeAliasNotFound)
+	expectStatus(t, s.do(t, http.MethodDelete, translation, alice, nil), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodDelete, translation, alice, nil), http.StatusNotFound, problem.CodeTranslationNotFound)
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"main.go/app"
+	"main.go/catalog"
+	"main.go/logging"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+)
+
+// @Summary      Adicionar nome alternativo
+// @Description  Adicionar um nome alternativo (sinônimo) ao ingrediente, como "aipim" e "macaxeira" para "mandioca". Os nomes alternativos são considerados na busca pelo nome e na importação de receitas. Apenas editores e administradores alteram os nomes do catálogo.
+// @Tags         ingredient
+// @Accept       json
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID do ingrediente"
+// @Param		 alias body models.IngredientAlias true "Nome alternativo"
+// @Success      201  {string}  string "Alias created!"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Ingredient not found"
+// @Failure      409  {object}  models.Problem  "Name is already in use"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /ingredient/{id}/aliases [post]
+func CreateIngredientAliasHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var alias models.IngredientAlias
+
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(&alias)
+		alias.Name = strings.TrimSpace(alias.Name)
+		if err != nil || alias.Name == "" {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+			return
+		}
+
+		ingredient, ok := findIngredientParam(app, w, r)
+		if !ok {
+			return
+		}
+
+		alias.IngredientID = ingredient.ID
+		if err := app.Ingredients.AddAlias(r.Context(), &alias); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				problem.Write(w, r, http.StatusConflict, problem.CodeNameInUse, "Name is already in use")
+			} else {
+				logging.FromContext(r.Context()).Error("Error creating alias", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("Alias created!"))
+	}
+}
+
+// @Summary      Remover nome alternativo
+// @Description  Remover um nome alternativo do ingrediente. Apenas editores e administradores alteram os nomes do catálogo.
+// @Tags         ingredient
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID do ingrediente"
+// @Param		 name path string true "Nome alternativo"
+// @Success      200  {string}  string "Alias deleted!"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Alias not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /ingredient/{id}/aliases/{name} [delete]
+func DeleteIngredientAliasHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		if err := app.Ingredients.RemoveAlias(r.Context(), id, chi.URLParam(r, "name")); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeAliasNotFound, "Alias not found")
+			} else {
+				logging.FromContext(r.Context()).Error("Error deleting alias", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Alias deleted!"))
+	}
+}
+
+// @Summary      Definir tradução
+// @Description  Definir o nome do ingrediente em um idioma (pt-BR, pt-PT, en ou es). As respostas usam o nome do idioma pedido no cabeçalho Accept-Language, e as traduções também são consideradas na busca pelo nome e na importação de receitas. Apenas editores e administradores alteram os nomes do catálogo.
+// @Tags         ingredient
+// @Accept       json
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID do ingrediente"
+// @Param		 locale path string true "Idioma" Enums(pt-BR, pt-PT, en, es)
+// @Param		 translation body models.IngredientTranslation true "Nome no idioma"
+// @Success      200  {string}  string "Translation saved!"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Ingredient not found"
+// @Failure      409  {object}  models.Problem  "Name is already in use"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /ingredient/{id}/translations/{locale} [put]
+func PutIngredientTranslationHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locale := chi.URLParam(r, "locale")
+		var req models.IngredientTranslation
+
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(&req)
+		req.Name = strings.TrimSpace(req.Name)
+		if err != nil || req.Name == "" {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+			return
+		}
+
+		if !slices.Contains(models.Locales, locale) || (req.Locale != "" && req.Locale != locale) {
+			problem.WriteValidation(w, r, []models.FieldError{{Field: "locale", Message: "locale must be one of " + strings.Join(models.Locales, ", ")}})
+			return
+		}
+
+		ingredient, ok := findIngredientParam(app, w, r)
+		if !ok {
+			return
+		}
+
+		translation := models.IngredientTranslation{IngredientID: ingredient.ID, Locale: locale, Name: req.Name}
+		if err := app.Ingredients.SetTranslation(r.Context(), &translation); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				problem.Write(w, r, http.StatusConflict, problem.CodeNameInUse, "Name is already in use")
+			} else {
+				logging.FromContext(r.Context()).Error("Error saving translation", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Translation saved!"))
+	}
+}
+
+// @Summary      Remover tradução
+// @Description  Remover o nome do ingrediente em um idioma. Apenas editores e administradores alteram os nomes do catálogo.
+// @Tags         ingredient
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID do ingrediente"
+// @Param		 locale path string true "Idioma" Enums(pt-BR, pt-PT, en, es)
+// @Success      200  {string}  string "Translation deleted!"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Translation not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /ingredient/{id}/translations/{locale} [delete]
+func DeleteIngredientTranslationHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		if err := app.Ingredients.RemoveTranslation(r.Context(), id, chi.URLParam(r, "locale")); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeTranslationNotFound, "Translation not found")
+			} else {
+				logging.FromContext(r.Context()).Error("Error deleting translation", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Translation deleted!"))
+	}
+}
+
+// Funções privadas
+
+// findIngredientParam busca o ingrediente do parâmetro id da rota, escrevendo a resposta de erro
+// quando não for encontrado
+func findIngredientParam(app *app.App, w http.ResponseWriter, r *http.Request) (*models.Ingredient, bool) {
+	id, _ := idParam(r, "id")
+
+	ingredient, err := app.Ingredients.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeIngredientNotFound, "Ingredient not found")
+		} else {
+			logging.FromContext(r.Context()).Error("Error querying ingredient", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		}
+		return nil, false
+	}
+	return ingredient, true
+}
+
+// requestLocale escolhe o idioma dos nomes dos ingredientes pelo cabeçalho Accept-Language e o
+// informa na resposta
+func requestLocale(w http.ResponseWriter, r *http.Request) string {
+	locale := catalog.MatchLocale(r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", locale)
+	w.Header().Add("Vary", "Accept-Language")
+	return locale
+}
+
+// localizeIngredients traduz os nomes dos ingredientes para o idioma da requisição. Em caso de erro,
+// os nomes originais são mantidos.
+func localizeIngredients(app *app.App, w http.ResponseWriter, r *http.Request, ingredients ...*models.Ingredient) {
+	if err := app.Ingredients.Localize(r.Context(), requestLocale(w, r), ingredients...); err != nil {
+		logging.FromContext(r.Context()).Error("Error querying ingredient translations", "error", err)
+	}
+}
+
+// localizeRecipes traduz os nomes dos ingredientes das receitas para o idioma da requisição. Em caso
+// de erro, os nomes originais são mantidos.
+func localizeRecipes(app *app.App, w http.ResponseWriter, r *http.Request, recipes ...*models.Recipe) {
+	var ingredients []*models.Ingredient
+	for _, recipe := range recipes {
+		for i := range recipe.IngredientsRecipes {
+			ingredients = append(ingredients, &recipe.IngredientsRecipes[i].Ingredient)
+		}
+	}
+	localizeIngredients(app, w, r, ingredients...)
+}
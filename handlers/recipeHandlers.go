@@ -2,47 +2,58 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"gorm.io/gorm"
 	"main.go/app"
+	"main.go/logging"
+	"main.go/metrics"
+	"main.go/middlewares"
 	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
 )
 
 // @Summary      Buscar todas as receitas
-// @Description  Buscar todas as receitas cadastradas
+// @Description  Buscar as receitas publicadas, junto com todas as receitas do usuário autenticado (se houver token). Os nomes dos ingredientes seguem o cabeçalho Accept-Language.
 // @Tags         recipe
 // @Produce      json
+// @Security Token
+// @Param		 Accept-Language header string false "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)"
 // @Success      200  {array}   models.Recipe
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /recipe/ [get]
 func GetAllRecipesHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var recipes []models.Recipe
+		userID, _ := middlewares.GetUserID(r)
 
 		// Retorna as receitas e ingredientes associados a elas da tabela ingredients_recipes
-		result := app.DB.Preload("IngredientsRecipes.Ingredient").Find(&recipes)
+		recipes, err := app.Recipes.List(r.Context(), userID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying recipes", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
 
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				http.Error(w, "Not Found", http.StatusNotFound)
-				return
-			} else {
-				fmt.Printf("Error querying recipes: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+		pointers := make([]*models.Recipe, len(recipes))
+		for i := range recipes {
+			signRecipeImages(app, &recipes[i])
+			pointers[i] = &recipes[i]
 		}
+		setForkCounts(app, r, pointers...)
+		localizeRecipes(app, w, r, pointers...)
 
 		// Transforma structs das receitas para JSON
 		recipesJson, err := json.Marshal(recipes)
 		if err != nil {
-			http.Error(w, "Error encoding recipes to JSON", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -52,171 +63,231 @@ func GetAllRecipesHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Buscar receita pelo ID
-// @Description  Buscar receita pelo ID
+// @Description  Buscar receita pelo ID. Receitas em rascunho, privadas ou com publicação agendada só são encontradas pelo autor. O formato da resposta é escolhido pelo parâmetro format ou pelo cabeçalho Accept, respeitando os pesos (q) informados: JSON, schema.org JSON-LD, Markdown ou HTML para impressão.
 // @Tags         recipe
-// @Produce      json
+// @Produce      json,application/ld+json,text/markdown,text/html
+// @Security Token
 // @Param		 id path int true "ID da receita"
+// @Param		 format query string false "Formato da resposta" Enums(json, jsonld, markdown, html)
+// @Param		 Accept-Language header string false "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)"
 // @Success      200  {array}   models.Recipe
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      406  {object}  models.Problem  "Not Acceptable"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /recipe/{id} [get]
 func GetRecipeByIdHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-
-		var recipe models.Recipe
-
-		result := app.DB.Preload("IngredientsRecipes.Ingredient").Where("id = ?", id).First(&recipe)
+		id, _ := idParam(r, "id")
+		userID, _ := middlewares.GetUserID(r)
 
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				http.Error(w, "Recipe not found", http.StatusNotFound)
+		recipe, err := app.Recipes.Get(r.Context(), id, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
 				return
 			} else {
-				fmt.Printf("Error querying recipe: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 				return
 			}
 		}
 
-		// Transforma struct da receita para JSON
-		recipeJson, err := json.Marshal(recipe)
-		if err != nil {
-			http.Error(w, "Error encoding recipe to JSON", http.StatusInternalServerError)
-			return
-		}
-
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(recipeJson)
+		// Escreve a receita no formato pedido (JSON, JSON-LD, Markdown ou HTML)
+		writeRecipe(app, w, r, recipe)
 	}
 }
 
 // @Summary      Buscar receita pelo nome
-// @Description  Buscar receita pelo nome sem case sensitive e convertendo '-' para espaços
+// @Description  Buscar receita pelo slug ou pelo nome sem case sensitive e convertendo '-' para espaços. Como os nomes são únicos apenas entre as receitas de cada autor, retorna a mais antiga entre as visíveis para o usuário; para um endereço estável use /user/{username}/recipe/{slug}. Aceita os mesmos formatos de resposta da busca pelo ID.
 // @Tags         recipe
-// @Produce      json
+// @Produce      json,application/ld+json,text/markdown,text/html
+// @Security Token
 // @Param		 name path string true "Nome da receita"
+// @Param		 format query string false "Formato da resposta" Enums(json, jsonld, markdown, html)
+// @Param		 Accept-Language header string false "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)"
 // @Success      200  {array}   models.Recipe
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      406  {object}  models.Problem  "Not Acceptable"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /recipe/name/{name} [get]
 func GetRecipeByNameHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		name := chi.URLParam(r, "name")
-		name = strings.ToLower(strings.ReplaceAll(name, "-", " ")) // Substitui hífens por espaço antes de fazer o select
+		userID, _ := middlewares.GetUserID(r)
 
-		var recipe models.Recipe
+		recipe, err := app.Recipes.GetByName(r.Context(), name, userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
+				return
+			} else {
+				logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+				return
+			}
+		}
+
+		// Escreve a receita no formato pedido (JSON, JSON-LD, Markdown ou HTML)
+		writeRecipe(app, w, r, recipe)
+	}
+}
 
-		// Query que seleciona pelo atributo name, comparando ambas Strings em minúsculo
-		result := app.DB.Preload("IngredientsRecipes.Ingredient").Where("name LIKE LOWER(?)", name).First(&recipe)
+// @Summary      Buscar receita do usuário pelo slug
+// @Description  Buscar a receita pelo nome de usuário do autor e pelo slug da receita. Slugs antigos, de antes de uma renomeação, redirecionam para o endereço atual. Receitas em rascunho, privadas ou com publicação agendada só são encontradas pelo autor. Aceita os mesmos formatos de resposta da busca pelo ID.
+// @Tags         recipe
+// @Produce      json,application/ld+json,text/markdown,text/html
+// @Security Token
+// @Param		 username path string true "Nome de usuário do autor"
+// @Param		 slug path string true "Slug da receita"
+// @Param		 format query string false "Formato da resposta" Enums(json, jsonld, markdown, html)
+// @Param		 Accept-Language header string false "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)"
+// @Success      200  {object}  models.Recipe
+// @Success      301  "Moved Permanently"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      406  {object}  models.Problem  "Not Acceptable"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /user/{username}/recipe/{slug} [get]
+func GetUserRecipeBySlugHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username := chi.URLParam(r, "username")
+		slug := chi.URLParam(r, "slug")
+		userID, _ := middlewares.GetUserID(r)
 
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				http.Error(w, "Recipe not found", http.StatusNotFound)
+		user, err := app.Users.GetByUsername(r.Context(), username)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeUserNotFound, "User not found")
 				return
 			} else {
-				fmt.Printf("Error querying recipe: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 				return
 			}
 		}
 
-		// Transforma struct da receita para JSON
-		recipeJson, err := json.Marshal(recipe)
-		if err != nil {
-			http.Error(w, "Error encoding recipe to JSON", http.StatusInternalServerError)
+		recipe, err := app.Recipes.GetBySlug(r.Context(), user.ID, slug, userID)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.Write(recipeJson)
+		// Slug antigo: redireciona para o endereço atual da receita
+		if recipe == nil {
+			location, err := recipeSlugRedirect(app, r, userID, user.ID, slug)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
+					return
+				}
+				logging.FromContext(r.Context()).Error("Error querying recipe redirect", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+				return
+			}
+			if r.URL.RawQuery != "" {
+				location += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, location, http.StatusMovedPermanently)
+			return
+		}
+
+		// Escreve a receita no formato pedido (JSON, JSON-LD, Markdown ou HTML)
+		writeRecipe(app, w, r, recipe)
 	}
 }
 
 // @Summary      Criar nova receita
-// @Description  Criar nova receita
+// @Description  Criar nova receita junto com seus ingredientes, informados pelo ID ou pelo nome do ingrediente. A receita pertence ao usuário autenticado; só administradores podem informar outro dono (user_id). Com create_missing_ingredients, os ingredientes informados pelo nome que ainda não existem são cadastrados. Sem visibility, a receita é publicada; use draft para criá-la como rascunho. A receita e seus ingredientes são gravados em uma única transação; se algum item for inválido nada é gravado e os erros de cada item são retornados.
 // @Tags         recipe
 // @Accept       json
 // @Security Token 
-// @Produce      text/plain
-// @Param		 recipe body models.Recipe true "Nova receita"
+// @Produce      text/plain,json
+// @Param		 recipe body models.RecipeRequest true "Nova receita"
 // @Success      201  {string} string "Recipe created!"
-// @Failure      400  "Invalid JSON"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      409  {object}  models.Problem  "Recipe already exists or data is incorrect"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /recipe [post]
 func CreateRecipeHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var recipe models.Recipe
+		var req models.RecipeRequest
 
 		decoder := json.NewDecoder(r.Body)
 		decoder.DisallowUnknownFields()
 
-		err := decoder.Decode(&recipe)
-		if err != nil || recipe.UserID == 0 || recipe.Name == "" || recipe.Instructions == "" {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		err := decoder.Decode(&req)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
-		result := app.DB.Create(&recipe)
-		if result.Error != nil {
-			http.Error(w, "Recipe already exists or data is incorrect", http.StatusBadRequest)
+		authorID, _ := middlewares.GetUserID(r)
+
+		// A receita pertence ao usuário autenticado; só administradores informam outro dono
+		if req.UserID == 0 || !isAdmin(app, r, authorID) {
+			req.UserID = authorID
+		}
+
+		var recipe models.Recipe
+		if !saveRecipe(app, w, r, &recipe, &req, authorID) {
 			return
 		}
+		metrics.RecipesCreated.WithLabelValues(metrics.RecipeCreated).Inc()
+		metrics.RecipeIngredientsAdded.Add(float64(len(req.Ingredients)))
 
 		w.Header().Set("Content-type", "text/plain")
+		w.Header().Set("Location", fmt.Sprintf("/recipe/%d", recipe.ID))
 		w.WriteHeader(http.StatusCreated)
 		w.Write([]byte("Recipe created!"))
 	}
 }
 
 // @Summary      Atualizar receita
-// @Description  Atualizar receita pelo ID
+// @Description  Atualizar receita pelo ID; apenas o autor ou um administrador podem alterá-la, e só administradores trocam o dono (user_id). Quando a lista ingredients é informada, ela substitui todos os ingredientes da receita na mesma transação; quando omitida, os ingredientes atuais são mantidos.
 // @Tags         recipe
 // @Accept       json
 // @Security Token 
-// @Produce      text/plain
+// @Produce      text/plain,json
 // @Param		 id path int true "ID da receita"
-// @Param		 recipe body models.Recipe true "Receita atualizada"
+// @Param		 recipe body models.RecipeRequest true "Receita atualizada"
 // @Success      200  {string}   string "Recipe updated!"
-// @Failure      400  "Invalid JSON"
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      409  {object}  models.Problem  "Recipe already exists or data is incorrect"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /recipe/{id} [put]
 func UpdateRecipeHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
+		var req models.RecipeRequest
 
-		var reqRecipe models.Recipe
-
-		// Transforma o JSON do body da request em uma struct do modelo Recipe, sem o ID
+		// Transforma o JSON do body da request em uma struct do modelo RecipeRequest, sem o ID
 		decoder := json.NewDecoder(r.Body)
 		decoder.DisallowUnknownFields()
-		err := decoder.Decode(&reqRecipe)
+		err := decoder.Decode(&req)
 		if err != nil {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
-		var recipe models.Recipe
+		// Seleciona a receita que se pretende atualizar, se o usuário puder alterá-la
+		recipe, ok := getEditableRecipe(app, w, r)
+		if !ok {
+			return
+		}
 
-		// Seleciona a receita já que se pretende atualizar
-		result := app.DB.Where("id = ?", id).First(&recipe)
+		authorID, _ := middlewares.GetUserID(r)
 
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				fmt.Println("Recipe not found")
-				http.Error(w, "Not Found", http.StatusNotFound)
-				return
-			} else {
-				fmt.Printf("Error querying recipe: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+		// O dono da receita só muda quando informado por um administrador
+		if req.UserID == 0 || !isAdmin(app, r, authorID) {
+			req.UserID = recipe.UserID
 		}
 
-		// Atualiza seus atributos com os valores da struct da request
-		recipe.Name = reqRecipe.Name
-		recipe.Instructions = reqRecipe.Instructions
-		app.DB.Save(&recipe)
+		if !saveRecipe(app, w, r, recipe, &req, authorID) {
+			return
+		}
 
 		w.Header().Set("Content-type", "text/plain")
 		w.Write([]byte("Recipe updated!"))
@@ -224,32 +295,33 @@ func UpdateRecipeHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Deletar receita
-// @Description  Deletar receita pelo ID
+// @Description  Mover a receita para a lixeira, de onde pode ser restaurada até ser removida definitivamente após o tempo de retenção. Apenas o autor ou um administrador podem removê-la.
 // @Tags         recipe
 // @Produce      text/plain
 // @Security Token 
 // @Param		 id path int true "ID da receita"
 // @Success      200  {string}   string "Recipe deleted!"
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /recipe/{id} [delete]
 func DeleteRecipeHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-
-		var recipe models.Recipe
-
-		result := app.DB.Where("id = ?", id).Delete(&recipe)
-
-		if result.Error != nil {
-			fmt.Printf("Error querying recipe: %v\n", result.Error)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		recipe, ok := getEditableRecipe(app, w, r)
+		if !ok {
 			return
 		}
 
-		if result.RowsAffected == 0 {
-			fmt.Println("Recipe not found")
-			http.Error(w, "Not Found", http.StatusNotFound)
+		userID, _ := middlewares.GetUserID(r)
+
+		// Os ingredientes, imagens e o histórico são mantidos até a receita ser removida definitivamente
+		if err := app.Recipes.Delete(r.Context(), recipe.ID, userID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -259,7 +331,7 @@ func DeleteRecipeHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Adicionar ingrediente à receita
-// @Description  Adicionar ingrediente cadastrado à uma receita criada, passando ambos IDs
+// @Description  Adicionar ingrediente cadastrado à uma receita criada, passando ambos IDs. O ingrediente é colocado no fim da lista; use a rota de ordenação para movê-lo.
 // @Tags         ingredients_recipes
 // @Accept       json
 // @Produce      text/plain
@@ -267,16 +339,21 @@ func DeleteRecipeHandler(app *app.App) http.HandlerFunc {
 // @Param		 id path int true "ID da receita"
 // @Param		 reqIngredientRecipe body models.IngredientsRecipes true "Ingrediente adicionado"
 // @Success      201  {string}   string "Ingredient added!"
-// @Failure      400  "Error adding ingredient to recipe"
-// @Failure      500  "Internal Server Error"
-// @Router       /recipe/{id}/ingredients [post]
+// @Failure      400  {object}  models.Problem  "Ingredient is already in the recipe, or the recipe or ingredient does not exist"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/ingredients/{id} [post]
 func AddIngredientRecipeHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Converte o parâmetro ID de String para uint
 		idStr := chi.URLParam(r, "id")
 		id, err := strconv.ParseUint(idStr, 10, 64)
 		if err != nil {
-			http.Error(w, "Error converting id param to uint", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid id")
+			return
+		}
+
+		if _, ok := getEditableRecipe(app, w, r); !ok {
 			return
 		}
 
@@ -287,26 +364,39 @@ func AddIngredientRecipeHandler(app *app.App) http.HandlerFunc {
 		decoder.DisallowUnknownFields()
 		err = decoder.Decode(&reqIngredientRecipe)
 		if err != nil {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
-		// Nova struct do modelo que recebe o RecipeID do parâmetro e demais atributos da struct da request
+		// Nova struct do modelo que recebe o RecipeID do parâmetro e demais atributos da struct da request;
+		// o ingrediente adicionado vai para o fim da lista
 		newRecipe := models.IngredientsRecipes{
 			RecipeID:     uint(id),
 			IngredientID: reqIngredientRecipe.IngredientID,
 			Quantity:     reqIngredientRecipe.Quantity,
+			Group:        reqIngredientRecipe.Group,
+			Note:         reqIngredientRecipe.Note,
+			Optional:     reqIngredientRecipe.Optional,
+			ToTaste:      reqIngredientRecipe.ToTaste,
 		}
 
-		result := app.DB.Create(&newRecipe)
-		if result.Error != nil {
-			http.Error(w, "Error adding ingredient to recipe", http.StatusBadRequest)
+		authorID, _ := middlewares.GetUserID(r)
+
+		err = app.Recipes.AddIngredient(r.Context(), &newRecipe, authorID)
+		if err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidRequest, "Ingredient is already in the recipe, or the recipe or ingredient does not exist")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error adding ingredient to recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
+		metrics.RecipeIngredientsAdded.Inc()
 
 		w.Header().Set("Content-type", "text/plain")
-		w.Write([]byte("Ingredient added!"))
 		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("Ingredient added!"))
 	}
 }
 
@@ -318,32 +408,352 @@ func AddIngredientRecipeHandler(app *app.App) http.HandlerFunc {
 // @Security Token 
 // @Param		 ingredient_id path int true "ID do ingrediente"
 // @Success      200  {string}   string "Ingredient removed from recipe!"
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
-// @Router       /recipe/{id}/ingredients/{ingredient_id} [delete]
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/ingredients/{id}/{ingredient_id} [delete]
 func DeleteIngredientRecipeHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-		ingredient_id := chi.URLParam(r, "ingredient_id")
+		id, _ := idParam(r, "id")
+		ingredient_id, _ := idParam(r, "ingredient_id")
+
+		if _, ok := getEditableRecipe(app, w, r); !ok {
+			return
+		}
+
+		authorID, _ := middlewares.GetUserID(r)
+
+		err := app.Recipes.RemoveIngredient(r.Context(), id, ingredient_id, authorID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "Recipe or ingredient not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-type", "text/plain")
+		w.Write([]byte("Ingredient removed from recipe!"))
+	}
+}
+
+// @Summary      Atualizar ingrediente da receita
+// @Description  Atualizar a quantidade, o grupo, a observação e as marcações de opcional e "a gosto" de um ingrediente da receita
+// @Tags         ingredients_recipes
+// @Accept       json
+// @Produce      text/plain,json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 ingredient_id path int true "ID do ingrediente"
+// @Param		 ingredient body models.RecipeIngredientUpdate true "Novos dados do ingrediente"
+// @Success      200  {string}   string "Ingredient updated!"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/ingredients/{id}/{ingredient_id} [put]
+func UpdateIngredientRecipeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+		ingredient_id, _ := idParam(r, "ingredient_id")
+
+		if _, ok := getEditableRecipe(app, w, r); !ok {
+			return
+		}
 
-		var ingredientRecipe models.IngredientsRecipes
+		var req models.RecipeIngredientUpdate
 
-		// Query bicondicional que seleciona somente linhas que possuam, simultaneamente, os ids da receita e do ingrediente passados
-		result := app.DB.Where("recipe_id = ? AND ingredient_id = ?", id, ingredient_id).Delete(&ingredientRecipe)
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&req)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+			return
+		}
 
-		if result.Error != nil {
-			fmt.Printf("Error querying recipe: %v\n", result.Error)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		req.Quantity = strings.TrimSpace(req.Quantity)
+		if req.Quantity == "" && !req.ToTaste {
+			problem.WriteValidation(w, r, []models.FieldError{{Field: "quantity", Message: "quantity is required"}})
 			return
 		}
 
-		if result.RowsAffected == 0 {
-			fmt.Println("Recipe or ingredient not found")
-			http.Error(w, "Not Found", http.StatusNotFound)
+		authorID, _ := middlewares.GetUserID(r)
+
+		err = app.Recipes.UpdateIngredient(r.Context(), &models.IngredientsRecipes{
+			RecipeID:     id,
+			IngredientID: ingredient_id,
+			Quantity:     req.Quantity,
+			Group:        strings.TrimSpace(req.Group),
+			Note:         strings.TrimSpace(req.Note),
+			Optional:     req.Optional,
+			ToTaste:      req.ToTaste,
+		}, authorID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "Recipe or ingredient not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error updating recipe ingredient", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
 		w.Header().Set("Content-type", "text/plain")
-		w.Write([]byte("Ingredient removed from recipe!"))
+		w.Write([]byte("Ingredient updated!"))
 	}
 }
+
+// @Summary      Ordenar ingredientes da receita
+// @Description  Definir a ordem de exibição dos ingredientes da receita, informando os IDs de todos os ingredientes na ordem desejada
+// @Tags         ingredients_recipes
+// @Accept       json
+// @Produce      text/plain,json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 order body models.RecipeIngredientOrder true "Nova ordem dos ingredientes"
+// @Success      200  {string}   string "Ingredients reordered!"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/ingredients/{id}/order [put]
+func ReorderIngredientsRecipeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.RecipeIngredientOrder
+
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&req)
+		if err != nil {
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+			return
+		}
+
+		recipe, ok := getEditableRecipe(app, w, r)
+		if !ok {
+			return
+		}
+
+		// A lista deve conter cada ingrediente da receita exatamente uma vez
+		var errs []models.FieldError
+		pending := map[uint]bool{}
+		for _, item := range recipe.IngredientsRecipes {
+			pending[item.IngredientID] = true
+		}
+		for i, ingredientID := range req.IngredientIDs {
+			if !pending[ingredientID] {
+				errs = append(errs, models.FieldError{
+					Field:   fmt.Sprintf("ingredient_ids[%d]", i),
+					Message: fmt.Sprintf("ingredient %d is not in the recipe or is repeated", ingredientID),
+				})
+			}
+			delete(pending, ingredientID)
+		}
+		for _, item := range recipe.IngredientsRecipes {
+			if pending[item.IngredientID] {
+				errs = append(errs, models.FieldError{
+					Field:   "ingredient_ids",
+					Message: fmt.Sprintf("ingredient %d is missing", item.IngredientID),
+				})
+			}
+		}
+		if len(errs) > 0 {
+			problem.WriteValidation(w, r, errs)
+			return
+		}
+
+		authorID, _ := middlewares.GetUserID(r)
+
+		err = app.Recipes.ReorderIngredients(r.Context(), recipe.ID, req.IngredientIDs, authorID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error reordering recipe ingredients", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-type", "text/plain")
+		w.Write([]byte("Ingredients reordered!"))
+	}
+}
+
+// Funções privadas
+
+// saveRecipe valida a requisição e grava a receita, seus ingredientes e a revisão do histórico,
+// escrevendo a resposta de erro quando não for possível. Receitas sem ID são criadas.
+func saveRecipe(app *app.App, w http.ResponseWriter, r *http.Request, recipe *models.Recipe, req *models.RecipeRequest, authorID uint) bool {
+	errs := validateRecipeRequest(req)
+
+	recipe.UserID = req.UserID
+	recipe.Name = strings.TrimSpace(req.Name)
+	recipe.Instructions = req.Instructions
+	recipe.Servings = req.Servings
+	recipe.PrepTime = req.PrepTime
+	recipe.CookTime = req.CookTime
+	recipe.TotalTime = req.TotalTime
+
+	// Na atualização, o agendamento só muda quando publish_at é informado, mesmo que como null
+	if req.HasPublishAt || recipe.ID == 0 {
+		recipe.PublishAt = req.PublishAt
+	}
+
+	// Receitas novas são publicadas, como no padrão do modelo e na importação do catálogo;
+	// na atualização, o estado só muda quando informado
+	if req.Visibility != "" {
+		recipe.Visibility = req.Visibility
+	} else if recipe.ID == 0 {
+		recipe.Visibility = models.VisibilityPublished
+	}
+
+	if len(errs) > 0 {
+		problem.WriteValidation(w, r, errs)
+		return false
+	}
+
+	// Uma lista ausente mantém os ingredientes atuais na atualização
+	err := app.Recipes.Save(r.Context(), recipe, repository.SaveRecipeOptions{
+		Ingredients:              req.Ingredients,
+		CreateMissingIngredients: req.CreateMissingIngredients,
+		AuthorID:                 authorID,
+		Summary:                  strings.TrimSpace(req.Summary),
+	})
+
+	var invalid *repository.InvalidIngredientsError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &invalid):
+		problem.WriteValidation(w, r, invalid.Errors)
+	case errors.Is(err, repository.ErrConflict):
+		problem.Write(w, r, http.StatusConflict, problem.CodeConflict, "Recipe already exists or data is incorrect")
+	default:
+		logging.FromContext(r.Context()).Error("Error saving recipe", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+	}
+	return false
+}
+
+// recipeSlugRedirect retorna o endereço atual da receita do autor que usava o slug antigo, se ela
+// for visível para o usuário
+func recipeSlugRedirect(app *app.App, r *http.Request, userID uint, authorID uint, slug string) (string, error) {
+	recipe, err := app.Recipes.GetBySlugRedirect(r.Context(), authorID, slug, userID)
+	if err != nil {
+		return "", err
+	}
+
+	owner, err := app.Users.Get(r.Context(), recipe.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/user/%s/recipe/%s", url.PathEscape(owner.Username), recipe.Slug), nil
+}
+
+// validateRecipeRequest confere os campos da receita, sem os ingredientes
+func validateRecipeRequest(req *models.RecipeRequest) []models.FieldError {
+	var errs []models.FieldError
+
+	if req.UserID == 0 {
+		errs = append(errs, models.FieldError{Field: "user_id", Message: "user_id is required"})
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		errs = append(errs, models.FieldError{Field: "name", Message: "name is required"})
+	}
+	if strings.TrimSpace(req.Instructions) == "" {
+		errs = append(errs, models.FieldError{Field: "instructions", Message: "instructions are required"})
+	}
+	if req.Visibility != "" && !slices.Contains(models.Visibilities, req.Visibility) {
+		errs = append(errs, models.FieldError{Field: "visibility", Message: "visibility must be one of " + strings.Join(models.Visibilities, ", ")})
+	}
+
+	numbers := []struct {
+		field string
+		value int
+	}{
+		{"servings", req.Servings},
+		{"prep_time", req.PrepTime},
+		{"cook_time", req.CookTime},
+		{"total_time", req.TotalTime},
+	}
+	for _, number := range numbers {
+		if number.value < 0 {
+			errs = append(errs, models.FieldError{Field: number.field, Message: number.field + " must not be negative"})
+		}
+	}
+
+	return errs
+}
+
+// getVisibleRecipe busca a receita visível para o usuário autenticado, com os ingredientes, escrevendo
+// a resposta de erro quando não encontrada
+func getVisibleRecipe(app *app.App, w http.ResponseWriter, r *http.Request, id uint, notFound string) (*models.Recipe, bool) {
+	userID, _ := middlewares.GetUserID(r)
+
+	recipe, err := app.Recipes.Get(r.Context(), id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, notFound)
+		} else {
+			logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		}
+		return nil, false
+	}
+	return recipe, true
+}
+
+// getEditableRecipe busca a receita do parâmetro id, com as linhas de ingredientes, e verifica se o
+// usuário autenticado pode alterá-la: o autor ou um administrador. Quem não vê a receita recebe 404,
+// para não revelar rascunhos e receitas privadas; quem a vê, mas não pode alterá-la, recebe 403.
+func getEditableRecipe(app *app.App, w http.ResponseWriter, r *http.Request) (*models.Recipe, bool) {
+	return getOwnRecipe(app, w, r, "Only the author or an administrator can change the recipe")
+}
+
+// getOwnRecipe busca a receita do parâmetro id restrita ao autor e aos administradores, como
+// getEditableRecipe, usando forbidden como detalhe do 403
+func getOwnRecipe(app *app.App, w http.ResponseWriter, r *http.Request, forbidden string) (*models.Recipe, bool) {
+	id, _ := idParam(r, "id")
+	userID, _ := middlewares.GetUserID(r)
+
+	recipe, err := app.Recipes.Find(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
+		} else {
+			logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		}
+		return nil, false
+	}
+
+	if recipe.UserID == userID || isAdmin(app, r, userID) {
+		return recipe, true
+	}
+
+	visible, err := app.Recipes.Visible(r.Context(), recipe.ID, userID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return nil, false
+	}
+	if !visible {
+		problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
+		return nil, false
+	}
+	problem.Write(w, r, http.StatusForbidden, problem.CodeForbidden, forbidden)
+	return nil, false
+}
+
+// idParam converte o parâmetro de rota em ID. IDs inválidos resultam em 0, que não corresponde a
+// nenhum registro.
+func idParam(r *http.Request, name string) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, name), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
@@ -0,0 +1,196 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestUpdateRecipeRequiresAuthorOrAdmin(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	root := s.user(t, "root", models.RoleAdmin)
+	published := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+	draft := s.recipe(t, alice, "Torta", models.VisibilityDraft)
+
+	update := models.RecipeRequest{Name: "Bolo de fubá", Instructions: "Asse por 40 minutos."}
+
+	// Quem vê a receita recebe 403; o rascunho continua escondido com 404
+	expectProblem(t, s.do(t, http.MethodPut, fmt.Sprintf("/recipe/%d", published), bob, update), http.StatusForbidden, problem.CodeForbidden)
+	expectProblem(t, s.do(t, http.MethodPut, fmt.Sprintf("/recipe/%d", draft), bob, update), http.StatusNotFound, problem.CodeRecipeNotFound)
+	expectProblem(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/ingredients/%d/1", published), bob, nil), http.StatusForbidden, problem.CodeForbidden)
+
+	expectStatus(t, s.do(t, http.MethodPut, fmt.Sprintf("/recipe/%d", published), alice, update), http.StatusOK)
+
+	update.Name = "Bolo de milho"
+	expectStatus(t, s.do(t, http.MethodPut, fmt.Sprintf("/recipe/%d", published), root, update), http.StatusOK)
+
+	recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", published), bob, nil))
+	if recipe.Name != "Bolo de milho" || recipe.UserID != alice {
+		t.Fatalf("recipe = %q by %d, want %q by %d", recipe.Name, recipe.UserID, "Bolo de milho", alice)
+	}
+}
+
+func TestCreateRecipeOwner(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	root := s.user(t, "root", models.RoleAdmin)
+
+	tests := []struct {
+		name   string
+		author uint
+		want   uint
+	}{
+		{"user_id from a user is ignored", bob, bob},
+		{"user_id from an admin is kept", root, alice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := models.RecipeRequest{UserID: alice, Name: "Pão " + tt.name, Instructions: "Sove a massa.", Visibility: models.VisibilityPublished}
+			rec := s.do(t, http.MethodPost, "/recipe/create", tt.author, req)
+			expectStatus(t, rec, http.StatusCreated)
+
+			recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, rec.Header().Get("Location"), tt.author, nil))
+			if recipe.UserID != tt.want {
+				t.Fatalf("user_id = %d, want %d", recipe.UserID, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateRecipeValidation(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+
+	req := models.RecipeRequest{
+		Name:        " ",
+		Ingredients: []models.RecipeIngredientRequest{{Name: "Fermento"}},
+	}
+	got := expectProblem(t, s.do(t, http.MethodPost, "/recipe/create", alice, req), http.StatusUnprocessableEntity, problem.CodeValidationFailed)
+
+	fields := map[string]bool{}
+	for _, err := range got.Errors {
+		fields[err.Field] = true
+	}
+	for _, field := range []string{"name", "instructions"} {
+		if !fields[field] {
+			t.Errorf("errors = %+v, want an error for %s", got.Errors, field)
+		}
+	}
+}
+
+func TestForkRecipe(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	flour := s.ingredient(t, "Farinha")
+	parent := s.recipe(t, alice, "Bolo", models.VisibilityPublished, flour)
+	draft := s.recipe(t, alice, "Torta", models.VisibilityDraft)
+
+	first := s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/fork", parent), bob, nil)
+	expectStatus(t, first, http.StatusCreated)
+	fork := decode[models.Recipe](t, first)
+	if fork.UserID != bob || fork.Visibility != models.VisibilityDraft || fork.ParentID == nil || *fork.ParentID != parent {
+		t.Fatalf("fork = %+v, want a draft of bob copied from %d", fork, parent)
+	}
+	if len(fork.IngredientsRecipes) != 1 || fork.IngredientsRecipes[0].IngredientID != flour {
+		t.Fatalf("fork ingredients = %+v, want the parent's", fork.IngredientsRecipes)
+	}
+
+	// A segunda cópia recebe um sufixo no nome
+	second := decode[models.Recipe](t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/fork", parent), bob, nil))
+	if second.Name != "Bolo (2)" {
+		t.Fatalf("name = %q, want %q", second.Name, "Bolo (2)")
+	}
+
+	expectProblem(t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/fork", draft), bob, nil), http.StatusNotFound, problem.CodeRecipeNotFound)
+
+	// Os rascunhos de bob só aparecem na árvore para ele
+	tree := decode[models.RecipeForkNode](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/forks", parent), bob, nil))
+	if tree.Author != "alice" || len(tree.Forks) != 2 || tree.Forks[0].Author != "bob" {
+		t.Fatalf("tree = %+v, want bob's two forks", tree)
+	}
+	tree = decode[models.RecipeForkNode](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/forks", parent), alice, nil))
+	if len(tree.Forks) != 0 {
+		t.Fatalf("forks = %+v, want none for alice", tree.Forks)
+	}
+}
+
+func TestAddIngredientRecipe(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	flour := s.ingredient(t, "Farinha")
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+
+	path := fmt.Sprintf("/recipe/ingredients/%d", cake)
+	body := map[string]any{"ingredient_id": flour, "quantity": "200g"}
+	expectStatus(t, s.do(t, http.MethodPost, path, alice, body), http.StatusCreated)
+	expectStatus(t, s.do(t, http.MethodPost, path, alice, body), http.StatusBadRequest)
+
+	recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), alice, nil))
+	if len(recipe.IngredientsRecipes) != 1 || recipe.IngredientsRecipes[0].IngredientID != flour {
+		t.Fatalf("ingredients = %+v, want only ingredient %d", recipe.IngredientsRecipes, flour)
+	}
+}
+
+func TestCreateRecipeVisibility(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+
+	tests := []struct {
+		visibility string
+		want       string
+		status     int
+	}{
+		{"", models.VisibilityPublished, http.StatusOK},
+		{models.VisibilityDraft, models.VisibilityDraft, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := models.RecipeRequest{Name: "Pão " + tt.want, Instructions: "Sove a massa.", Visibility: tt.visibility}
+		rec := s.do(t, http.MethodPost, "/recipe/create", alice, req)
+		expectStatus(t, rec, http.StatusCreated)
+
+		recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, rec.Header().Get("Location"), alice, nil))
+		if recipe.Visibility != tt.want {
+			t.Errorf("visibility %q: created as %q, want %q", tt.visibility, recipe.Visibility, tt.want)
+		}
+		expectStatus(t, s.do(t, http.MethodGet, rec.Header().Get("Location"), bob, nil), tt.status)
+	}
+}
+
+func TestUpdateRecipeKeepsSchedule(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+
+	publishAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	create := models.RecipeRequest{Name: "Bolo", Instructions: "Asse por 40 minutos.", Visibility: models.VisibilityPublished, PublishAt: &publishAt}
+	rec := s.do(t, http.MethodPost, "/recipe/create", alice, create)
+	expectStatus(t, rec, http.StatusCreated)
+	path := rec.Header().Get("Location")
+
+	// Sem publish_at, a atualização mantém o agendamento
+	update := models.RecipeRequest{Name: "Bolo de fubá", Instructions: "Asse por 40 minutos."}
+	expectStatus(t, s.do(t, http.MethodPut, path, alice, update), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodGet, path, bob, nil), http.StatusNotFound, problem.CodeRecipeNotFound)
+
+	recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, path, alice, nil))
+	if recipe.PublishAt == nil || !recipe.PublishAt.Equal(publishAt) {
+		t.Fatalf("publish_at = %v, want %v", recipe.PublishAt, publishAt)
+	}
+
+	// Com null, o agendamento é cancelado
+	body := map[string]any{"name": "Bolo de fubá", "instructions": "Asse por 40 minutos.", "publish_at": nil}
+	expectStatus(t, s.do(t, http.MethodPut, path, alice, body), http.StatusOK)
+	recipe = decode[models.Recipe](t, s.do(t, http.MethodGet, path, bob, nil))
+	if recipe.PublishAt != nil {
+		t.Fatalf("publish_at = %v, want none", recipe.PublishAt)
+	}
+}
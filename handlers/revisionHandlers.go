@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"main.go/app"
+	"main.go/history"
+	"main.go/logging"
+	"main.go/middlewares"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+)
+
+// @Summary      Histórico da receita
+// @Description  Listar as revisões da receita, da mais recente para a mais antiga, com autor, data e resumo da alteração. Apenas o autor da receita ou um administrador veem o histórico, que guarda também estados que não foram publicados.
+// @Tags         recipe
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Success      200  {array}   models.RecipeRevision
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Recipe not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/revisions [get]
+func GetRecipeRevisionsHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipe, ok := getRecipeHistory(app, w, r)
+		if !ok {
+			return
+		}
+		id := recipe.ID
+
+		revisions, err := app.Recipes.Revisions(r.Context(), id)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying recipe revisions", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		if len(revisions) == 0 {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found")
+			return
+		}
+
+		setRevisionAuthors(app, r, revisions)
+
+		revisionsJson, err := json.Marshal(revisions)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(revisionsJson)
+	}
+}
+
+// @Summary      Buscar revisão da receita
+// @Description  Buscar uma revisão da receita pelo número, com o estado completo da receita e de seus ingredientes naquela revisão. Apenas o autor da receita ou um administrador veem o histórico.
+// @Tags         recipe
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 number path int true "Número da revisão"
+// @Success      200  {object}  models.RecipeRevision
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Recipe or revision not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/revisions/{number} [get]
+func GetRecipeRevisionHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipe, ok := getRecipeHistory(app, w, r)
+		if !ok {
+			return
+		}
+		id := recipe.ID
+
+		revision, ok := getRevision(app, w, r, id, chi.URLParam(r, "number"))
+		if !ok {
+			return
+		}
+
+		snapshot, err := history.Decode(revision)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error decoding recipe revision", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+		revision.Recipe = snapshot
+
+		revisions := []models.RecipeRevision{*revision}
+		setRevisionAuthors(app, r, revisions)
+
+		revisionJson, err := json.Marshal(revisions[0])
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(revisionJson)
+	}
+}
+
+// @Summary      Comparar revisões da receita
+// @Description  Comparar duas revisões da receita: campos alterados, modo de preparo linha a linha e ingredientes adicionados, removidos ou alterados. Sem parâmetros, compara a última revisão com a anterior. Apenas o autor da receita ou um administrador veem o histórico.
+// @Tags         recipe
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 from query int false "Número da revisão de origem (padrão: a anterior à de destino)"
+// @Param		 to query int false "Número da revisão de destino (padrão: a última)"
+// @Success      200  {object}  models.RecipeRevisionDiff
+// @Failure      400  {object}  models.Problem  "Invalid revision number"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Revision not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/revisions/diff [get]
+func DiffRecipeRevisionsHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipe, ok := getRecipeHistory(app, w, r)
+		if !ok {
+			return
+		}
+		id := recipe.ID
+
+		from := r.URL.Query().Get("from")
+		to := r.URL.Query().Get("to")
+
+		for _, value := range []string{from, to} {
+			if _, err := strconv.Atoi(value); value != "" && err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid revision number")
+				return
+			}
+		}
+
+		// Sem destino, compara a última revisão
+		if to == "" {
+			revisions, err := app.Recipes.Revisions(r.Context(), id)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Error querying recipe revisions", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+				return
+			}
+			if len(revisions) == 0 {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeRevisionNotFound, "Revision not found")
+				return
+			}
+			to = strconv.Itoa(revisions[0].Number)
+		}
+
+		// Sem origem, compara com a revisão anterior
+		if from == "" {
+			number, _ := strconv.Atoi(to)
+			from = strconv.Itoa(max(number-1, 1))
+		}
+
+		fromRevision, ok := getRevision(app, w, r, id, from)
+		if !ok {
+			return
+		}
+		toRevision, ok := getRevision(app, w, r, id, to)
+		if !ok {
+			return
+		}
+
+		fromSnapshot, err := history.Decode(fromRevision)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error decoding recipe revision", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+		toSnapshot, err := history.Decode(toRevision)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error decoding recipe revision", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		diff := history.Diff(fromSnapshot, toSnapshot)
+		diff.From = fromRevision.Number
+		diff.To = toRevision.Number
+
+		diffJson, err := json.Marshal(diff)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(diffJson)
+	}
+}
+
+// @Summary      Restaurar revisão da receita
+// @Description  Restaurar a receita, com a visibilidade e o agendamento da publicação, e seus ingredientes para o estado de uma revisão antiga. Apenas o autor da receita ou um administrador podem restaurá-la. A restauração é registrada como uma nova revisão, preservando o histórico; ingredientes removidos do catálogo desde então são cadastrados novamente.
+// @Tags         recipe
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 number path int true "Número da revisão a restaurar"
+// @Success      201  {object}  models.RecipeRevision
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Revision not found"
+// @Failure      409  {object}  models.Problem  "Recipe name is already in use"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/revisions/{number}/restore [post]
+func RestoreRecipeRevisionHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipe, ok := getEditableRecipe(app, w, r)
+		if !ok {
+			return
+		}
+
+		revision, ok := getRevision(app, w, r, recipe.ID, chi.URLParam(r, "number"))
+		if !ok {
+			return
+		}
+
+		authorID, _ := middlewares.GetUserID(r)
+
+		restored, err := app.Recipes.RestoreRevision(r.Context(), revision, authorID)
+		if err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				problem.Write(w, r, http.StatusConflict, problem.CodeNameInUse, "Recipe name is already in use")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error restoring recipe revision", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		// A receita já estava igual à revisão: nada a registrar
+		if restored == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		restoredJson, err := json.Marshal(restored)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/recipe/%d/revisions/%d", restored.RecipeID, restored.Number))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(restoredJson)
+	}
+}
+
+// Funções privadas
+
+// getRecipeHistory busca a receita do parâmetro id para as leituras do histórico, restritas ao autor e
+// aos administradores: as revisões guardam rascunhos e estados anteriores da receita
+func getRecipeHistory(app *app.App, w http.ResponseWriter, r *http.Request) (*models.Recipe, bool) {
+	return getOwnRecipe(app, w, r, "Only the author or an administrator can view the recipe history")
+}
+
+// getRevision busca a revisão pelo ID da receita e número, escrevendo a resposta de erro quando não encontrada
+func getRevision(app *app.App, w http.ResponseWriter, r *http.Request, recipeID uint, number string) (*models.RecipeRevision, bool) {
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		problem.Write(w, r, http.StatusNotFound, problem.CodeRevisionNotFound, "Revision not found")
+		return nil, false
+	}
+
+	revision, err := app.Recipes.Revision(r.Context(), recipeID, n)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeRevisionNotFound, "Revision not found")
+		} else {
+			logging.FromContext(r.Context()).Error("Error querying recipe revision", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		}
+		return nil, false
+	}
+
+	return revision, true
+}
+
+// setRevisionAuthors preenche o nome do autor de cada revisão
+func setRevisionAuthors(app *app.App, r *http.Request, revisions []models.RecipeRevision) {
+	var ids []uint
+	for _, revision := range revisions {
+		ids = append(ids, revision.AuthorID)
+	}
+
+	names, err := app.Users.Usernames(r.Context(), ids)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error querying revision authors", "error", err)
+		return
+	}
+	for i := range revisions {
+		revisions[i].Author = names[revisions[i].AuthorID]
+	}
+}
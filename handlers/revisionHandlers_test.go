@@ -0,0 +1,70 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestRestoreRecipeRevision(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityDraft)
+
+	publish := models.RecipeRequest{Name: "Bolo de fubá", Instructions: "Asse por 40 minutos.", Visibility: models.VisibilityPublished}
+	expectStatus(t, s.do(t, http.MethodPut, fmt.Sprintf("/recipe/%d", cake), alice, publish), http.StatusOK)
+
+	// O histórico guarda o rascunho: só o autor e os administradores o leem
+	for _, path := range []string{"/recipe/%d/revisions", "/recipe/%d/revisions/1", "/recipe/%d/revisions/diff"} {
+		expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf(path, cake), bob, nil), http.StatusForbidden, problem.CodeForbidden)
+	}
+
+	revisions := decode[[]models.RecipeRevision](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/revisions", cake), alice, nil))
+	if len(revisions) != 2 || revisions[0].Number != 2 || revisions[0].Author != "alice" {
+		t.Fatalf("revisions = %+v, want 2 revisions by alice, newest first", revisions)
+	}
+
+	restore := fmt.Sprintf("/recipe/%d/revisions/1/restore", cake)
+	expectProblem(t, s.do(t, http.MethodPost, restore, bob, nil), http.StatusForbidden, problem.CodeForbidden)
+	expectProblem(t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/revisions/9/restore", cake), alice, nil), http.StatusNotFound, problem.CodeRevisionNotFound)
+
+	rec := s.do(t, http.MethodPost, restore, alice, nil)
+	expectStatus(t, rec, http.StatusCreated)
+	if restored := decode[models.RecipeRevision](t, rec); restored.Number != 3 || restored.Summary != "restored revision 1" {
+		t.Fatalf("revision = %+v, want revision 3 restoring 1", restored)
+	}
+
+	// A revisão 1 era um rascunho, que volta a ficar escondido
+	expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), bob, nil), http.StatusNotFound, problem.CodeRecipeNotFound)
+	recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), alice, nil))
+	if recipe.Name != "Bolo" || recipe.Visibility != models.VisibilityDraft {
+		t.Fatalf("recipe = %q (%s), want %q (draft)", recipe.Name, recipe.Visibility, "Bolo")
+	}
+
+	// Restaurar de novo não muda nada
+	expectStatus(t, s.do(t, http.MethodPost, restore, alice, nil), http.StatusNoContent)
+}
+
+func TestRestoreRecipeRevisionKeepsOwner(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	root := s.user(t, "root", models.RoleAdmin)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+
+	transfer := models.RecipeRequest{UserID: bob, Name: "Bolo de fubá", Instructions: "Asse por 40 minutos."}
+	expectStatus(t, s.do(t, http.MethodPut, fmt.Sprintf("/recipe/%d", cake), root, transfer), http.StatusOK)
+
+	// O novo dono restaura a primeira revisão, que era de alice, e continua dono da receita
+	expectStatus(t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/revisions/1/restore", cake), bob, nil), http.StatusCreated)
+	recipe := decode[models.Recipe](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), bob, nil))
+	if recipe.Name != "Bolo" || recipe.UserID != bob {
+		t.Fatalf("recipe = %q by %d, want %q by %d", recipe.Name, recipe.UserID, "Bolo", bob)
+	}
+	expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/revisions", cake), alice, nil), http.StatusForbidden, problem.CodeForbidden)
+	expectStatus(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/revisions", cake), root, nil), http.StatusOK)
+}
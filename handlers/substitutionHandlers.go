@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"main.go/app"
+	"main.go/logging"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+)
+
+// @Summary      Buscar substituições
+// @Description  Buscar as substituições de ingredientes cadastradas, opcionalmente filtradas pelo ingrediente substituído e pelo motivo alimentar
+// @Tags         substitution
+// @Produce      json
+// @Param		 ingredient_id query int false "ID do ingrediente substituído"
+// @Param		 reason query string false "Motivo alimentar" Enums(vegan, vegetarian, dairy-free, gluten-free, egg-free)
+// @Param		 Accept-Language header string false "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)"
+// @Success      200  {array}   models.Substitution
+// @Failure      400  {object}  models.Problem  "Invalid ingredient_id"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /substitution [get]
+func GetSubstitutionsHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var ingredientID uint
+		if value := r.URL.Query().Get("ingredient_id"); value != "" {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid ingredient_id")
+				return
+			}
+			ingredientID = uint(id)
+		}
+
+		substitutions, err := app.Substitutions.List(r.Context(), ingredientID, r.URL.Query().Get("reason"))
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying substitutions", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		pointers := make([]*models.Substitution, len(substitutions))
+		for i := range substitutions {
+			pointers[i] = &substitutions[i]
+		}
+		localizeSubstitutions(app, w, r, pointers...)
+
+		substitutionsJson, err := json.Marshal(substitutions)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(substitutionsJson)
+	}
+}
+
+// @Summary      Buscar substituição pelo ID
+// @Description  Buscar substituição de ingrediente pelo ID
+// @Tags         substitution
+// @Produce      json
+// @Param		 id path int true "ID da substituição"
+// @Param		 Accept-Language header string false "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)"
+// @Success      200  {object}  models.Substitution
+// @Failure      404  {object}  models.Problem  "Substitution not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /substitution/{id} [get]
+func GetSubstitutionByIdHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		substitution, ok := getSubstitution(app, w, r)
+		if !ok {
+			return
+		}
+
+		writeSubstitution(app, w, r, substitution, http.StatusOK)
+	}
+}
+
+// @Summary      Cadastrar substituição
+// @Description  Cadastrar uma substituição de ingrediente (ex.: leitelho por leite e suco de limão), com a proporção de cada ingrediente em relação à quantidade do substituído. Restrito a editores e administradores.
+// @Tags         substitution
+// @Accept       json
+// @Produce      json
+// @Security Token
+// @Param		 substitution body models.SubstitutionRequest true "Nova substituição"
+// @Success      201  {object}  models.Substitution
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /substitution [post]
+func CreateSubstitutionHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var substitution models.Substitution
+		if !saveSubstitution(app, w, r, &substitution) {
+			return
+		}
+
+		w.Header().Set("Location", fmt.Sprintf("/substitution/%d", substitution.ID))
+		writeSubstitution(app, w, r, &substitution, http.StatusCreated)
+	}
+}
+
+// @Summary      Atualizar substituição
+// @Description  Substituir os dados e os ingredientes de uma substituição. Restrito a editores e administradores.
+// @Tags         substitution
+// @Accept       json
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da substituição"
+// @Param		 substitution body models.SubstitutionRequest true "Substituição atualizada"
+// @Success      200  {object}  models.Substitution
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Substitution not found"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /substitution/{id} [put]
+func UpdateSubstitutionHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		substitution, ok := getSubstitution(app, w, r)
+		if !ok {
+			return
+		}
+
+		if !saveSubstitution(app, w, r, substitution) {
+			return
+		}
+
+		writeSubstitution(app, w, r, substitution, http.StatusOK)
+	}
+}
+
+// @Summary      Deletar substituição
+// @Description  Remover uma substituição de ingrediente. Restrito a editores e administradores.
+// @Tags         substitution
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID da substituição"
+// @Success      200  {string}  string "Substitution deleted!"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Substitution not found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /substitution/{id} [delete]
+func DeleteSubstitutionHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		if err := app.Substitutions.Delete(r.Context(), id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeSubstitutionNotFound, "Substitution not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error deleting substitution", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Substitution deleted!"))
+	}
+}
+
+// @Summary      Sugerir substituições para a receita
+// @Description  Listar, para cada ingrediente da receita, as substituições cadastradas, com as quantidades calculadas a partir da quantidade usada na receita. Para gerar uma versão da receita com as substituições aplicadas, use a cópia (fork) com o campo substitute.
+// @Tags         recipe
+// @Produce      json
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Param		 reason query string false "Motivo alimentar" Enums(vegan, vegetarian, dairy-free, gluten-free, egg-free)
+// @Param		 Accept-Language header string false "Idioma dos nomes dos ingredientes (pt-BR, pt-PT, en ou es)"
+// @Success      200  {object}  models.RecipeSubstitutions
+// @Failure      404  {object}  models.Problem  "Recipe not found"
+// @Failure      422  {object}  models.Problem  "Validation failed"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/substitutions [get]
+func GetRecipeSubstitutionsHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		reason := r.URL.Query().Get("reason")
+		if reason != "" && !slices.Contains(models.SubstitutionReasons, reason) {
+			problem.WriteValidation(w, r, []models.FieldError{substitutionReasonError("reason")})
+			return
+		}
+
+		recipe, ok := getVisibleRecipe(app, w, r, id, "Recipe not found")
+		if !ok {
+			return
+		}
+
+		suggestions, err := app.Substitutions.Suggest(r.Context(), recipe, reason, requestLocale(w, r))
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying substitutions", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		suggestionsJson, err := json.Marshal(suggestions)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(suggestionsJson)
+	}
+}
+
+// Funções privadas
+
+// getSubstitution busca a substituição do parâmetro id, escrevendo a resposta de erro quando não
+// encontrada
+func getSubstitution(app *app.App, w http.ResponseWriter, r *http.Request) (*models.Substitution, bool) {
+	id, _ := idParam(r, "id")
+
+	substitution, err := app.Substitutions.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			problem.Write(w, r, http.StatusNotFound, problem.CodeSubstitutionNotFound, "Substitution not found")
+		} else {
+			logging.FromContext(r.Context()).Error("Error querying substitution", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		}
+		return nil, false
+	}
+	return substitution, true
+}
+
+// saveSubstitution valida o corpo da requisição e grava a substituição com seus ingredientes,
+// escrevendo a resposta de erro quando não for possível
+func saveSubstitution(app *app.App, w http.ResponseWriter, r *http.Request, substitution *models.Substitution) bool {
+	var req models.SubstitutionRequest
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&req); err != nil {
+		problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
+		return false
+	}
+
+	errs, err := validateSubstitutionRequest(r.Context(), app, &req)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error querying ingredients", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return false
+	}
+	if len(errs) > 0 {
+		problem.WriteValidation(w, r, errs)
+		return false
+	}
+
+	substitution.IngredientID = req.IngredientID
+	substitution.Reason = req.Reason
+	substitution.Notes = strings.TrimSpace(req.Notes)
+	substitution.Replacements = nil
+	for _, item := range req.Replacements {
+		ratio := item.Ratio
+		if ratio == 0 {
+			ratio = 1
+		}
+		substitution.Replacements = append(substitution.Replacements, models.SubstitutionItem{
+			IngredientID: item.IngredientID,
+			Ratio:        ratio,
+			Note:         strings.TrimSpace(item.Note),
+		})
+	}
+
+	if err := app.Substitutions.Save(r.Context(), substitution); err != nil {
+		logging.FromContext(r.Context()).Error("Error saving substitution", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return false
+	}
+
+	return true
+}
+
+// validateSubstitutionRequest confere os campos da substituição e se os ingredientes existem
+func validateSubstitutionRequest(ctx context.Context, app *app.App, req *models.SubstitutionRequest) ([]models.FieldError, error) {
+	var errs []models.FieldError
+
+	ids := []uint{}
+	if req.IngredientID == 0 {
+		errs = append(errs, models.FieldError{Field: "ingredient_id", Message: "ingredient_id is required"})
+	} else {
+		ids = append(ids, req.IngredientID)
+	}
+	if req.Reason != "" && !slices.Contains(models.SubstitutionReasons, req.Reason) {
+		errs = append(errs, substitutionReasonError("reason"))
+	}
+	if len(req.Replacements) == 0 {
+		errs = append(errs, models.FieldError{Field: "replacements", Message: "at least one replacement is required"})
+	}
+
+	seen := map[uint]int{}
+	for i, item := range req.Replacements {
+		field := fmt.Sprintf("replacements[%d]", i)
+		switch {
+		case item.IngredientID == 0:
+			errs = append(errs, models.FieldError{Field: field + ".ingredient_id", Message: "ingredient_id is required"})
+			continue
+		case item.IngredientID == req.IngredientID:
+			errs = append(errs, models.FieldError{Field: field + ".ingredient_id", Message: "an ingredient cannot replace itself"})
+		}
+		if j, ok := seen[item.IngredientID]; ok {
+			errs = append(errs, models.FieldError{Field: field + ".ingredient_id", Message: fmt.Sprintf("ingredient is already listed in replacements[%d]", j)})
+		}
+		seen[item.IngredientID] = i
+		if item.Ratio < 0 {
+			errs = append(errs, models.FieldError{Field: field + ".ratio", Message: "ratio must not be negative"})
+		}
+		ids = append(ids, item.IngredientID)
+	}
+
+	var found []uint
+	for _, id := range ids {
+		if _, err := app.Ingredients.Get(ctx, id); err == nil {
+			found = append(found, id)
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+	if req.IngredientID != 0 && !slices.Contains(found, req.IngredientID) {
+		errs = append(errs, models.FieldError{Field: "ingredient_id", Message: fmt.Sprintf("ingredient %d not found", req.IngredientID)})
+	}
+	for i, item := range req.Replacements {
+		if item.IngredientID != 0 && !slices.Contains(found, item.IngredientID) {
+			errs = append(errs, models.FieldError{Field: fmt.Sprintf("replacements[%d].ingredient_id", i), Message: fmt.Sprintf("ingredient %d not found", item.IngredientID)})
+		}
+	}
+
+	return errs, nil
+}
+
+func substitutionReasonError(field string) models.FieldError {
+	return models.FieldError{Field: field, Message: field + " must be one of " + strings.Join(models.SubstitutionReasons, ", ")}
+}
+
+// writeSubstitution responde com a substituição recarregada do banco, com os ingredientes
+func writeSubstitution(app *app.App, w http.ResponseWriter, r *http.Request, substitution *models.Substitution, status int) {
+	substitution, err := app.Substitutions.Get(r.Context(), substitution.ID)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("Error querying substitution", "error", err)
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return
+	}
+
+	localizeSubstitutions(app, w, r, substitution)
+
+	substitutionJson, err := json.Marshal(substitution)
+	if err != nil {
+		problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(substitutionJson)
+}
+
+// localizeSubstitutions traduz os nomes dos ingredientes das substituições para o idioma da requisição
+func localizeSubstitutions(app *app.App, w http.ResponseWriter, r *http.Request, substitutions ...*models.Substitution) {
+	var ingredients []*models.Ingredient
+	for _, substitution := range substitutions {
+		ingredients = append(ingredients, &substitution.Ingredient)
+		for i := range substitution.Replacements {
+			ingredients = append(ingredients, &substitution.Replacements[i].Ingredient)
+		}
+	}
+	localizeIngredients(app, w, r, ingredients...)
+}
@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestSubstitutions(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	editor := s.user(t, "editor", models.RoleEditor)
+	buttermilk := s.ingredient(t, "Leitelho")
+	milk := s.ingredient(t, "Leite")
+	lemon := s.ingredient(t, "Limão")
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished, buttermilk)
+
+	create := models.SubstitutionRequest{
+		IngredientID: buttermilk,
+		Replacements: []models.SubstitutionItemRequest{{IngredientID: milk, Ratio: 0.95}, {IngredientID: lemon, Ratio: 0.05, Note: "suco"}},
+	}
+	expectProblem(t, s.do(t, http.MethodPost, "/substitution", alice, create), http.StatusForbidden, problem.CodeForbidden)
+
+	invalid := models.SubstitutionRequest{IngredientID: buttermilk, Replacements: []models.SubstitutionItemRequest{{IngredientID: 99}}}
+	expectProblem(t, s.do(t, http.MethodPost, "/substitution", editor, invalid), http.StatusUnprocessableEntity, problem.CodeValidationFailed)
+
+	rec := s.do(t, http.MethodPost, "/substitution", editor, create)
+	expectStatus(t, rec, http.StatusCreated)
+	substitution := decode[models.Substitution](t, rec)
+	if substitution.Ingredient.Name != "Leitelho" || len(substitution.Replacements) != 2 || substitution.Replacements[1].Ingredient.Name != "Limão" {
+		t.Fatalf("substitution = %+v, want buttermilk replaced by milk and lemon", substitution)
+	}
+	path := rec.Header().Get("Location")
+
+	list := decode[[]models.Substitution](t, s.do(t, http.MethodGet, fmt.Sprintf("/substitution?ingredient_id=%d", buttermilk), 0, nil))
+	if len(list) != 1 || list[0].ID != substitution.ID {
+		t.Fatalf("substitutions = %+v, want only %d", list, substitution.ID)
+	}
+	expectProblem(t, s.do(t, http.MethodGet, "/substitution?ingredient_id=leitelho", 0, nil), http.StatusBadRequest, problem.CodeInvalidParameter)
+
+	suggestions := decode[models.RecipeSubstitutions](t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d/substitutions", cake), 0, nil))
+	if len(suggestions.Ingredients) != 1 || len(suggestions.Ingredients[0].Substitutions) != 1 {
+		t.Fatalf("suggestions = %+v, want one substitution for the buttermilk", suggestions)
+	}
+	if replacement := suggestions.Ingredients[0].Substitutions[0].Replacements[0]; replacement.Name != "Leite" || replacement.Quantity != "0,95" {
+		t.Fatalf("replacement = %+v, want 0,95 of milk", replacement)
+	}
+
+	// Na versão vegana, o leitelho dá lugar ao leite
+	update := models.SubstitutionRequest{IngredientID: buttermilk, Reason: models.ReasonVegan, Replacements: []models.SubstitutionItemRequest{{IngredientID: milk}}}
+	expectStatus(t, s.do(t, http.MethodPut, path, editor, update), http.StatusOK)
+	fork := decode[models.Recipe](t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/fork", cake), alice, models.RecipeForkRequest{Substitute: models.ReasonVegan}))
+	if len(fork.IngredientsRecipes) != 1 || fork.IngredientsRecipes[0].IngredientID != milk {
+		t.Fatalf("fork ingredients = %+v, want milk", fork.IngredientsRecipes)
+	}
+
+	expectStatus(t, s.do(t, http.MethodDelete, path, editor, nil), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodGet, path, 0, nil), http.StatusNotFound, problem.CodeSubstitutionNotFound)
+	expectProblem(t, s.do(t, http.MethodDelete, path, editor, nil), http.StatusNotFound, problem.CodeSubstitutionNotFound)
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/app"
+	"main.go/logging"
+	"main.go/middlewares"
+	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
+)
+
+// @Summary      Lixeira do usuário
+// @Description  Listar as receitas do usuário que estão na lixeira, com a data em que serão removidas definitivamente. Administradores veem todas as receitas e também os ingredientes na lixeira.
+// @Tags         trash
+// @Produce      json
+// @Security Token
+// @Success      200  {object}  models.Trash
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /trash [get]
+func GetTrashHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := middlewares.GetUserID(r)
+		admin := isAdmin(app, r, userID)
+
+		// Cada usuário vê as próprias receitas; os ingredientes, que não têm dono, só os administradores
+		owner := userID
+		if admin {
+			owner = 0
+		}
+
+		recipes, err := app.Recipes.Trash(r.Context(), owner)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying trash", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		var ingredients []models.Ingredient
+		if admin {
+			ingredients, err = app.Ingredients.Trash(r.Context())
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Error querying trash", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+				return
+			}
+		}
+
+		trash := models.Trash{Recipes: []models.TrashItem{}, Ingredients: []models.TrashItem{}}
+		for _, recipe := range recipes {
+			trash.Recipes = append(trash.Recipes, trashItem(app, recipe.ID, recipe.Name, recipe.DeletedAt))
+		}
+		for _, ingredient := range ingredients {
+			trash.Ingredients = append(trash.Ingredients, trashItem(app, ingredient.ID, ingredient.Name, ingredient.DeletedAt))
+		}
+
+		trashJson, err := json.Marshal(trash)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(trashJson)
+	}
+}
+
+// @Summary      Restaurar receita
+// @Description  Restaurar da lixeira uma receita do usuário (administradores podem restaurar qualquer receita), com seus ingredientes, imagens e histórico
+// @Tags         trash
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID da receita"
+// @Success      200  {string}  string "Recipe restored!"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Recipe not found in trash"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /recipe/{id}/restore [post]
+func RestoreRecipeHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+		userID, _ := middlewares.GetUserID(r)
+
+		recipe, err := app.Recipes.FindDeleted(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeRecipeNotFound, "Recipe not found in trash")
+			} else {
+				logging.FromContext(r.Context()).Error("Error querying recipe", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+
+		if recipe.UserID != userID && !isAdmin(app, r, userID) {
+			problem.Write(w, r, http.StatusForbidden, problem.CodeForbidden, "")
+			return
+		}
+
+		if err := app.Recipes.Restore(r.Context(), recipe.ID); err != nil {
+			logging.FromContext(r.Context()).Error("Error restoring recipe", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Recipe restored!"))
+	}
+}
+
+// @Summary      Restaurar ingrediente
+// @Description  Restaurar da lixeira um ingrediente. Como os ingredientes não têm dono, apenas administradores podem restaurá-los.
+// @Tags         trash
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID do ingrediente"
+// @Success      200  {string}  string "Ingredient restored!"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Ingredient not found in trash"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /ingredient/{id}/restore [post]
+func RestoreIngredientHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+		userID, _ := middlewares.GetUserID(r)
+
+		if !isAdmin(app, r, userID) {
+			problem.Write(w, r, http.StatusForbidden, problem.CodeForbidden, "")
+			return
+		}
+
+		if err := app.Ingredients.Restore(r.Context(), id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeIngredientNotFound, "Ingredient not found in trash")
+			} else {
+				logging.FromContext(r.Context()).Error("Error restoring ingredient", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Ingredient restored!"))
+	}
+}
+
+// @Summary      Restaurar usuário
+// @Description  Restaurar da lixeira um usuário, que volta a conseguir entrar no sistema, junto com as receitas que foram para a lixeira com ele
+// @Tags         admin
+// @Produce      text/plain
+// @Security Token
+// @Param		 id path int true "ID do usuário"
+// @Success      200  {string}  string "User restored!"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "User not found in trash"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /admin/users/{id}/restore [post]
+func RestoreUserHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := idParam(r, "id")
+
+		if err := app.Users.Restore(r.Context(), id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeUserNotFound, "User not found in trash")
+			} else {
+				logging.FromContext(r.Context()).Error("Error restoring user", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("User restored!"))
+	}
+}
+
+// @Summary      Esvaziar lixeira
+// @Description  Remover definitivamente as receitas (com suas imagens), ingredientes e usuários que estão na lixeira há mais de older_than_days dias. Sem o parâmetro, usa o tempo de retenção configurado; com 0, esvazia toda a lixeira.
+// @Tags         admin
+// @Produce      json
+// @Security Token
+// @Param		 older_than_days query int false "Idade mínima, em dias, dos itens removidos (padrão: o tempo de retenção)"
+// @Success      200  {object}  models.TrashPurgeReport
+// @Failure      400  {object}  models.Problem  "Invalid older_than_days"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
+// @Router       /admin/trash/purge [post]
+func PurgeTrashHandler(app *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		age := app.Trash.Retention()
+		if value := r.URL.Query().Get("older_than_days"); value != "" {
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 {
+				problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidParameter, "Invalid older_than_days")
+				return
+			}
+			age = time.Duration(days) * 24 * time.Hour
+		}
+
+		report, err := app.Trash.Purge(r.Context(), time.Now().Add(-age))
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error purging trash", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		reportJson, err := json.Marshal(report)
+		if err != nil {
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(reportJson)
+	}
+}
+
+// Funções privadas
+
+// trashItem monta o item da lixeira, com a data de remoção definitiva pela retenção configurada
+func trashItem(app *app.App, id uint, name string, deletedAt gorm.DeletedAt) models.TrashItem {
+	return models.TrashItem{
+		ID:        id,
+		Name:      name,
+		DeletedAt: deletedAt.Time,
+		PurgeAt:   deletedAt.Time.Add(app.Trash.Retention()),
+	}
+}
+
+// isAdmin indica se o usuário tem o papel de administrador
+func isAdmin(app *app.App, r *http.Request, userID uint) bool {
+	user, err := app.Users.Get(r.Context(), userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+		}
+		return false
+	}
+	return user.Role == models.RoleAdmin
+}
@@ -0,0 +1,57 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestTrashOwnership(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	root := s.user(t, "root", models.RoleAdmin)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+	pie := s.recipe(t, bob, "Torta", models.VisibilityPublished)
+	salt := s.ingredient(t, "Sal")
+
+	// Só o autor ou um administrador movem a receita para a lixeira
+	expectProblem(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/%d", cake), bob, nil), http.StatusForbidden, problem.CodeForbidden)
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/%d", cake), alice, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/%d", pie), root, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/ingredient/%d", salt), root, nil), http.StatusOK)
+
+	tests := []struct {
+		user        uint
+		recipes     []uint
+		ingredients int
+	}{
+		{alice, []uint{cake}, 0},
+		{bob, []uint{pie}, 0},
+		{root, []uint{pie, cake}, 1},
+	}
+	for _, tt := range tests {
+		trash := decode[models.Trash](t, s.do(t, http.MethodGet, "/trash", tt.user, nil))
+
+		var ids []uint
+		for _, item := range trash.Recipes {
+			ids = append(ids, item.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.recipes) || len(trash.Ingredients) != tt.ingredients {
+			t.Errorf("user %d: trash = %+v, want recipes %v and %d ingredients", tt.user, trash, tt.recipes, tt.ingredients)
+		}
+	}
+
+	expectProblem(t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/restore", cake), bob, nil), http.StatusForbidden, problem.CodeForbidden)
+	expectProblem(t, s.do(t, http.MethodPost, fmt.Sprintf("/ingredient/%d/restore", salt), alice, nil), http.StatusForbidden, problem.CodeForbidden)
+
+	expectStatus(t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/restore", cake), alice, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), bob, nil), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodPost, fmt.Sprintf("/recipe/%d/restore", cake), alice, nil), http.StatusNotFound, problem.CodeRecipeNotFound)
+
+	expectStatus(t, s.do(t, http.MethodPost, fmt.Sprintf("/ingredient/%d/restore", salt), root, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodGet, fmt.Sprintf("/ingredient/%d", salt), 0, nil), http.StatusOK)
+}
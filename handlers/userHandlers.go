@@ -2,17 +2,19 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
 	"time"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 	"main.go/app"
+	"main.go/logging"
+	"main.go/metrics"
+	"main.go/middlewares"
 	"main.go/models"
+	"main.go/problem"
+	"main.go/repository"
 )
 
 // @Summary      Criar novo usuário
@@ -21,7 +23,8 @@ import (
 // @Accept       json
 // @Produce      text/plain
 // @Success      201  {string}   string "User created!"
-// @Failure      400  "Invalid JSON"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      409  {object}  models.Problem  "User already exists or data is incorrect"
 // @Router       /user [post]
 func CreateUserHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -33,7 +36,7 @@ func CreateUserHandler(app *app.App) http.HandlerFunc {
 
 		err := decoder.Decode(&user)
 		if err != nil || user.Username == "" || user.Email == "" || user.Password == "" {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
@@ -41,9 +44,11 @@ func CreateUserHandler(app *app.App) http.HandlerFunc {
 		hash, _ := hashPassword(user.Password)
 		user.Password = hash
 
-		result := app.DB.Create(&user)
-		if result.Error != nil {
-			http.Error(w, "Usuário já existe ou dados incorretos", http.StatusBadRequest)
+		// O papel não pode ser escolhido no cadastro; administradores são definidos pela linha de comando
+		user.Role = models.RoleUser
+
+		if err := app.Users.Create(r.Context(), &user); err != nil {
+			problem.Write(w, r, http.StatusConflict, problem.CodeConflict, "User already exists or data is incorrect")
 			return
 		}
 
@@ -60,30 +65,21 @@ func CreateUserHandler(app *app.App) http.HandlerFunc {
 // @Produce      json
 // @Security Token 
 // @Success      200  {array}   models.User
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /user [get]
 func GetAllUsersHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var users []models.User
-
-		result := app.DB.Find(&users)
-
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				fmt.Println("Users not found")
-				http.Error(w, "Not Found", http.StatusNotFound)
-				return
-			} else {
-				fmt.Printf("Error querying user: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+		users, err := app.Users.List(r.Context())
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
 		}
 
 		userJson, err := json.Marshal(users)
 		if err != nil {
-			http.Error(w, "Error encoding users to JSON", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -99,31 +95,28 @@ func GetAllUsersHandler(app *app.App) http.HandlerFunc {
 // @Security Token 
 // @Param		 id path int true "ID do usuário"
 // @Success      200  {array}   models.User
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /user/{id} [get]
 func GetUserByIdHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-		var user models.User
-
-		result := app.DB.Where("id = ?", id).First(&user)
+		id, _ := idParam(r, "id")
 
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				fmt.Println("User not found")
-				http.Error(w, "Not Found", http.StatusNotFound)
+		user, err := app.Users.Get(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeUserNotFound, "User not found")
 				return
 			} else {
-				fmt.Printf("Error querying user: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 				return
 			}
 		}
 
 		userJson, err := json.Marshal(user)
 		if err != nil {
-			http.Error(w, "Error encoding user to JSON", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -133,45 +126,44 @@ func GetUserByIdHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Buscar receitas criadas pelo usuário
-// @Description  Buscar receitas criadas pelo usuário
+// @Description  Buscar receitas criadas pelo usuário. O próprio usuário vê todas as suas receitas; os demais, apenas as publicadas.
 // @Tags         user
 // @Produce      json
 // @Param		 id path int true "ID do usuário"
 // @Security Token 
 // @Success      200  {array}   models.Recipe
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /user/{id}/recipes [get]
 func GetUserRecipesHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID := chi.URLParam(r, "id")
-		var recipes []models.Recipe
-
-		// Query que seleciona as receitas através do id de usuário associado
-		result := app.DB.Where("user_id = ?", userID).Find(&recipes)
+		userID, _ := idParam(r, "id")
+		callerID, _ := middlewares.GetUserID(r)
 
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				fmt.Println("User not found")
-				http.Error(w, "Not Found", http.StatusNotFound)
-				return
-			} else {
-				fmt.Printf("Error querying user: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
+		// Receitas do usuário; de outros usuários, apenas as publicadas
+		recipes, err := app.Recipes.ListByUser(r.Context(), userID, callerID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+			return
 		}
 
 		// Verifica se foram encontradas linhas com aquele UserID
 		if len(recipes) == 0 {
-			http.Error(w, "No recipes found for this user", http.StatusNotFound)
+			problem.Write(w, r, http.StatusNotFound, problem.CodeNotFound, "No recipes found for this user")
 			return
 		}
 
+		pointers := make([]*models.Recipe, len(recipes))
+		for i := range recipes {
+			pointers[i] = &recipes[i]
+		}
+		setForkCounts(app, r, pointers...)
+
 		// Transforma o array de structs do tipo Recipe em JSON
 		recipesJson, err := json.Marshal(recipes)
 		if err != nil {
-			http.Error(w, "Error encoding user to JSON", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -181,32 +173,30 @@ func GetUserRecipesHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Deletar usuário
-// @Description  Deletar usuário pelo ID
+// @Description  Mover o usuário para a lixeira, junto com as receitas dele: ele deixa de conseguir entrar no sistema e pode ser restaurado por um administrador até ser removido definitivamente após o tempo de retenção. Apenas o próprio usuário ou um administrador podem removê-lo.
 // @Tags         user
 // @Produce      text/plain
 // @Security Token 
 // @Param		 id path int true "ID do usuário"
 // @Success      200  {string}   string "User deleted!"
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /user/{id} [delete]
 func DeleteUserHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-
-		var user models.User
-
-		result := app.DB.Where("id = ?", id).Delete(&user)
-
-		if result.Error != nil {
-			fmt.Printf("Error querying user: %v\n", result.Error)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		id, _ := idParam(r, "id")
+		if !canChangeUser(app, w, r, id) {
 			return
 		}
 
-		if result.RowsAffected == 0 {
-			fmt.Println("User not found")
-			http.Error(w, "Not Found", http.StatusNotFound)
+		if err := app.Users.Delete(r.Context(), id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeUserNotFound, "User not found")
+				return
+			}
+			logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -216,20 +206,25 @@ func DeleteUserHandler(app *app.App) http.HandlerFunc {
 }
 
 // @Summary      Atualizar usuário
-// @Description  Atualizar usuário pelo ID
+// @Description  Atualizar usuário pelo ID; apenas o próprio usuário ou um administrador podem alterá-lo
 // @Tags         user
 // @Accept       json
 // @Produce      text/plain
 // @Security Token 
 // @Param		 id path int true "ID do usuário"
 // @Success      200  {string}   string "User updated!"
-// @Failure      400  "Invalid JSON"
-// @Failure      404  "Not Found"
-// @Failure      500  "Internal Server Error"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      403  {object}  models.Problem  "Forbidden"
+// @Failure      404  {object}  models.Problem  "Not Found"
+// @Failure      409  {object}  models.Problem  "User already exists or data is incorrect"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /user/{id} [put]
 func UpdateUserHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
+		id, _ := idParam(r, "id")
+		if !canChangeUser(app, w, r, id) {
+			return
+		}
 
 		var reqUser models.User
 
@@ -240,22 +235,18 @@ func UpdateUserHandler(app *app.App) http.HandlerFunc {
 
 		// Verifica a validez do JSON e se todos os campos necessários foram preenchidos
 		if err != nil || reqUser.Username == "" || reqUser.Email == "" || reqUser.Password == "" {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
-		var user models.User
-
-		result := app.DB.Where("id = ?", id).First(&user)
-
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				fmt.Println("User not found")
-				http.Error(w, "Not Found", http.StatusNotFound)
+		user, err := app.Users.Get(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				problem.Write(w, r, http.StatusNotFound, problem.CodeUserNotFound, "User not found")
 				return
 			} else {
-				fmt.Printf("Error querying user: %v\n", result.Error)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+				problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 				return
 			}
 		}
@@ -265,7 +256,10 @@ func UpdateUserHandler(app *app.App) http.HandlerFunc {
 		user.Email = reqUser.Email
 		hash, _ := hashPassword(reqUser.Password)
 		user.Password = hash
-		app.DB.Save(&user)
+		if err := app.Users.Update(r.Context(), user); err != nil {
+			problem.Write(w, r, http.StatusConflict, problem.CodeConflict, "User already exists or data is incorrect")
+			return
+		}
 
 		w.Header().Set("Content-Type", "text/plain")
 		w.Write([]byte("User updated!"))
@@ -278,9 +272,9 @@ func UpdateUserHandler(app *app.App) http.HandlerFunc {
 // @Accept       json
 // @Produce      json
 // @Success      200  {object}  models.User
-// @Failure      400  "Invalid JSON"
-// @Failure      401  "Unauthorized"
-// @Failure      500  "Internal Server Error"
+// @Failure      400  {object}  models.Problem  "Invalid JSON"
+// @Failure      401  {object}  models.Problem  "Unauthorized"
+// @Failure      500  {object}  models.Problem  "Internal Server Error"
 // @Router       /user/login [post]
 func LoginUserHandler(app *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -290,21 +284,27 @@ func LoginUserHandler(app *app.App) http.HandlerFunc {
 		// Transforma o JSON da requisição para struct do tipo UserLoginRequest
 		err := json.NewDecoder(r.Body).Decode(&reqUser)
 		if err != nil {
-			http.Error(w, "Invalid JSON", http.StatusBadRequest)
+			problem.Write(w, r, http.StatusBadRequest, problem.CodeInvalidJSON, "Invalid JSON")
 			return
 		}
 
 		// Verifica existência do usuário no banco, guardando-o na struct, se existir
-		user, err := getUserByEmail(app, reqUser.Email)
+		user, err := app.Users.GetByEmail(r.Context(), reqUser.Email)
 		if err != nil {
-			http.Error(w, "Email or password are incorrect", http.StatusUnauthorized)
+			if errors.Is(err, repository.ErrNotFound) {
+				metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+			} else {
+				logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+			}
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidCredentials, "Email or password are incorrect")
 			return
 		}
 
 		// Compara a senha inserida com a senha encriptada salva no banco (em hash)
 		validPsw := checkPasswordHash(reqUser.Password, user.Password)
 		if !validPsw {
-			http.Error(w, "Email or password are incorrect", http.StatusUnauthorized)
+			metrics.Logins.WithLabelValues(metrics.LoginFailed).Inc()
+			problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidCredentials, "Email or password are incorrect")
 			return
 		}
 
@@ -318,10 +318,10 @@ func LoginUserHandler(app *app.App) http.HandlerFunc {
 		})
 
 		// Token é assinado utilizando o SECRET
-		key := []byte(os.Getenv("SECRET"))
+		key := []byte(app.Config.Secret)
 		tokenString, err := token.SignedString(key)
 		if err != nil {
-			http.Error(w, "Could not create JWT Token", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
@@ -331,10 +331,12 @@ func LoginUserHandler(app *app.App) http.HandlerFunc {
 		// Converte o usuário logado (resgatado do banco e convertido em struct) para JSON
 		userJson, err := json.Marshal(user)
 		if err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
 			return
 		}
 
+		metrics.Logins.WithLabelValues(metrics.LoginSucceeded).Inc()
+
 		// Retorna JSON do usuário e o token de autenticação no Header
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Authorization", tokenString)
@@ -343,20 +345,20 @@ func LoginUserHandler(app *app.App) http.HandlerFunc {
 }
 
 // Funções privadas
-func getUserByEmail(app *app.App, email string) (*models.User, error) {
-	var user models.User
-
-	result := app.DB.Where("email = ?", email).First(&user)
 
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			fmt.Println("User not found")
-		} else {
-			fmt.Printf("Error querying user: %v\n", result.Error)
-		}
-		return nil, result.Error
+// canChangeUser verifica se o usuário autenticado pode alterar ou remover o usuário do ID
+// informado: apenas ele mesmo ou um administrador. Escreve a resposta de erro quando não pode.
+func canChangeUser(app *app.App, w http.ResponseWriter, r *http.Request, id uint) bool {
+	userID, ok := middlewares.GetUserID(r)
+	if !ok {
+		problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+		return false
+	}
+	if userID != id && !isAdmin(app, r, userID) {
+		problem.Write(w, r, http.StatusForbidden, problem.CodeForbidden, "")
+		return false
 	}
-	return &user, nil
+	return true
 }
 
 func hashPassword(password string) (string, error) {
@@ -0,0 +1,73 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"main.go/models"
+	"main.go/problem"
+)
+
+func TestChangeUserRequiresSelfOrAdmin(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	root := s.user(t, "root", models.RoleAdmin)
+
+	update := models.User{Username: "alice2", Email: "alice2@example.com", Password: "secret123"}
+
+	expectProblem(t, s.do(t, http.MethodPut, fmt.Sprintf("/user/%d", alice), bob, update), http.StatusForbidden, problem.CodeForbidden)
+	expectProblem(t, s.do(t, http.MethodDelete, fmt.Sprintf("/user/%d", alice), bob, nil), http.StatusForbidden, problem.CodeForbidden)
+	expectProblem(t, s.do(t, http.MethodDelete, fmt.Sprintf("/user/%d", root), bob, nil), http.StatusForbidden, problem.CodeForbidden)
+
+	expectStatus(t, s.do(t, http.MethodPut, fmt.Sprintf("/user/%d", alice), alice, update), http.StatusOK)
+	update.Username = "alice3"
+	expectStatus(t, s.do(t, http.MethodPut, fmt.Sprintf("/user/%d", alice), root, update), http.StatusOK)
+
+	user := decode[models.User](t, s.do(t, http.MethodGet, fmt.Sprintf("/user/%d", alice), alice, nil))
+	if user.Username != "alice3" {
+		t.Fatalf("username = %q, want alice3", user.Username)
+	}
+
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/user/%d", alice), root, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/user/%d", bob), bob, nil), http.StatusOK)
+}
+
+func TestTrashedUserLosesAccess(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	root := s.user(t, "root", models.RoleAdmin)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityDraft)
+
+	expectStatus(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), alice, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/user/%d", alice), root, nil), http.StatusOK)
+
+	// O token continua válido, mas o usuário está na lixeira
+	expectProblem(t, s.do(t, http.MethodGet, "/trash", alice, nil), http.StatusUnauthorized, problem.CodeInvalidToken)
+	expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), alice, nil), http.StatusUnauthorized, problem.CodeInvalidToken)
+
+	expectStatus(t, s.do(t, http.MethodPost, fmt.Sprintf("/admin/users/%d/restore", alice), root, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodGet, "/trash", alice, nil), http.StatusOK)
+}
+
+func TestTrashedUserTakesRecipes(t *testing.T) {
+	s := newTestServer(t)
+	alice := s.user(t, "alice", models.RoleUser)
+	bob := s.user(t, "bob", models.RoleUser)
+	root := s.user(t, "root", models.RoleAdmin)
+	cake := s.recipe(t, alice, "Bolo", models.VisibilityPublished)
+	pie := s.recipe(t, alice, "Torta", models.VisibilityPublished)
+	bread := s.recipe(t, bob, "Pão", models.VisibilityPublished)
+
+	// A torta já estava na lixeira antes do usuário e continua lá depois que ele volta
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/recipe/%d", pie), alice, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodDelete, fmt.Sprintf("/user/%d", alice), alice, nil), http.StatusOK)
+
+	expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), bob, nil), http.StatusNotFound, problem.CodeRecipeNotFound)
+	expectStatus(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", bread), bob, nil), http.StatusOK)
+
+	expectStatus(t, s.do(t, http.MethodPost, fmt.Sprintf("/admin/users/%d/restore", alice), root, nil), http.StatusOK)
+	expectStatus(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", cake), bob, nil), http.StatusOK)
+	expectProblem(t, s.do(t, http.MethodGet, fmt.Sprintf("/recipe/%d", pie), bob, nil), http.StatusNotFound, problem.CodeRecipeNotFound)
+}
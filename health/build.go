@@ -0,0 +1,39 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"main.go/models"
+)
+
+// BuildTime é a data da compilação, definida pelo -ldflags do build (make build usa
+// -X main.go/health.BuildTime=<data em UTC>); sem ela, build_time fica ausente, já que a data do
+// commit registrada pelo Go não é a da compilação. Commit também pode ser definido assim, e só é
+// usado quando o Go não registrou o commit no executável (ex.: código fora de um repositório git).
+var (
+	Commit    string
+	BuildTime string
+)
+
+// Build retorna a versão do executável em execução.
+func Build() models.BuildInfo {
+	build := models.BuildInfo{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return build
+	}
+	build.Version = info.Main.Version
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Commit = setting.Value
+		case "vcs.time":
+			build.CommitTime = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
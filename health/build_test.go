@@ -0,0 +1,17 @@
+package health
+
+import "testing"
+
+func TestBuildTimeFromLinker(t *testing.T) {
+	defer func(buildTime string) { BuildTime = buildTime }(BuildTime)
+
+	BuildTime = ""
+	if build := Build(); build.BuildTime != "" {
+		t.Fatalf("build_time = %q without -ldflags, want none", build.BuildTime)
+	}
+
+	BuildTime = "2026-10-19T06:10:00Z"
+	if build := Build(); build.BuildTime != BuildTime || build.CommitTime == BuildTime {
+		t.Fatalf("build = %+v, want build_time %s apart from commit_time", build, BuildTime)
+	}
+}
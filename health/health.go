@@ -0,0 +1,136 @@
+// Package health verifica se a API está pronta para atender: cada dependência (banco, migrações e
+// as que forem registradas) é conferida por um Checker do Registry.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/db/migrations"
+	"main.go/models"
+)
+
+// checkTimeout é o tempo máximo de cada verificação
+const checkTimeout = 2 * time.Second
+
+// Checker confere se uma dependência da API está disponível.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc permite usar uma função como Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Registry guarda as verificações de prontidão.
+type Registry struct {
+	mu       sync.RWMutex
+	names    []string
+	checkers map[string]Checker
+}
+
+// NewRegistry cria um Registry sem verificações.
+func NewRegistry() *Registry {
+	return &Registry{checkers: map[string]Checker{}}
+}
+
+// Register adiciona a verificação, substituindo a registrada antes com o mesmo nome.
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.checkers[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.checkers[name] = checker
+}
+
+// Run executa as verificações em paralelo, cada uma com até checkTimeout para responder. A API está
+// pronta quando todas passam.
+func (r *Registry) Run(ctx context.Context) models.Readiness {
+	r.mu.RLock()
+	names := append([]string(nil), r.names...)
+	checkers := make([]Checker, len(names))
+	for i, name := range names {
+		checkers[i] = r.checkers[name]
+	}
+	r.mu.RUnlock()
+
+	readiness := models.Readiness{Status: models.HealthOK, Checks: make([]models.ReadinessCheck, len(names))}
+
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readiness.Checks[i] = run(ctx, names[i], checker)
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range readiness.Checks {
+		if check.Status != models.HealthOK {
+			readiness.Status = models.HealthUnavailable
+		}
+	}
+	return readiness
+}
+
+// Database confere a conexão com o banco.
+func Database(db *gorm.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// Migrations confere se o banco está com todas as migrações aplicadas.
+func Migrations(migrator *migrations.Migrator) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migrations, starting with %s", len(pending), pending[0])
+		}
+		return nil
+	})
+}
+
+// Funções privadas
+
+// run executa a verificação, tratando o pânico e o tempo esgotado como falha
+func run(ctx context.Context, name string, checker Checker) (result models.ReadinessCheck) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result = models.ReadinessCheck{Name: name, Status: models.HealthOK}
+	defer func() {
+		if p := recover(); p != nil {
+			result.Status = models.HealthUnavailable
+			result.Error = fmt.Sprint(p)
+		}
+		result.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	if err := checker.Check(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", checkTimeout)
+		}
+		result.Status = models.HealthUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,230 @@
+package history
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"main.go/models"
+)
+
+// Diff compara dois estados da receita: os campos simples, o modo de preparo linha a linha e os
+// ingredientes (identificados pelo ID). Os números das revisões devem ser preenchidos por quem chama.
+func Diff(from *models.RecipeSnapshot, to *models.RecipeSnapshot) *models.RecipeRevisionDiff {
+	diff := &models.RecipeRevisionDiff{
+		Fields:       []models.FieldChange{},
+		Instructions: []models.LineChange{},
+		Ingredients:  []models.IngredientChange{},
+	}
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"user_id", strconv.FormatUint(uint64(from.UserID), 10), strconv.FormatUint(uint64(to.UserID), 10)},
+		{"name", from.Name, to.Name},
+		{"servings", strconv.Itoa(from.Servings), strconv.Itoa(to.Servings)},
+		{"prep_time", strconv.Itoa(from.PrepTime), strconv.Itoa(to.PrepTime)},
+		{"cook_time", strconv.Itoa(from.CookTime), strconv.Itoa(to.CookTime)},
+		{"total_time", strconv.Itoa(from.TotalTime), strconv.Itoa(to.TotalTime)},
+	}
+	// Revisões antigas não guardam a visibilidade, que só é comparada quando as duas a têm
+	if from.Visibility != "" && to.Visibility != "" {
+		fields = append(fields, []struct {
+			name     string
+			old, new string
+		}{
+			{"visibility", from.Visibility, to.Visibility},
+			{"publish_at", formatTime(from.PublishAt), formatTime(to.PublishAt)},
+		}...)
+	}
+	for _, field := range fields {
+		if field.old != field.new {
+			diff.Fields = append(diff.Fields, models.FieldChange{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+
+	if from.Instructions != to.Instructions {
+		diff.Instructions = diffLines(splitLines(from.Instructions), splitLines(to.Instructions))
+	}
+
+	old := map[uint]models.RecipeSnapshotIngredient{}
+	for _, item := range from.Ingredients {
+		old[item.IngredientID] = item
+	}
+	current := map[uint]bool{}
+
+	// Ordem relativa dos ingredientes presentes nas duas versões, para detectar reordenação
+	var oldOrder, newOrder []uint
+	for _, item := range from.Ingredients {
+		if containsIngredient(to.Ingredients, item.IngredientID) {
+			oldOrder = append(oldOrder, item.IngredientID)
+		}
+	}
+
+	for _, item := range to.Ingredients {
+		current[item.IngredientID] = true
+
+		previous, ok := old[item.IngredientID]
+		if !ok {
+			diff.Ingredients = append(diff.Ingredients, models.IngredientChange{Op: "added", IngredientID: item.IngredientID, Name: item.Name})
+			continue
+		}
+		newOrder = append(newOrder, item.IngredientID)
+
+		if changes := ingredientFields(previous, item); len(changes) > 0 {
+			diff.Ingredients = append(diff.Ingredients, models.IngredientChange{Op: "changed", IngredientID: item.IngredientID, Name: item.Name, Fields: changes})
+		}
+	}
+
+	for _, item := range from.Ingredients {
+		if !current[item.IngredientID] {
+			diff.Ingredients = append(diff.Ingredients, models.IngredientChange{Op: "removed", IngredientID: item.IngredientID, Name: item.Name})
+		}
+	}
+
+	for i := range oldOrder {
+		if oldOrder[i] != newOrder[i] {
+			diff.Reordered = true
+			break
+		}
+	}
+
+	return diff
+}
+
+// Summarize descreve as diferenças em uma frase curta, usada como resumo das revisões sem resumo
+// informado (ex.: "changed name, instructions; added Ovo; removed Sal").
+func Summarize(diff *models.RecipeRevisionDiff) string {
+	var parts []string
+
+	var fields []string
+	for _, field := range diff.Fields {
+		fields = append(fields, field.Field)
+	}
+	if len(diff.Instructions) > 0 {
+		fields = append(fields, "instructions")
+	}
+	if len(fields) > 0 {
+		parts = append(parts, "changed "+strings.Join(fields, ", "))
+	}
+
+	for _, op := range []string{"added", "removed", "changed"} {
+		var names []string
+		for _, item := range diff.Ingredients {
+			if item.Op == op {
+				names = append(names, item.Name)
+			}
+		}
+		if len(names) > 0 {
+			parts = append(parts, op+" "+strings.Join(names, ", "))
+		}
+	}
+
+	if diff.Reordered {
+		parts = append(parts, "reordered ingredients")
+	}
+
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Funções privadas
+
+// empty indica que não há diferenças entre os estados comparados
+func empty(diff *models.RecipeRevisionDiff) bool {
+	return len(diff.Fields) == 0 && len(diff.Instructions) == 0 && len(diff.Ingredients) == 0 && !diff.Reordered
+}
+
+func ingredientFields(old models.RecipeSnapshotIngredient, new models.RecipeSnapshotIngredient) []models.FieldChange {
+	var changes []models.FieldChange
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"quantity", old.Quantity, new.Quantity},
+		{"group", old.Group, new.Group},
+		{"note", old.Note, new.Note},
+		{"optional", strconv.FormatBool(old.Optional), strconv.FormatBool(new.Optional)},
+		{"to_taste", strconv.FormatBool(old.ToTaste), strconv.FormatBool(new.ToTaste)},
+	}
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, models.FieldChange{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+
+	return changes
+}
+
+func containsIngredient(items []models.RecipeSnapshotIngredient, id uint) bool {
+	for _, item := range items {
+		if item.IngredientID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// diffLines compara as linhas pela maior subsequência comum, marcando as linhas mantidas,
+// removidas e adicionadas
+func diffLines(old []string, new []string) []models.LineChange {
+	// lcs[i][j] é o tamanho da maior subsequência comum entre old[i:] e new[j:]
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	changes := []models.LineChange{}
+	i, j := 0, 0
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			changes = append(changes, models.LineChange{Op: "equal", Text: old[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			changes = append(changes, models.LineChange{Op: "delete", Text: old[i]})
+			i++
+		default:
+			changes = append(changes, models.LineChange{Op: "insert", Text: new[j]})
+			j++
+		}
+	}
+	for ; i < len(old); i++ {
+		changes = append(changes, models.LineChange{Op: "delete", Text: old[i]})
+	}
+	for ; j < len(new); j++ {
+		changes = append(changes, models.LineChange{Op: "insert", Text: new[j]})
+	}
+
+	return changes
+}
+
+// formatTime formata a data agendada para a comparação, vazia quando não há agendamento
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,165 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"main.go/models"
+)
+
+// Resumos da primeira revisão de cada receita
+const (
+	SummaryCreated = "created recipe"
+	SummaryInitial = "initial version"
+)
+
+// Snapshot lê o estado atual da receita e de seus ingredientes.
+func Snapshot(tx *gorm.DB, recipeID uint) (*models.RecipeSnapshot, error) {
+	var recipe models.Recipe
+
+	err := tx.Preload("IngredientsRecipes", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, ingredient_id")
+	}).Preload("IngredientsRecipes.Ingredient", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("id = ?", recipeID).First(&recipe).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSnapshot(recipe), nil
+}
+
+// NewSnapshot converte a receita, com os ingredientes já carregados, no estado guardado nas revisões.
+func NewSnapshot(recipe models.Recipe) *models.RecipeSnapshot {
+	snapshot := &models.RecipeSnapshot{
+		UserID:       recipe.UserID,
+		Name:         recipe.Name,
+		Instructions: recipe.Instructions,
+		Servings:     recipe.Servings,
+		PrepTime:     recipe.PrepTime,
+		CookTime:     recipe.CookTime,
+		TotalTime:    recipe.TotalTime,
+		Visibility:   recipe.Visibility,
+		PublishAt:    recipe.PublishAt,
+		Ingredients:  []models.RecipeSnapshotIngredient{},
+	}
+
+	for _, item := range recipe.IngredientsRecipes {
+		snapshot.Ingredients = append(snapshot.Ingredients, models.RecipeSnapshotIngredient{
+			IngredientID: item.IngredientID,
+			Name:         item.Ingredient.Name,
+			Quantity:     item.Quantity,
+			Group:        item.Group,
+			Note:         item.Note,
+			Optional:     item.Optional,
+			ToTaste:      item.ToTaste,
+		})
+	}
+
+	return snapshot
+}
+
+// Decode lê o estado da receita guardado na revisão.
+func Decode(revision *models.RecipeRevision) (*models.RecipeSnapshot, error) {
+	var snapshot models.RecipeSnapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+		return nil, fmt.Errorf("revision %d: %w", revision.ID, err)
+	}
+	return &snapshot, nil
+}
+
+// Lock bloqueia a receita até o fim da transação (SELECT ... FOR UPDATE), para que alterações
+// simultâneas da mesma receita registrem suas revisões uma de cada vez, sem repetir o número. Deve ser
+// chamado no início da transação, antes de alterar a receita e seus ingredientes. No SQLite, que não
+// tem bloqueio de linhas, as transações de escrita já são executadas uma de cada vez.
+func Lock(tx *gorm.DB, recipeID uint) error {
+	var recipe models.Recipe
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", recipeID).First(&recipe).Error
+}
+
+// Record registra uma nova revisão com o estado atual da receita. Deve ser chamado dentro da mesma
+// transação da alteração, que bloqueia a receita (ver Lock) caso ainda não o tenha feito. Sem resumo,
+// ele é gerado a partir das diferenças para a revisão anterior. Quando nada mudou desde a última
+// revisão, nenhuma revisão é criada e o retorno é nil.
+func Record(tx *gorm.DB, recipeID uint, authorID uint, summary string) (*models.RecipeRevision, error) {
+	if err := Lock(tx, recipeID); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := Snapshot(tx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var last models.RecipeRevision
+
+	result := tx.Where("recipe_id = ?", recipeID).Order("number DESC").Limit(1).Find(&last)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		previous, err := Decode(&last)
+		if err != nil {
+			return nil, err
+		}
+		diff := Diff(previous, snapshot)
+		if empty(diff) {
+			return nil, nil
+		}
+		if summary == "" {
+			summary = Summarize(diff)
+		}
+	} else if summary == "" {
+		summary = SummaryCreated
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := models.RecipeRevision{
+		RecipeID: recipeID,
+		Number:   last.Number + 1,
+		AuthorID: authorID,
+		Summary:  summary,
+		Snapshot: string(data),
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// Backfill cria a primeira revisão das receitas que ainda não têm histórico (criadas antes dele
+// existir), com o estado atual e o dono da receita como autor.
+func Backfill(db *gorm.DB) error {
+	var ids []uint
+
+	err := db.Model(&models.Recipe{}).
+		Where("NOT EXISTS (SELECT 1 FROM recipe_revisions WHERE recipe_revisions.recipe_id = recipes.id)").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var recipe models.Recipe
+			if err := tx.Select("user_id").Where("id = ?", id).First(&recipe).Error; err != nil {
+				return err
+			}
+			_, err := Record(tx, id, recipe.UserID, SummaryInitial)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("recipe %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
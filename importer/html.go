@@ -0,0 +1,177 @@
+package importer
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// ParseHTML extrai a receita de uma página HTML. Primeiro procura blocos
+// <script type="application/ld+json">; se nenhum contiver uma Recipe, usa as
+// marcações microdata (itemprop) de schema.org.
+func ParseHTML(data []byte) (*Draft, error) {
+	root, err := xhtml.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, script := range findAll(root, isJSONLDScript) {
+		if draft, err := ParseJSONLD([]byte(innerText(script))); err == nil {
+			return draft, nil
+		}
+	}
+
+	scopes := findAll(root, func(n *xhtml.Node) bool {
+		return n.Type == xhtml.ElementNode && strings.Contains(attr(n, "itemtype"), "schema.org/Recipe")
+	})
+	if len(scopes) == 0 {
+		return nil, ErrNoRecipe
+	}
+
+	return parseMicrodata(scopes[0]), nil
+}
+
+func parseMicrodata(scope *xhtml.Node) *Draft {
+	draft := &Draft{}
+
+	for _, n := range itemProps(scope) {
+		value := itemValue(n)
+		if value == "" {
+			continue
+		}
+
+		for _, prop := range strings.Fields(attr(n, "itemprop")) {
+			switch prop {
+			case "name":
+				if draft.Name == "" {
+					draft.Name = value
+				}
+			case "recipeIngredient", "ingredients":
+				draft.Ingredients = append(draft.Ingredients, value)
+			case "recipeInstructions":
+				draft.Instructions = append(draft.Instructions, instructions(value)...)
+			case "recipeYield":
+				draft.Yield = value
+			case "prepTime":
+				draft.PrepTime = value
+			case "cookTime":
+				draft.CookTime = value
+			case "totalTime":
+				draft.TotalTime = value
+			}
+		}
+	}
+
+	return draft
+}
+
+// itemProps retorna os elementos com itemprop pertencentes ao escopo informado,
+// sem descer em escopos aninhados (ex.: o "name" do autor)
+func itemProps(scope *xhtml.Node) []*xhtml.Node {
+	var props []*xhtml.Node
+	var walk func(*xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != xhtml.ElementNode {
+				continue
+			}
+			if attr(c, "itemprop") != "" {
+				props = append(props, c)
+			}
+			if !hasAttr(c, "itemscope") {
+				walk(c)
+			}
+		}
+	}
+	walk(scope)
+	return props
+}
+
+// itemValue segue as regras de valor do microdata: content, datetime ou o texto do elemento
+func itemValue(n *xhtml.Node) string {
+	for _, key := range []string{"content", "datetime"} {
+		if value := attr(n, key); value != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return strings.TrimSpace(cleanText(renderInner(n)))
+}
+
+func isJSONLDScript(n *xhtml.Node) bool {
+	return n.Type == xhtml.ElementNode && n.Data == "script" && strings.EqualFold(attr(n, "type"), "application/ld+json")
+}
+
+func findAll(root *xhtml.Node, match func(*xhtml.Node) bool) []*xhtml.Node {
+	var found []*xhtml.Node
+	var walk func(*xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		if match(n) {
+			found = append(found, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return found
+}
+
+func attr(n *xhtml.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *xhtml.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func innerText(n *xhtml.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == xhtml.TextNode {
+			b.WriteString(c.Data)
+		}
+	}
+	return b.String()
+}
+
+func renderInner(n *xhtml.Node) string {
+	var buf bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		xhtml.Render(&buf, c)
+	}
+	return buf.String()
+}
+
+var (
+	blockTags  = regexp.MustCompile(`(?i)<\s*(br|/p|/li|/div|/h[1-6])\s*/?>`)
+	anyTag     = regexp.MustCompile(`<[^>]*>`)
+	spaceRunes = regexp.MustCompile(`[ \t\r\f\v\x{00a0}]+`)
+)
+
+// cleanText remove marcações HTML (comuns em JSON-LD de blogs), decodifica entidades
+// e normaliza espaços, preservando quebras de linha entre blocos
+func cleanText(s string) string {
+	s = blockTags.ReplaceAllString(s, "\n")
+	s = anyTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = spaceRunes.ReplaceAllString(s, " ")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Frações unicode comuns em receitas, convertidas para a forma "1/2"
+var unicodeFractions = strings.NewReplacer(
+	"½", " 1/2", "⅓", " 1/3", "⅔", " 2/3", "¼", " 1/4", "¾", " 3/4",
+	"⅕", " 1/5", "⅛", " 1/8", "⅜", " 3/8", "⅝", " 5/8", "⅞", " 7/8",
+)
+
+// Unidades reconhecidas, em português, inglês e espanhol. As mais longas vêm primeiro
+// para que "colheres de sopa" tenha prioridade sobre "colheres".
+var units = []string{
+	`colher(?:es)? \((?:de )?(?:sopa|chá|cha|sobremesa|café)\)`,
+	`colher(?:es)? (?:de )?(?:sopa|chá|cha|sobremesa|café)`,
+	`x[íi]caras? \((?:de )?(?:chá|cha)\)`, `x[íi]caras? (?:de )?(?:chá|cha)`,
+	`copos? \(?americanos?\)?`,
+	`cucharadas?`, `cucharaditas?`, `tazas?`,
+	`tablespoons?`, `teaspoons?`, `tbsps?\.?`, `tsps?\.?`, `cups?`,
+	`x[íi]caras?`, `colher(?:es)?`, `copos?`, `latas?`, `pacotes?`, `caixas?`,
+	`dentes?`, `pitadas?`, `fatias?`, `ramos?`, `maços?`, `folhas?`, `unidades?`, `gotas?`,
+	`pinch(?:es)?`, `cloves?`, `slices?`, `cans?`, `packages?`,
+	`quilos?`, `gramas?`, `litros?`, `mililitros?`,
+	`ounces?`, `pounds?`, `oz\.?`, `lbs?\.?`,
+	`kg`, `mg`, `ml`, `g`, `l`,
+}
+
+var (
+	number      = `\d+(?:[.,]\d+)?(?:\s+\d+/\d+)?|\d+/\d+`
+	quantityRe  = regexp.MustCompile(`(?i)^((?:` + number + `)(?:\s*(?:-|a|to|ou|or)\s*(?:` + number + `))?)\s*(?:(` + strings.Join(units, "|") + `)(?:\s|$|\.))?\s*(?:(?:de|do|da|of)\s+)?(.*)$`)
+	toTasteRe   = regexp.MustCompile(`(?i)\s*[,(]?\s*\b(a gosto|q\.?\s?b\.?|to taste|al gusto)\b\)?\s*`)
+	parensRe    = regexp.MustCompile(`\s*\([^)]*\)`)
+	trailerRe   = regexp.MustCompile(`\s*[,;].*$`)
+	noisePrefix = regexp.MustCompile(`(?i)^(?:[-•*·]\s*)`)
+)
+
+// IngredientLine é uma linha de ingrediente dividida em quantidade e nome.
+type IngredientLine struct {
+	Quantity string
+	Name     string
+	// ToTaste indica uma linha sem quantidade, usada "a gosto".
+	ToTaste bool
+}
+
+// ParseIngredientLine divide uma linha como "2 xícaras (chá) de farinha de trigo, peneirada"
+// em quantidade ("2 xícaras (chá)") e nome ("farinha de trigo"). Linhas sem quantidade só são
+// aceitas quando indicam "a gosto"; caso contrário ok é falso.
+func ParseIngredientLine(line string) (IngredientLine, bool) {
+	line = strings.TrimSpace(noisePrefix.ReplaceAllString(unicodeFractions.Replace(line), ""))
+	line = strings.Join(strings.Fields(line), " ")
+	if line == "" {
+		return IngredientLine{}, false
+	}
+
+	if match := quantityRe.FindStringSubmatch(line); match != nil {
+		quantity := strings.TrimSpace(match[1])
+		if match[2] != "" {
+			quantity += " " + strings.TrimSuffix(strings.TrimSpace(match[2]), ".")
+		}
+		name := cleanIngredientName(match[3])
+		if name == "" {
+			return IngredientLine{}, false
+		}
+		return IngredientLine{Quantity: quantity, Name: name}, true
+	}
+
+	if toTasteRe.MatchString(line) {
+		name := cleanIngredientName(toTasteRe.ReplaceAllString(line, " "))
+		if name == "" {
+			return IngredientLine{}, false
+		}
+		return IngredientLine{Name: name, ToTaste: true}, true
+	}
+
+	return IngredientLine{}, false
+}
+
+// cleanIngredientName remove observações entre parênteses e após vírgula
+// ("picada", "em temperatura ambiente"), deixando apenas o nome do ingrediente
+func cleanIngredientName(name string) string {
+	name = toTasteRe.ReplaceAllString(name, " ")
+	name = parensRe.ReplaceAllString(name, "")
+	name = trailerRe.ReplaceAllString(name, "")
+	name = strings.Trim(strings.TrimSpace(name), ".:")
+	return strings.TrimSpace(name)
+}
+
+var (
+	durationRe = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	servingsRe = regexp.MustCompile(`\d+`)
+)
+
+// ParseDuration converte uma duração ISO 8601 ("PT1H30M") em minutos.
+func ParseDuration(value string) (int, bool) {
+	match := durationRe.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil || value == "" {
+		return 0, false
+	}
+
+	days, _ := strconv.Atoi(match[1])
+	hours, _ := strconv.Atoi(match[2])
+	minutes, _ := strconv.Atoi(match[3])
+	seconds, _ := strconv.ParseFloat(match[4], 64)
+
+	total := days*24*60 + hours*60 + minutes + int(seconds/60)
+	return total, true
+}
+
+// ParseServings extrai o número de porções de textos como "8 porções" ou "Serves 4".
+func ParseServings(value string) (int, bool) {
+	match := servingsRe.FindString(value)
+	if match == "" {
+		return 0, false
+	}
+	servings, err := strconv.Atoi(match)
+	if err != nil || servings <= 0 {
+		return 0, false
+	}
+	return servings, true
+}
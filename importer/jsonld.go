@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoRecipe indica que nenhum objeto schema.org Recipe foi encontrado no documento.
+var ErrNoRecipe = errors.New("no schema.org Recipe found")
+
+// Draft reúne os dados brutos extraídos de um objeto schema.org Recipe, antes de
+// serem convertidos para os modelos da API.
+type Draft struct {
+	Name         string
+	Ingredients  []string
+	Instructions []string
+	Yield        string
+	PrepTime     string
+	CookTime     string
+	TotalTime    string
+}
+
+// ParseJSONLD procura um objeto schema.org Recipe no documento JSON-LD informado.
+// O objeto pode estar na raiz, em uma lista ou dentro de "@graph".
+func ParseJSONLD(data []byte) (*Draft, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON-LD: %w", err)
+	}
+
+	node := findRecipe(doc)
+	if node == nil {
+		return nil, ErrNoRecipe
+	}
+
+	draft := &Draft{
+		Name:        strings.TrimSpace(text(node["name"])),
+		Ingredients: texts(firstOf(node, "recipeIngredient", "ingredients")),
+		Yield:       yield(node["recipeYield"]),
+		PrepTime:    text(node["prepTime"]),
+		CookTime:    text(node["cookTime"]),
+		TotalTime:   text(node["totalTime"]),
+	}
+	draft.Instructions = instructions(node["recipeInstructions"])
+
+	return draft, nil
+}
+
+// findRecipe percorre o documento em profundidade até encontrar um nó com @type Recipe
+func findRecipe(value any) map[string]any {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if node := findRecipe(item); node != nil {
+				return node
+			}
+		}
+	case map[string]any:
+		if isType(v["@type"], "Recipe") {
+			return v
+		}
+		if node := findRecipe(v["@graph"]); node != nil {
+			return node
+		}
+		if node := findRecipe(v["mainEntity"]); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+// isType verifica o @type, que pode ser uma string ou uma lista, com ou sem o prefixo schema.org
+func isType(value any, name string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == name || strings.HasSuffix(v, "/"+name) || strings.HasSuffix(v, ":"+name)
+	case []any:
+		for _, item := range v {
+			if isType(item, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func firstOf(node map[string]any, keys ...string) any {
+	for _, key := range keys {
+		if value, ok := node[key]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// text converte valores simples (string, número ou objeto com @value/text/name) em texto
+func text(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strings.TrimSuffix(fmt.Sprintf("%g", v), ".0")
+	case []any:
+		if len(v) > 0 {
+			return text(v[0])
+		}
+	case map[string]any:
+		for _, key := range []string{"@value", "text", "name"} {
+			if s := text(v[key]); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func texts(value any) []string {
+	var result []string
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if s := strings.TrimSpace(cleanText(text(item))); s != "" {
+				result = append(result, s)
+			}
+		}
+	default:
+		for _, line := range strings.Split(text(v), "\n") {
+			if s := strings.TrimSpace(cleanText(line)); s != "" {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// yield prefere o valor que contenha um número, pois recipeYield costuma vir como ["4", "4 porções"]
+func yield(value any) string {
+	if list, ok := value.([]any); ok {
+		for _, item := range list {
+			if s := text(item); strings.ContainsAny(s, "0123456789") {
+				return s
+			}
+		}
+	}
+	return text(value)
+}
+
+// instructions achata recipeInstructions, que pode ser texto, lista de textos, HowToStep
+// ou HowToSection com itemListElement
+func instructions(value any) []string {
+	var steps []string
+
+	switch v := value.(type) {
+	case string:
+		for _, line := range strings.Split(cleanText(v), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				steps = append(steps, line)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			steps = append(steps, instructions(item)...)
+		}
+	case map[string]any:
+		if elements, ok := v["itemListElement"]; ok {
+			return instructions(elements)
+		}
+		steps = append(steps, instructions(text(v))...)
+	}
+
+	return steps
+}
@@ -0,0 +1,75 @@
+// Package logging configura o log estruturado da aplicação (log/slog) e guarda no contexto o logger
+// de cada requisição, que já traz o ID da requisição. Os atributos com dados sensíveis, como o
+// cabeçalho Authorization e as senhas, são substituídos por "[REDACTED]".
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"main.go/config"
+)
+
+// redacted substitui os valores dos atributos sensíveis
+const redacted = "[REDACTED]"
+
+// sensitiveKeys são os nomes de atributos (sem diferenciar maiúsculas e minúsculas) nunca escritos no log
+var sensitiveKeys = []string{"authorization", "proxy-authorization", "cookie", "set-cookie", "password", "secret", "token"}
+
+type contextKey struct{}
+
+// New cria o logger no nível e no formato ("json" ou "text") da configuração, escrevendo em w.
+func New(cfg config.Log, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", cfg.Level)
+	}
+
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redact}
+
+	switch cfg.Format {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q", cfg.Format)
+	}
+}
+
+// WithContext guarda o logger no contexto.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext retorna o logger guardado no contexto, ou o logger padrão quando não houver um.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With guarda no contexto o logger do contexto acrescido dos atributos.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithContext(ctx, FromContext(ctx).With(args...))
+}
+
+// Funções privadas
+
+// redact oculta os valores dos atributos sensíveis, inclusive dentro de grupos (ex.: os cabeçalhos)
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindGroup {
+		return attr
+	}
+	key := strings.ToLower(attr.Key)
+	for _, sensitive := range sensitiveKeys {
+		if key == sensitive || strings.HasSuffix(key, "_"+sensitive) {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+	return attr
+}
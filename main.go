@@ -1,17 +1,36 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	// "github.com/swaggo/http-swagger"
 	// "github.com/swaggo/http-swagger/swaggerFiles"
 	"main.go/app"
+	"main.go/cli"
+	"main.go/config"
+	"main.go/cookbook"
 	"main.go/db"
+	"main.go/db/migrations"
+	"main.go/health"
+	"main.go/history"
+	"main.go/logging"
+	"main.go/metrics"
+	"main.go/middlewares"
+	"main.go/problem"
 	// "main.go/docs"
+	"main.go/render"
+	"main.go/repository"
 	"main.go/routes"
+	"main.go/server"
+	"main.go/storage"
+	"main.go/tracing"
+	"main.go/trash"
 )
 
 // @title           Cookbook API
@@ -32,16 +51,168 @@ import (
 // @Name Authorization
 
 func main() {
+	// Lê a configuração das variáveis de ambiente, do .env e do arquivo em CONFIG_FILE
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("Failed to load configuration", err)
+	}
+
+	// Log estruturado no nível e formato de LOG_LEVEL e LOG_FORMAT, usado também pelo pacote log.
+	// Valores inválidos mantêm o log padrão e são informados por Validate
+	if logger, err := logging.New(cfg.Log, os.Stdout); err == nil {
+		slog.SetDefault(logger)
+	}
+
+	// Comandos de manutenção (ex.: catalog import, migrate up), executados sem iniciar o servidor
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(cfg, os.Args[1:]))
+	}
+
+	// O servidor não inicia com uma configuração inválida (ex.: sem SECRET)
+	if err := cfg.Validate(); err != nil {
+		fatal("Invalid configuration", err)
+	}
+
+	// SIGINT e SIGTERM encerram o servidor e os workers; um segundo sinal encerra o processo na hora
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+
+	// Traces do OpenTelemetry, enviados conforme TRACING_EXPORTER
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+
 	// Inicializa conexão com banco e cria DAO
-	db := db.InitDB()
-	app := &app.App{DB: db}
+	db := db.InitDB(cfg.Database)
+
+	// Duração das consultas e estatísticas do pool de conexões no /metrics
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		fatal("Failed to register database metrics", err)
+	}
+
+	// Spans das consultas, filhos do span da requisição
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		fatal("Failed to register database tracing", err)
+	}
+
+	// Aplica as migrações pendentes; com MIGRATE_ON_START=false, elas devem ser aplicadas antes pelo
+	// comando migrate up, e o servidor não inicia enquanto houver alguma pendente
+	migrator, err := migrations.New(db)
+	if err != nil {
+		fatal("Failed to load migrations", err)
+	}
+	if !cfg.MigrateOnStart {
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			fatal("Failed to check migrations", err)
+		}
+		if len(pending) > 0 {
+			slog.Error("Pending migrations; run \"migrate up\" first", "count", len(pending), "first", pending[0].String())
+			os.Exit(1)
+		}
+	} else {
+		applied, err := migrator.Up(context.Background())
+		for _, migration := range applied {
+			slog.Info("Applied migration", "migration", migration.String())
+		}
+		if err != nil {
+			fatal("Failed to migrate database", err)
+		}
+	}
+
+	// Receitas criadas antes do histórico de revisões ganham uma primeira revisão com o estado atual
+	if err := history.Backfill(db); err != nil {
+		fatal("Failed to create initial recipe revisions", err)
+	}
+
+	store, signer := storage.InitStorage(cfg.Storage, cfg.Secret)
+
+	// Templates de exportação das receitas, que podem ser substituídos pelos arquivos em TEMPLATES_DIR
+	renderer, err := render.NewRenderer(cfg.TemplatesDir)
+	if err != nil {
+		fatal("Failed to load templates", err)
+	}
+
+	// Inicia os workers que geram os livros de receitas exportados
+	cookbooks := cookbook.NewExporter(db, store, 2)
+	cookbooks.Start(ctx)
+
+	// Remove definitivamente os itens que estão na lixeira há mais de TRASH_RETENTION_DAYS dias
+	purger := trash.NewPurger(db, store, cfg.TrashRetention())
+	purger.Start(ctx)
+
+	// Verificações do /readyz; outras dependências podem ser adicionadas com checks.Register
+	checks := health.NewRegistry()
+	checks.Register("database", health.Database(db))
+	checks.Register("migrations", health.Migrations(migrator))
+
+	app := &app.App{
+		Config:        cfg,
+		Users:         repository.NewGormUserRepository(db),
+		Recipes:       repository.NewGormRecipeRepository(db),
+		Ingredients:   repository.NewGormIngredientRepository(db),
+		Substitutions: repository.NewGormSubstitutionRepository(db),
+		Images:        repository.NewGormImageRepository(db),
+		Exports:       repository.NewGormExportRepository(db),
+		Catalog:       repository.NewGormCatalogRepository(db),
+		Storage:       store,
+		Signer:        signer,
+		Renderer:      renderer,
+		Cookbooks:     cookbooks,
+		Trash:         purger,
+		Health:        checks,
+	}
 
 	// Cria o router e registra as rotas do servidor
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	// r.Get("/swagger/*", httpSwagger.WrapHandler(swaggerFiles.Handler))
-	routes.RegisterRoutes(r, app)
+	r.Use(middlewares.RequestID)
+	r.MethodNotAllowed(problem.MethodNotAllowed)
+	routes.RegisterHealthRoutes(r, app)
+
+	// As rotas da API, inclusive as inexistentes (404), passam pelo log e pelas métricas das requisições
+	api := chi.NewRouter()
+	api.Use(middlewares.Tracing)
+	api.Use(middlewares.RequestLogger)
+	api.Use(middlewares.Metrics)
+	// Rotas e métodos inexistentes também respondem com application/problem+json
+	api.NotFound(problem.NotFound)
+	api.MethodNotAllowed(problem.MethodNotAllowed)
+	// api.Get("/swagger/*", httpSwagger.WrapHandler(swaggerFiles.Handler))
+	routes.RegisterRoutes(api, app)
+	r.Mount("/", api)
+
+	serveErr := server.Run(ctx, cfg.Server, r)
+	if serveErr != nil {
+		slog.Error("Server error", "error", serveErr)
+	}
+
+	// Espera os workers pararem antes de fechar as conexões com o banco
+	stop()
+	cookbooks.Wait()
+	purger.Wait()
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			slog.Error("Error closing database", "error", err)
+		}
+	}
+
+	// Envia os spans que ainda não foram exportados
+	tracingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(tracingCtx); err != nil {
+		slog.Error("Error shutting down tracing", "error", err)
+	}
+	cancel()
+
+	if serveErr != nil {
+		os.Exit(1)
+	}
+	slog.Info("Server stopped")
+}
 
-	log.Println("Server running on Port 3000")
-	http.ListenAndServe(":3000", r)
+// fatal registra o erro que impede o servidor de iniciar e encerra o processo
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
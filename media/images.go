@@ -0,0 +1,172 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxImageSize é o tamanho máximo, em bytes, aceito para o upload de uma imagem.
+const MaxImageSize = 5 << 20
+
+// MaxImagePixels é o número máximo de pixels (largura × altura) aceito para uma imagem. Um arquivo
+// pequeno pode declarar dimensões enormes, e decodificá-lo reservaria memória proporcional a elas.
+const MaxImagePixels = 40_000_000
+
+// ThumbnailSizes são as larguras máximas, em pixels, das miniaturas geradas para cada imagem.
+var ThumbnailSizes = []int{160, 480, 1024}
+
+var (
+	// ErrUnsupportedType indica que o conteúdo enviado não é um tipo de imagem aceito.
+	ErrUnsupportedType = errors.New("unsupported image type")
+	// ErrTooLarge indica que a imagem ultrapassa MaxImageSize.
+	ErrTooLarge = errors.New("image is too large")
+	// ErrTooManyPixels indica que as dimensões da imagem ultrapassam MaxImagePixels.
+	ErrTooManyPixels = errors.New("image dimensions are too large")
+)
+
+// Tipos de conteúdo aceitos e a extensão usada ao guardar o arquivo original
+var allowedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Image representa uma imagem validada e decodificada, pronta para ser guardada.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	decoded     image.Image
+}
+
+// Thumbnail representa uma miniatura gerada a partir de uma imagem.
+type Thumbnail struct {
+	Size        int
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// Validate verifica o tamanho e o tipo real do conteúdo (pelos bytes, não pelo nome do arquivo),
+// confere as dimensões declaradas no cabeçalho antes de decodificar e decodifica a imagem,
+// garantindo que o arquivo não está corrompido.
+func Validate(data []byte) (*Image, error) {
+	if len(data) > MaxImageSize {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrUnsupportedType)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return nil, ErrTooManyPixels
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+
+	bounds := decoded.Bounds()
+	return &Image{
+		Data:        data,
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		decoded:     decoded,
+	}, nil
+}
+
+// Thumbnails gera uma miniatura para cada tamanho em ThumbnailSizes menor que a imagem original,
+// mantendo a proporção. Imagens PNG e GIF geram miniaturas PNG (preservando transparência);
+// as demais geram JPEG.
+func Thumbnails(img *Image) ([]Thumbnail, error) {
+	var thumbnails []Thumbnail
+
+	for _, size := range ThumbnailSizes {
+		if size >= img.Width {
+			continue
+		}
+
+		width := size
+		height := img.Height * size / img.Width
+		if height < 1 {
+			height = 1
+		}
+
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img.decoded, img.decoded.Bounds(), draw.Over, nil)
+
+		var buf bytes.Buffer
+		thumbnail := Thumbnail{Size: size, Width: width, Height: height}
+
+		if img.ContentType == "image/png" || img.ContentType == "image/gif" {
+			if err := png.Encode(&buf, dst); err != nil {
+				return nil, err
+			}
+			thumbnail.ContentType = "image/png"
+			thumbnail.Extension = ".png"
+		} else {
+			if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+				return nil, err
+			}
+			thumbnail.ContentType = "image/jpeg"
+			thumbnail.Extension = ".jpg"
+		}
+
+		thumbnail.Data = buf.Bytes()
+		thumbnails = append(thumbnails, thumbnail)
+	}
+
+	return thumbnails, nil
+}
+
+// ToJPEG converte uma imagem em qualquer formato aceito para JPEG, usado pelos formatos
+// de exportação que não suportam todos os tipos de imagem. Imagens JPEG são devolvidas sem alteração.
+func ToJPEG(data []byte) ([]byte, error) {
+	if http.DetectContentType(data) == "image/jpeg" {
+		return data, nil
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Preenche o fundo de branco, pois o JPEG não tem transparência
+	bounds := decoded.Bounds()
+	dst := image.NewRGBA(bounds)
+	draw.Draw(dst, bounds, image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, bounds, decoded, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.NRGBA{R: 200, A: 128})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pngHeader monta apenas a assinatura e o cabeçalho IHDR de um PNG com as dimensões informadas,
+// como um arquivo pequeno que declara uma imagem enorme.
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12] = 8 // profundidade de cor
+	ihdr[13] = 6 // RGBA
+
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, 13)
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestValidate(t *testing.T) {
+	img, err := Validate(encodePNG(t, 300, 200))
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if img.ContentType != "image/png" || img.Extension != ".png" || img.Width != 300 || img.Height != 200 {
+		t.Fatalf("Validate = %s %s %dx%d", img.ContentType, img.Extension, img.Width, img.Height)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text", []byte("not an image"), ErrUnsupportedType},
+		{"truncated", encodePNG(t, 300, 200)[:100], ErrUnsupportedType},
+		{"too many bytes", append(encodePNG(t, 1, 1), make([]byte, MaxImageSize)...), ErrTooLarge},
+		{"too many pixels", pngHeader(50_000, 50_000), ErrTooManyPixels},
+		{"too wide", pngHeader(MaxImagePixels+1, 1), ErrTooManyPixels},
+	}
+	for _, tt := range tests {
+		if _, err := Validate(tt.data); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestThumbnails(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		sizes       []int
+		heights     []int
+		contentType string
+	}{
+		{"png keeps transparency", encodePNG(t, 1200, 600), []int{160, 480, 1024}, []int{80, 240, 512}, "image/png"},
+		{"jpeg", encodeJPEG(t, 500, 1000), []int{160, 480}, []int{320, 960}, "image/jpeg"},
+		{"smaller than every size", encodeJPEG(t, 160, 90), nil, nil, ""},
+		{"minimum height", encodePNG(t, 1000, 2), []int{160, 480}, []int{1, 1}, "image/png"},
+	}
+	for _, tt := range tests {
+		img, err := Validate(tt.data)
+		if err != nil {
+			t.Fatalf("%s: Validate: %v", tt.name, err)
+		}
+		thumbnails, err := Thumbnails(img)
+		if err != nil {
+			t.Fatalf("%s: Thumbnails: %v", tt.name, err)
+		}
+		if len(thumbnails) != len(tt.sizes) {
+			t.Fatalf("%s: %d thumbnails, want %d", tt.name, len(thumbnails), len(tt.sizes))
+		}
+
+		for i, thumbnail := range thumbnails {
+			if thumbnail.Size != tt.sizes[i] || thumbnail.Width != tt.sizes[i] || thumbnail.Height != tt.heights[i] {
+				t.Errorf("%s: thumbnail %d is %dx%d (size %d), want %dx%d", tt.name, i, thumbnail.Width, thumbnail.Height, thumbnail.Size, tt.sizes[i], tt.heights[i])
+			}
+			if thumbnail.ContentType != tt.contentType || http.DetectContentType(thumbnail.Data) != tt.contentType {
+				t.Errorf("%s: thumbnail %d is %s, want %s", tt.name, i, thumbnail.ContentType, tt.contentType)
+			}
+
+			config, _, err := image.DecodeConfig(bytes.NewReader(thumbnail.Data))
+			if err != nil || config.Width != thumbnail.Width || config.Height != thumbnail.Height {
+				t.Errorf("%s: thumbnail %d decodes to %dx%d (%v)", tt.name, i, config.Width, config.Height, err)
+			}
+		}
+	}
+}
+
+func TestToJPEG(t *testing.T) {
+	original := encodeJPEG(t, 10, 10)
+	if data, err := ToJPEG(original); err != nil || !bytes.Equal(data, original) {
+		t.Fatalf("ToJPEG changed a JPEG image (%v)", err)
+	}
+
+	data, err := ToJPEG(encodePNG(t, 40, 20))
+	if err != nil {
+		t.Fatalf("ToJPEG: %v", err)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || format != "jpeg" || config.Width != 40 || config.Height != 20 {
+		t.Fatalf("ToJPEG = %s %dx%d (%v)", format, config.Width, config.Height, err)
+	}
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// startKey guarda na instância da consulta o momento em que ela começou
+const startKey = "metrics:start"
+
+var (
+	dbQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_queries_total",
+		Help:      "Database queries by operation, table and result.",
+	}, []string{"operation", "table", "result"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+)
+
+// GormPlugin mede a duração das consultas feitas pelo GORM e registra as estatísticas do pool de
+// conexões (conexões abertas, em uso, esperas). Instalado com db.Use(metrics.GormPlugin{}).
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := Registry.Register(collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name())); err != nil {
+		return err
+	}
+
+	callback := db.Callback()
+	errs := []error{
+		callback.Create().Before("gorm:create").Register("metrics:before_create", before),
+		callback.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		callback.Query().Before("gorm:query").Register("metrics:before_query", before),
+		callback.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		callback.Update().Before("gorm:update").Register("metrics:before_update", before),
+		callback.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		callback.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		callback.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		callback.Row().Before("gorm:row").Register("metrics:before_row", before),
+		callback.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		callback.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		callback.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	}
+	return errors.Join(errs...)
+}
+
+// Funções privadas
+
+func before(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start := value.(time.Time)
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		result := "ok"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			result = "error"
+		}
+
+		dbQueries.WithLabelValues(operation, table, result).Inc()
+		dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	}
+}
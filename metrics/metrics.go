@@ -0,0 +1,105 @@
+// Package metrics expõe as métricas da API no formato do Prometheus: as requisições HTTP por rota,
+// as consultas e o pool de conexões do banco (pelo plugin do GORM) e os eventos de negócio, como
+// logins e receitas criadas.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace é o prefixo do nome das métricas
+const namespace = "cookbook"
+
+// Resultados do login
+const (
+	LoginSucceeded = "success"
+	LoginFailed    = "failure"
+)
+
+// Origens das receitas criadas
+const (
+	RecipeCreated = "create"
+	RecipeForked  = "fork"
+)
+
+// Registry guarda as métricas da API, junto com as do runtime do Go e do processo.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests being served.",
+	})
+
+	// Logins conta as tentativas de login por resultado (LoginSucceeded ou LoginFailed).
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+
+	// RecipesCreated conta as receitas criadas por origem (RecipeCreated ou RecipeForked).
+	RecipesCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recipes_created_total",
+		Help:      "Recipes created by source.",
+	}, []string{"source"})
+
+	// RecipeIngredientsAdded conta os ingredientes adicionados às receitas, na criação ou um a um.
+	RecipeIngredientsAdded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recipe_ingredients_added_total",
+		Help:      "Ingredients added to recipes.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, httpInFlight,
+		Logins, RecipesCreated, RecipeIngredientsAdded,
+		dbQueries, dbQueryDuration,
+	)
+}
+
+// Handler responde com as métricas do Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveRequest registra uma requisição HTTP concluída. A rota é o padrão do chi (ex.:
+// "/recipe/{id}"), para que o número de séries não cresça com os IDs.
+func ObserveRequest(method string, route string, status int, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// RequestStarted e RequestFinished acompanham as requisições em andamento.
+func RequestStarted() {
+	httpInFlight.Inc()
+}
+
+func RequestFinished() {
+	httpInFlight.Dec()
+}
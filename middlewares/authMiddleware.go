@@ -2,53 +2,104 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"main.go/app"
+	"main.go/logging"
+	"main.go/problem"
+	"main.go/repository"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extrai o cabeçalho Authorization
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header is missing", http.StatusUnauthorized)
-			return
-		}
-
-		// Retira o prefixo da autorização, deixando apenas o token (normalmente usado o formato 'Bearer <token>')
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			http.Error(w, "Invalid token format", http.StatusUnauthorized)
-			return
-		}
-
-		// Valida o token
-		secretKey := []byte(os.Getenv("SECRET"))
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) { // Faz-se parse do tokenString, que retorna a chave do token
-			// Verifica se o método de assinatura é o esperado (HS256)
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, http.ErrAbortHandler
+// AuthMiddleware exige o token JWT no cabeçalho Authorization, assinado com o secret da configuração,
+// e guarda o ID do usuário no contexto da requisição. O usuário do token precisa continuar
+// cadastrado: quem foi para a lixeira perde o acesso, mesmo com um token ainda válido.
+func AuthMiddleware(app *app.App) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Extrai o cabeçalho Authorization
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				problem.Write(w, r, http.StatusUnauthorized, problem.CodeMissingAuthorization, "Authorization header is missing")
+				return
 			}
-			return secretKey, nil
+
+			// Retira o prefixo da autorização, deixando apenas o token (normalmente usado o formato 'Bearer <token>')
+			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+			if tokenString == authHeader {
+				problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token format")
+				return
+			}
+
+			// Valida o token
+			secretKey := []byte(app.Config.Secret)
+			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) { // Faz-se parse do tokenString, que retorna a chave do token
+				// Verifica se o método de assinatura é o esperado (HS256)
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, http.ErrAbortHandler
+				}
+				return secretKey, nil
+			})
+
+			if err != nil || !token.Valid {
+				problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token")
+				return
+			}
+
+			// Pega e valida as claims do token
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+				return
+			}
+			sub, ok := claims["sub"].(float64)
+			if !ok || sub <= 0 {
+				problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+				return
+			}
+
+			// A busca ignora os usuários na lixeira
+			if _, err := app.Users.Get(r.Context(), uint(sub)); err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token")
+				} else {
+					logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+					problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+				}
+				return
+			}
+
+			// Adiciona as claims ao contexto da requisição para uso posterior
+			ctx := context.WithValue(r.Context(), "userID", claims["sub"])
+			ctx = logging.With(ctx, "user_id", claims["sub"])
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// OptionalAuthMiddleware identifica o usuário quando a requisição traz o cabeçalho Authorization, sem
+// exigi-lo. Usado nas rotas públicas que mostram ao autor as próprias receitas não publicadas.
+func OptionalAuthMiddleware(app *app.App) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		required := AuthMiddleware(app)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			required.ServeHTTP(w, r)
 		})
+	}
+}
 
-		if err != nil || !token.Valid {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
-
-		// Pega e valida as claims do token
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-			return
-		}
-
-		// Adiciona as claims ao contexto da requisição para uso posterior
-		ctx := context.WithValue(r.Context(), "userID", claims["sub"])
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+// GetUserID retorna o ID do usuário autenticado, extraído das claims do token pelo AuthMiddleware
+func GetUserID(r *http.Request) (uint, bool) {
+	// As claims numéricas do JWT são decodificadas como float64
+	sub, ok := r.Context().Value("userID").(float64)
+	if !ok || sub <= 0 {
+		return 0, false
+	}
+	return uint(sub), true
 }
@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"main.go/logging"
+)
+
+// RequestLogger registra cada requisição ao terminar, com o status, o tamanho e a duração da
+// resposta, no logger do contexto (que traz o ID da requisição). As respostas 5xx são registradas
+// como erro e, no nível debug, os cabeçalhos da requisição também são registrados, com os sensíveis
+// ocultos.
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		ctx := r.Context()
+		logger := logging.FromContext(ctx)
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Int("bytes", ww.BytesWritten()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.String("user_agent", r.UserAgent()),
+		}
+		if route := chi.RouteContext(ctx); route != nil && route.RoutePattern() != "" {
+			attrs = append(attrs, slog.String("route", route.RoutePattern()))
+		}
+		if logger.Enabled(ctx, slog.LevelDebug) {
+			headers := make([]any, 0, len(r.Header))
+			for name, values := range r.Header {
+				headers = append(headers, slog.Any(name, values))
+			}
+			attrs = append(attrs, slog.Group("headers", headers...))
+		}
+
+		logger.LogAttrs(ctx, level, "Request", attrs...)
+	})
+}
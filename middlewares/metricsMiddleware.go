@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"main.go/metrics"
+)
+
+// unmatchedRoute identifica nas métricas as requisições que não correspondem a nenhuma rota
+const unmatchedRoute = "unmatched"
+
+// Metrics registra a contagem e a duração das requisições pelo padrão da rota do chi (ex.:
+// "/recipe/{id}"), conhecido apenas depois de a requisição ser roteada.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		metrics.RequestStarted()
+		defer metrics.RequestFinished()
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		// Sem rota correspondente, o padrão é o da montagem do router da API ("/*")
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" && rctx.RoutePattern() != "/*" {
+			route = rctx.RoutePattern()
+		}
+
+		metrics.ObserveRequest(r.Method, route, status, time.Since(start))
+	})
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"main.go/logging"
+)
+
+// RequestIDHeader é o cabeçalho com o ID da requisição, recebido de um proxy ou gerado aqui
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength é o tamanho máximo aceito para o ID recebido
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestID identifica a requisição pelo cabeçalho X-Request-ID, gerando um ID quando ele não vier
+// ou for inválido, e o devolve na resposta. O logger do contexto passa a incluir o ID em todas as
+// mensagens da requisição.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		ctx = logging.With(ctx, "request_id", id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetRequestID retorna o ID da requisição definido pelo RequestID
+func GetRequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+// Funções privadas
+
+// validRequestID aceita apenas IDs curtos, com letras, números e os separadores comuns (ex.: UUIDs)
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"main.go/app"
+	"main.go/logging"
+	"main.go/problem"
+	"main.go/repository"
+)
+
+// RequireRole libera a rota apenas para usuários com um dos papéis informados. Deve ser usado
+// depois do AuthMiddleware; o papel é lido do banco, para que mudanças valham sem novo login.
+func RequireRole(app *app.App, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserID(r)
+			if !ok {
+				problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token claims")
+				return
+			}
+
+			user, err := app.Users.Get(r.Context(), userID)
+			if err != nil {
+				if errors.Is(err, repository.ErrNotFound) {
+					problem.Write(w, r, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid token")
+				} else {
+					logging.FromContext(r.Context()).Error("Error querying user", "error", err)
+					problem.Write(w, r, http.StatusInternalServerError, problem.CodeInternal, "")
+				}
+				return
+			}
+
+			for _, role := range roles {
+				if user.Role == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			problem.Write(w, r, http.StatusForbidden, problem.CodeForbidden, "")
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Situações possíveis de uma exportação de livro de receitas
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportDone    = "done"
+	ExportFailed  = "failed"
+)
+
+// CookbookExport representa uma exportação assíncrona de receitas como livro (EPUB e PDF).
+// @Description Modelo para acompanhar a exportação de um livro de receitas.
+type CookbookExport struct {
+	// ID é o identificador único da exportação.
+	ID uint `gorm:"primaryKey" json:"id"`
+	// UserID é o ID do usuário que pediu a exportação.
+	UserID uint `gorm:"not null;index" json:"user_id"`
+	// Title é o título do livro.
+	Title string `gorm:"not null" json:"title" example:"Receitas da vovó"`
+	// AuthorID é o ID do usuário cujas receitas compõem o livro, quando não for informada uma coleção.
+	AuthorID uint `json:"author_id,omitempty"`
+	// RecipeIDs é a coleção de receitas do livro, separadas por vírgula, quando informada.
+	RecipeIDs string `json:"-"`
+	// Status é a situação da exportação: pending, running, done ou failed.
+	Status string `gorm:"not null;index" json:"status" example:"done"`
+	// Error é a mensagem de erro, quando a exportação falhar.
+	Error string `json:"error,omitempty"`
+	// EpubKey e PdfKey são as chaves dos arquivos gerados no armazenamento.
+	EpubKey string `json:"-"`
+	PdfKey  string `json:"-"`
+	// EpubURL e PdfURL são os endereços para baixar os arquivos, quando a exportação terminar.
+	EpubURL string `gorm:"-" json:"epub_url,omitempty"`
+	PdfURL  string `gorm:"-" json:"pdf_url,omitempty"`
+	// CreatedAt é a data do pedido de exportação.
+	CreatedAt time.Time `json:"created_at"`
+	// FinishedAt é a data de término da exportação.
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// CookbookExportRequest representa o pedido de exportação de um livro de receitas.
+// @Description Modelo para pedir a exportação de um livro de receitas.
+type CookbookExportRequest struct {
+	// Title é o título do livro (opcional).
+	Title string `json:"title" example:"Receitas da vovó"`
+	// UserID é o ID do usuário cujas receitas compõem o livro (padrão: o usuário autenticado).
+	UserID uint `json:"user_id" example:"1"`
+	// RecipeIDs é uma coleção de receitas escolhidas; quando informada, substitui as receitas do usuário.
+	RecipeIDs []uint `json:"recipe_ids"`
+}
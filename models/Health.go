@@ -0,0 +1,54 @@
+package models
+
+// Status das verificações de saúde e de prontidão
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// Health representa a verificação de que o processo está no ar.
+// @Description O processo está no ar; não verifica as dependências.
+type Health struct {
+	// Status é sempre "ok".
+	Status string `json:"status" example:"ok"`
+}
+
+// Readiness representa a verificação de prontidão.
+// @Description Resultado das verificações das dependências da API. O status é "ok" apenas quando todas passam.
+type Readiness struct {
+	// Status é "ok" ou "unavailable".
+	Status string `json:"status" example:"ok" enums:"ok,unavailable"`
+	// Checks são os resultados de cada verificação, na ordem em que foram registradas.
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+// ReadinessCheck representa o resultado de uma verificação de prontidão.
+// @Description Resultado de uma verificação, com o erro quando ela falha.
+type ReadinessCheck struct {
+	// Name identifica a verificação.
+	Name string `json:"name" example:"database"`
+	// Status é "ok" ou "unavailable".
+	Status string `json:"status" example:"ok" enums:"ok,unavailable"`
+	// Error é o motivo da falha.
+	Error string `json:"error,omitempty" example:"2 pending migrations, starting with 0003_recipe_ratings"`
+	// DurationMs é o tempo da verificação em milissegundos.
+	DurationMs int64 `json:"duration_ms" example:"3"`
+}
+
+// BuildInfo representa a versão em execução.
+// @Description Versão do executável, com o commit de origem e a versão do Go.
+type BuildInfo struct {
+	// Version é a versão do módulo, "(devel)" quando compilado a partir do código.
+	Version string `json:"version" example:"(devel)"`
+	// Commit é o commit do código compilado.
+	Commit string `json:"commit,omitempty" example:"14d17c5a9e0f3b8c2d7e6f1a4b5c8d9e0f1a2b3c"`
+	// CommitTime é a data do commit.
+	CommitTime string `json:"commit_time,omitempty" example:"2026-10-19T06:06:24Z"`
+	// Modified indica que o código tinha alterações não commitadas.
+	Modified bool `json:"modified"`
+	// BuildTime é a data da compilação, definida no build com -ldflags (make build); ausente quando
+	// não informada.
+	BuildTime string `json:"build_time,omitempty" example:"2026-10-19T06:10:00Z"`
+	// GoVersion é a versão do Go usada na compilação.
+	GoVersion string `json:"go_version" example:"go1.23.2"`
+}
@@ -1,11 +1,21 @@
 package models
 
+import "gorm.io/gorm"
+
 // Ingredient representa um ingrediente utilizado em receitas.
 // @Description Modelo para gerenciamento de ingredientes.
 type Ingredient struct {
 	// ID é o identificador único do ingrediente.
 	ID uint `gorm:"primaryKey" json:"id"`
-	// Name é o nome do ingrediente.
+	// Name é o nome do ingrediente em português do Brasil. Nas respostas, é substituído pela tradução
+	// do idioma pedido no cabeçalho Accept-Language, quando houver.
     Name string `gorm:"unique;not null" json:"name" example:"Farinha de trigo."`
+	// Aliases são os nomes alternativos (sinônimos) do ingrediente, como os nomes de ingredientes duplicados unificados a ele.
+	Aliases []IngredientAlias `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE" json:"aliases,omitempty"`
+	// Translations são os nomes do ingrediente em outros idiomas.
+	Translations []IngredientTranslation `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE" json:"translations,omitempty"`
+	// DeletedAt é a data em que o ingrediente foi para a lixeira; as receitas continuam a exibi-lo até ele ser removido definitivamente.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
+	// DeletedBy é o usuário que moveu o ingrediente para a lixeira.
+	DeletedBy uint `gorm:"not null;default:0" json:"-" swaggerignore:"true"`
 }
-
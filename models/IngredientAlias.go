@@ -0,0 +1,12 @@
+package models
+
+// IngredientAlias representa um nome alternativo de um ingrediente.
+// @Description Nome alternativo (sinônimo) de um ingrediente, como "aipim" para "mandioca" ou o nome de um ingrediente duplicado que foi unificado a ele.
+type IngredientAlias struct {
+	// ID é o identificador único do nome alternativo.
+	ID uint `gorm:"primaryKey" json:"-"`
+	// IngredientID é o ID do ingrediente ao qual o nome pertence.
+	IngredientID uint `gorm:"not null;index" json:"-"`
+	// Name é o nome alternativo, único entre todos os nomes alternativos.
+	Name string `gorm:"not null;uniqueIndex" json:"name" example:"aipim"`
+}
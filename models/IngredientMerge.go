@@ -0,0 +1,45 @@
+package models
+
+// IngredientMergeRequest representa o pedido de unificação de ingredientes duplicados.
+// @Description Ingrediente canônico e os duplicados que serão unificados a ele.
+type IngredientMergeRequest struct {
+	// CanonicalID é o ID do ingrediente que será mantido.
+	CanonicalID uint `json:"canonical_id" example:"1"`
+	// DuplicateIDs são os IDs dos ingredientes que serão unificados ao canônico e removidos.
+	DuplicateIDs []uint `json:"duplicate_ids" example:"2,3"`
+}
+
+// IngredientMergeReport representa o resultado da unificação de ingredientes.
+// @Description Ingrediente canônico, com os nomes dos duplicados como nomes alternativos, e as receitas alteradas.
+type IngredientMergeReport struct {
+	// Ingredient é o ingrediente canônico após a unificação.
+	Ingredient Ingredient `json:"ingredient"`
+	// RecipeIDs são as receitas cujos ingredientes passaram a apontar para o canônico.
+	RecipeIDs []uint `json:"recipe_ids" example:"4,7"`
+}
+
+// IngredientUsage representa as receitas que usam um ingrediente.
+// @Description Receitas que usam o ingrediente e impedem sua remoção sem o parâmetro force.
+type IngredientUsage struct {
+	// IngredientID é o ID do ingrediente.
+	IngredientID uint `json:"ingredient_id" example:"1"`
+	// Count é o número de receitas que usam o ingrediente, inclusive as que o usuário não vê.
+	Count int64 `json:"count" example:"3"`
+	// Recipes são as receitas que usam o ingrediente e são visíveis para o usuário; rascunhos e
+	// receitas privadas de outros autores entram apenas na contagem.
+	Recipes []IngredientUsageRecipe `json:"recipes"`
+}
+
+// IngredientInUse representa o erro ao remover um ingrediente usado em receitas.
+// @Description Erro (RFC 7807) com as receitas que usam o ingrediente e impedem sua remoção sem o parâmetro force.
+type IngredientInUse struct {
+	Problem
+	IngredientUsage
+}
+
+// IngredientUsageRecipe representa uma receita que usa o ingrediente.
+// @Description Receita que usa o ingrediente.
+type IngredientUsageRecipe struct {
+	ID   uint   `json:"id" example:"4"`
+	Name string `json:"name" example:"bolo de cenoura"`
+}
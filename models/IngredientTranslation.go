@@ -0,0 +1,27 @@
+package models
+
+// Idiomas dos nomes dos ingredientes. O nome principal do ingrediente é o de DefaultLocale.
+const (
+	LocalePtBR = "pt-BR"
+	LocalePtPT = "pt-PT"
+	LocaleEn   = "en"
+	LocaleEs   = "es"
+
+	DefaultLocale = LocalePtBR
+)
+
+// Locales são os idiomas aceitos para os nomes dos ingredientes, na ordem de preferência.
+var Locales = []string{LocalePtBR, LocalePtPT, LocaleEn, LocaleEs}
+
+// IngredientTranslation representa o nome de um ingrediente em um idioma.
+// @Description Nome do ingrediente em um idioma, usado nas respostas conforme o cabeçalho Accept-Language.
+type IngredientTranslation struct {
+	// ID é o identificador único da tradução.
+	ID uint `gorm:"primaryKey" json:"-"`
+	// IngredientID é o ID do ingrediente traduzido.
+	IngredientID uint `gorm:"not null;uniqueIndex:idx_ingredient_translation" json:"-"`
+	// Locale é o idioma do nome (pt-BR, pt-PT, en ou es).
+	Locale string `gorm:"not null;uniqueIndex:idx_ingredient_translation" json:"locale" example:"en"`
+	// Name é o nome do ingrediente no idioma.
+	Name string `gorm:"not null" json:"name" example:"cassava"`
+}
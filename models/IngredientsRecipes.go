@@ -9,6 +9,16 @@ type IngredientsRecipes struct {
 	IngredientID uint `gorm:"primaryKey" json:"ingredient_id" swaggertype:"integer"`
 	// Quantity é a quantidade do ingrediente adicionado.
 	Quantity string `gorm:"not null" json:"quantity" swaggertype:"string" example:"200g"`
+	// Position é a posição do ingrediente na lista da receita, começando em 0.
+	Position int `gorm:"not null;default:0" json:"position" example:"0"`
+	// Group é o título do grupo de ingredientes ao qual o ingrediente pertence (ex.: "Massa", "Cobertura").
+	Group string `gorm:"column:group_name" json:"group,omitempty" example:"Massa"`
+	// Note é uma observação sobre o ingrediente (ex.: "peneirada").
+	Note string `json:"note,omitempty" example:"peneirada"`
+	// Optional indica que o ingrediente é opcional.
+	Optional bool `gorm:"not null;default:false" json:"optional,omitempty"`
+	// ToTaste indica que o ingrediente é usado a gosto; nesse caso a quantidade pode ficar vazia.
+	ToTaste bool `gorm:"not null;default:false" json:"to_taste,omitempty"`
 	// Ingredient é o objeto do ingrediente adicionado.
 	Ingredient Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE" json:"ingredient"`
 }
@@ -0,0 +1,23 @@
+package models
+
+// Problem representa uma resposta de erro no formato do RFC 7807 (application/problem+json).
+// @Description Erro da requisição, com um código estável para ser tratado pelos clientes e, nos erros de validação, a lista dos campos inválidos.
+type Problem struct {
+	// Type identifica o tipo do problema; "about:blank" indica que o status HTTP basta, e o código
+	// em Code distingue os erros.
+	Type string `json:"type" example:"about:blank"`
+	// Title é o texto do status HTTP.
+	Title string `json:"title" example:"Not Found"`
+	// Status é o status HTTP da resposta.
+	Status int `json:"status" example:"404"`
+	// Detail explica o erro desta requisição.
+	Detail string `json:"detail,omitempty" example:"Recipe not found"`
+	// Instance é o caminho da requisição.
+	Instance string `json:"instance,omitempty" example:"/recipe/42"`
+	// Code é o código estável do erro (ex.: recipe_not_found, validation_failed, invalid_token).
+	Code string `json:"code" example:"recipe_not_found"`
+	// RequestID é o ID da requisição, o mesmo do cabeçalho X-Request-ID e do log.
+	RequestID string `json:"request_id,omitempty" example:"4bf92f3577b34da6a3ce929d0e0e4736"`
+	// Errors são os campos inválidos, nos erros de validação.
+	Errors []FieldError `json:"errors,omitempty"`
+}
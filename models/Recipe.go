@@ -1,16 +1,103 @@
 package models
 
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados de visibilidade da receita
+const (
+	// VisibilityDraft é a receita em elaboração, visível apenas para o autor.
+	VisibilityDraft = "draft"
+	// VisibilityPrivate é a receita pronta, mas visível apenas para o autor.
+	VisibilityPrivate = "private"
+	// VisibilityUnlisted é a receita acessível por quem tem o endereço, fora das listagens.
+	VisibilityUnlisted = "unlisted"
+	// VisibilityPublished é a receita pública, presente nas listagens.
+	VisibilityPublished = "published"
+)
+
+// Visibilities são os estados de visibilidade aceitos.
+var Visibilities = []string{VisibilityDraft, VisibilityPrivate, VisibilityUnlisted, VisibilityPublished}
+
 // Recipe representa uma receita criada por um usuário.
 // @Description Modelo para gerenciamento de receitas.
 type Recipe struct {
 	// ID é o identificador único da receita.
 	ID uint `gorm:"primaryKey" json:"id"`
 	// UserID é o identificador do usuário que criou a receita.
-	UserID uint `gorm:"not null" json:"user_id"`
-	// Name é o nome da sua receita.
-	Name string `gorm:"unique;not null" json:"name" swaggertype:"string" example:"bolo de chocolate"`
+	UserID uint `gorm:"not null;uniqueIndex:idx_recipe_user_name;uniqueIndex:idx_recipe_user_slug" json:"user_id"`
+	// Name é o nome da sua receita, único entre as receitas do autor.
+	Name string `gorm:"not null;uniqueIndex:idx_recipe_user_name" json:"name" swaggertype:"string" example:"bolo de chocolate"`
+	// Slug identifica a receita na URL, único entre as receitas do autor (ex.: /user/seunome/recipe/bolo-de-chocolate).
+	Slug string `gorm:"not null;default:'';uniqueIndex:idx_recipe_user_slug" json:"slug" example:"bolo-de-chocolate"`
 	// Instructions representa as instruções sobre o modo de preparo da receita.
 	Instructions string `gorm:"not null" json:"instructions" swaggertype:"string" example:"Em uma tigela adicione a farinha, o açucar e o cacau em pó." `
+	// Servings é o número de porções que a receita rende.
+	Servings int `json:"servings,omitempty" example:"8"`
+	// PrepTime é o tempo de preparo, em minutos.
+	PrepTime int `json:"prep_time,omitempty" example:"20"`
+	// CookTime é o tempo de cozimento, em minutos.
+	CookTime int `json:"cook_time,omitempty" example:"40"`
+	// TotalTime é o tempo total da receita, em minutos.
+	TotalTime int `json:"total_time,omitempty" example:"60"`
+	// Visibility é o estado da receita: draft, private, unlisted ou published (padrão: published;
+	// cópias de outras receitas começam como draft).
+	Visibility string `gorm:"not null;default:published;index" json:"visibility" enums:"draft,private,unlisted,published" example:"published"`
+	// PublishAt agenda a publicação: receitas published ou unlisted só ficam visíveis para outros usuários a partir dessa data.
+	PublishAt *time.Time `gorm:"index" json:"publish_at,omitempty"`
+	// DeletedAt é a data em que a receita foi para a lixeira; receitas na lixeira não aparecem nas consultas.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
+	// DeletedBy é o usuário que moveu a receita para a lixeira.
+	DeletedBy uint `gorm:"not null;default:0" json:"-" swaggerignore:"true"`
 	// IngredientsRecipes representa o conjunto de ingredientes que pertence à receita.
     IngredientsRecipes []IngredientsRecipes `gorm:"foreignKey:RecipeID;constraint:OnDelete:CASCADE" json:"ingredients"`
+	// Images representa as imagens da receita e dos passos do modo de preparo.
+	Images []RecipeImage `gorm:"foreignKey:RecipeID;constraint:OnDelete:CASCADE" json:"images,omitempty"`
+	// ParentID é a receita da qual esta receita foi copiada (fork), para atribuição ao original.
+	ParentID *uint `gorm:"index" json:"parent_id,omitempty" example:"1"`
+	// ForkCount é o número de cópias (forks) publicadas feitas diretamente a partir desta receita.
+	ForkCount int64 `gorm:"-" json:"fork_count"`
+	// Forks representa as cópias feitas a partir desta receita.
+	Forks []Recipe `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL" json:"-" swaggerignore:"true"`
+	// SlugRedirects representa os slugs antigos da receita, mantidos após renomeações.
+	SlugRedirects []RecipeSlugRedirect `gorm:"foreignKey:RecipeID;constraint:OnDelete:CASCADE" json:"-" swaggerignore:"true"`
+	// Revisions representa o histórico de alterações da receita.
+	Revisions []RecipeRevision `gorm:"foreignKey:RecipeID;constraint:OnDelete:CASCADE" json:"-" swaggerignore:"true"`
+}
+
+// Steps divide as instruções da receita em passos, um por linha não vazia.
+func (r Recipe) Steps() []string {
+	var steps []string
+	for _, line := range strings.Split(r.Instructions, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
+
+// BeforeSave grava a data de publicação em UTC. O SQLite guarda as datas como texto, e só as
+// comparações entre datas no mesmo fuso (como as de catalog.Visible) dão o resultado correto.
+func (r *Recipe) BeforeSave(tx *gorm.DB) error {
+	if r.PublishAt != nil {
+		publishAt := r.PublishAt.UTC()
+		r.PublishAt = &publishAt
+	}
+	return nil
+}
+
+// VisibleTo indica se o usuário pode abrir a receita pelo endereço: o autor vê a receita em qualquer
+// estado; os demais, apenas as publicadas ou não listadas cuja data de publicação já chegou.
+func (r Recipe) VisibleTo(userID uint, now time.Time) bool {
+	if userID != 0 && r.UserID == userID {
+		return true
+	}
+	if r.Visibility != VisibilityPublished && r.Visibility != VisibilityUnlisted {
+		return false
+	}
+	return r.PublishAt == nil || !r.PublishAt.After(now)
 }
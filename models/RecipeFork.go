@@ -0,0 +1,43 @@
+package models
+
+// RecipeForkRequest representa as opções para copiar (fork) uma receita.
+// @Description Opções da cópia de uma receita.
+type RecipeForkRequest struct {
+	// Name é o nome da cópia (padrão: o nome da receita original).
+	Name string `json:"name,omitempty" example:"bolo de chocolate sem glúten"`
+	// Substitute é o motivo alimentar (ex.: vegan) cujas substituições são aplicadas aos ingredientes
+	// da cópia, gerando, por exemplo, a versão vegana da receita.
+	Substitute string `json:"substitute,omitempty" example:"vegan"`
+}
+
+// RecipeForkNode representa uma receita na árvore de cópias (forks).
+// @Description Receita da árvore de cópias, com as cópias feitas a partir dela.
+type RecipeForkNode struct {
+	// ID é o identificador da receita.
+	ID uint `json:"id" example:"2"`
+	// Name é o nome da receita.
+	Name string `json:"name" example:"bolo de chocolate"`
+	// UserID é o identificador do autor da receita.
+	UserID uint `json:"user_id" example:"1"`
+	// Author é o nome do autor da receita.
+	Author string `json:"author" example:"seunome"`
+	// Forks são as cópias feitas diretamente a partir desta receita.
+	Forks []RecipeForkNode `json:"forks"`
+}
+
+// RecipeForkDiff representa as diferenças entre uma cópia (fork) e a receita original.
+// @Description Diferenças da cópia em relação à receita original, no estado atual das duas.
+type RecipeForkDiff struct {
+	// ParentID é o identificador da receita original.
+	ParentID uint `json:"parent_id" example:"1"`
+	// RecipeID é o identificador da cópia.
+	RecipeID uint `json:"recipe_id" example:"2"`
+	// Fields são os campos simples alterados na cópia.
+	Fields []FieldChange `json:"fields"`
+	// Instructions é a comparação linha a linha do modo de preparo; vazia quando não mudou.
+	Instructions []LineChange `json:"instructions"`
+	// Ingredients são os ingredientes adicionados, removidos ou alterados na cópia.
+	Ingredients []IngredientChange `json:"ingredients"`
+	// Reordered indica que a ordem dos ingredientes mudou.
+	Reordered bool `json:"reordered"`
+}
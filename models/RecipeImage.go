@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// RecipeImage representa uma imagem enviada para uma receita ou para um passo do modo de preparo.
+// @Description Modelo para gerenciamento das imagens de receitas.
+type RecipeImage struct {
+	// ID é o identificador único da imagem.
+	ID uint `gorm:"primaryKey" json:"id"`
+	// RecipeID é o ID da receita à qual a imagem pertence.
+	RecipeID uint `gorm:"not null;index" json:"recipe_id"`
+	// Step é o número do passo do modo de preparo ilustrado pela imagem (nulo para a imagem da receita).
+	Step *int `json:"step,omitempty" example:"2"`
+	// Key é a chave do arquivo original no armazenamento.
+	Key string `gorm:"not null" json:"-"`
+	// ContentType é o tipo MIME da imagem original.
+	ContentType string `gorm:"not null" json:"content_type" example:"image/jpeg"`
+	// Size é o tamanho da imagem original em bytes.
+	Size int64 `gorm:"not null" json:"size"`
+	// Width e Height são as dimensões da imagem original em pixels.
+	Width  int `gorm:"not null" json:"width"`
+	Height int `gorm:"not null" json:"height"`
+	// URL é a URL assinada e temporária para acessar a imagem original.
+	URL string `gorm:"-" json:"url"`
+	// Thumbnails são as miniaturas geradas automaticamente a partir da imagem original.
+	Thumbnails []RecipeImageThumbnail `gorm:"foreignKey:ImageID;constraint:OnDelete:CASCADE" json:"thumbnails"`
+	// CreatedAt é a data de envio da imagem.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecipeImageThumbnail representa uma miniatura gerada a partir de uma imagem de receita.
+// @Description Modelo das miniaturas das imagens de receitas.
+type RecipeImageThumbnail struct {
+	// ID é o identificador único da miniatura.
+	ID uint `gorm:"primaryKey" json:"-"`
+	// ImageID é o ID da imagem original.
+	ImageID uint `gorm:"not null;index" json:"-"`
+	// Size é a largura máxima usada para gerar a miniatura.
+	Size int `gorm:"not null" json:"size" example:"480"`
+	// Key é a chave do arquivo da miniatura no armazenamento.
+	Key string `gorm:"not null" json:"-"`
+	// Width e Height são as dimensões da miniatura em pixels.
+	Width  int `gorm:"not null" json:"width"`
+	Height int `gorm:"not null" json:"height"`
+	// URL é a URL assinada e temporária para acessar a miniatura.
+	URL string `gorm:"-" json:"url"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// RecipeImportResult representa o resultado da importação de uma receita externa.
+// @Description Rascunho da receita importada e relatório das linhas que não puderam ser interpretadas.
+type RecipeImportResult struct {
+	// Recipe é o rascunho da receita, ainda não salvo, pronto para ser revisado e criado.
+	Recipe Recipe `json:"recipe"`
+	// UnmatchedIngredients são os nomes dos ingredientes que não correspondem a nenhum cadastrado. Eles
+	// ficam no rascunho sem ID e são cadastrados ao criar a receita com create_missing_ingredients.
+	UnmatchedIngredients []string `json:"unmatched_ingredients" example:"fava tonka"`
+	// Unparsed são as linhas que não puderam ser interpretadas.
+	Unparsed []RecipeImportIssue `json:"unparsed"`
+}
+
+// RecipeImportIssue representa uma linha do documento importado que não pôde ser interpretada.
+// @Description Linha não interpretada durante a importação de uma receita.
+type RecipeImportIssue struct {
+	// Field é o campo de origem da linha (name, ingredients, instructions, servings, prep_time, cook_time, total_time).
+	Field string `json:"field" example:"ingredients"`
+	// Line é o texto original da linha.
+	Line string `json:"line" example:"Recheio a seu gosto"`
+	// Reason é o motivo pelo qual a linha não foi interpretada.
+	Reason string `json:"reason" example:"quantity not recognized"`
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// RecipeRequest representa os dados para criar ou atualizar uma receita junto com seus ingredientes.
+// @Description Receita com a lista de ingredientes, gravadas juntas em uma única transação.
+type RecipeRequest struct {
+	// UserID é o identificador do dono da receita, informado apenas por administradores (padrão: o
+	// usuário autenticado, na criação, e o dono atual, na atualização).
+	UserID uint `json:"user_id" example:"1"`
+	// Name é o nome da receita.
+	Name string `json:"name" example:"bolo de chocolate"`
+	// Instructions são as instruções do modo de preparo, um passo por linha.
+	Instructions string `json:"instructions" example:"Em uma tigela adicione a farinha, o açucar e o cacau em pó."`
+	// Servings é o número de porções que a receita rende.
+	Servings int `json:"servings,omitempty" example:"8"`
+	// PrepTime é o tempo de preparo, em minutos.
+	PrepTime int `json:"prep_time,omitempty" example:"20"`
+	// CookTime é o tempo de cozimento, em minutos.
+	CookTime int `json:"cook_time,omitempty" example:"40"`
+	// TotalTime é o tempo total da receita, em minutos.
+	TotalTime int `json:"total_time,omitempty" example:"60"`
+	// Ingredients é a lista completa de ingredientes da receita. Na atualização, quando omitida,
+	// os ingredientes atuais são mantidos.
+	Ingredients []RecipeIngredientRequest `json:"ingredients"`
+	// CreateMissingIngredients cadastra os ingredientes informados pelo nome que ainda não existem.
+	CreateMissingIngredients bool `json:"create_missing_ingredients" example:"false"`
+	// Visibility é o estado da receita: draft, private, unlisted ou published (padrão: published na
+	// criação; na atualização, quando omitido, o estado atual é mantido).
+	Visibility string `json:"visibility,omitempty" enums:"draft,private,unlisted,published" example:"published"`
+	// PublishAt agenda a publicação de receitas published ou unlisted para uma data futura. Na
+	// atualização, quando omitido, o agendamento atual é mantido; null o cancela.
+	PublishAt *time.Time `json:"publish_at,omitempty" example:"2025-12-24T09:00:00Z"`
+	// HasPublishAt indica que publish_at foi informado no JSON, mesmo que como null.
+	HasPublishAt bool `json:"-" swaggerignore:"true"`
+	// Summary é o resumo da alteração registrado no histórico (padrão: gerado a partir das diferenças).
+	Summary string `json:"summary,omitempty" example:"menos açúcar na massa"`
+}
+
+// UnmarshalJSON lê a receita sem aceitar campos desconhecidos, registrando se publish_at foi
+// informado: sem isso, omiti-lo e enviá-lo como null teriam o mesmo resultado.
+func (r *RecipeRequest) UnmarshalJSON(data []byte) error {
+	type recipeRequest RecipeRequest
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode((*recipeRequest)(r)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	_, r.HasPublishAt = fields["publish_at"]
+	return nil
+}
+
+// RecipeIngredientRequest representa um ingrediente da receita, identificado pelo ID ou pelo nome.
+// @Description Ingrediente da receita, informado pelo ID ou pelo nome do ingrediente cadastrado.
+type RecipeIngredientRequest struct {
+	// IngredientID é o ID do ingrediente cadastrado.
+	IngredientID uint `json:"ingredient_id,omitempty" example:"3"`
+	// Name é o nome do ingrediente, usado quando o ID não é informado (sem diferenciar maiúsculas e minúsculas).
+	Name string `json:"name,omitempty" example:"Farinha de trigo"`
+	// Quantity é a quantidade do ingrediente; pode ficar vazia quando o ingrediente é usado a gosto.
+	Quantity string `json:"quantity" example:"200g"`
+	// Group é o título do grupo de ingredientes (ex.: "Massa", "Cobertura").
+	Group string `json:"group,omitempty" example:"Massa"`
+	// Note é uma observação sobre o ingrediente (ex.: "peneirada").
+	Note string `json:"note,omitempty" example:"peneirada"`
+	// Optional indica que o ingrediente é opcional.
+	Optional bool `json:"optional,omitempty"`
+	// ToTaste indica que o ingrediente é usado a gosto.
+	ToTaste bool `json:"to_taste,omitempty"`
+}
+
+// RecipeIngredientUpdate representa os novos dados de um ingrediente da receita.
+// @Description Dados de um ingrediente da receita que podem ser alterados.
+type RecipeIngredientUpdate struct {
+	// Quantity é a quantidade do ingrediente; pode ficar vazia quando o ingrediente é usado a gosto.
+	Quantity string `json:"quantity" example:"250g"`
+	// Group é o título do grupo de ingredientes (ex.: "Massa", "Cobertura").
+	Group string `json:"group,omitempty" example:"Massa"`
+	// Note é uma observação sobre o ingrediente (ex.: "peneirada").
+	Note string `json:"note,omitempty" example:"peneirada"`
+	// Optional indica que o ingrediente é opcional.
+	Optional bool `json:"optional,omitempty"`
+	// ToTaste indica que o ingrediente é usado a gosto.
+	ToTaste bool `json:"to_taste,omitempty"`
+}
+
+// RecipeIngredientOrder representa a nova ordem dos ingredientes da receita.
+// @Description IDs de todos os ingredientes da receita, na ordem desejada.
+type RecipeIngredientOrder struct {
+	// IngredientIDs são os IDs dos ingredientes da receita, cada um uma única vez.
+	IngredientIDs []uint `json:"ingredient_ids" example:"3,1,2"`
+}
+
+// FieldError representa um erro de validação de um campo da requisição.
+// @Description Erro de validação de um campo ou de um item de lista da requisição.
+type FieldError struct {
+	// Field é o caminho do campo inválido.
+	Field string `json:"field" example:"ingredients[1].quantity"`
+	// Message descreve o problema encontrado.
+	Message string `json:"message" example:"quantity is required"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// RecipeRevision representa uma versão imutável de uma receita e seus ingredientes.
+// @Description Revisão do histórico de uma receita, registrada a cada alteração.
+type RecipeRevision struct {
+	// ID é o identificador único da revisão.
+	ID uint `gorm:"primaryKey" json:"id"`
+	// RecipeID é o identificador da receita.
+	RecipeID uint `gorm:"not null;uniqueIndex:idx_recipe_revision" json:"recipe_id"`
+	// Number é o número sequencial da revisão dentro da receita, começando em 1.
+	Number int `gorm:"not null;uniqueIndex:idx_recipe_revision" json:"number" example:"3"`
+	// AuthorID é o usuário que fez a alteração (0 para alterações feitas pela linha de comando).
+	AuthorID uint `gorm:"not null;default:0" json:"author_id"`
+	// Author é o nome do usuário que fez a alteração.
+	Author string `gorm:"-" json:"author,omitempty" example:"seunome"`
+	// Summary é o resumo da alteração.
+	Summary string `gorm:"not null" json:"summary" example:"changed quantity of Farinha"`
+	// Snapshot guarda o estado da receita em JSON.
+	Snapshot string `gorm:"type:text;not null" json:"-"`
+	// Recipe é o estado da receita nesta revisão, retornado apenas na consulta de uma revisão.
+	Recipe *RecipeSnapshot `gorm:"-" json:"recipe,omitempty"`
+	// CreatedAt é a data da alteração.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RecipeSnapshot representa o estado de uma receita guardado em uma revisão. Visibility e PublishAt
+// ficam vazios nas revisões registradas antes de passarem a ser guardados.
+// @Description Estado da receita e de seus ingredientes em uma revisão.
+type RecipeSnapshot struct {
+	UserID       uint                       `json:"user_id"`
+	Name         string                     `json:"name"`
+	Instructions string                     `json:"instructions"`
+	Servings     int                        `json:"servings,omitempty"`
+	PrepTime     int                        `json:"prep_time,omitempty"`
+	CookTime     int                        `json:"cook_time,omitempty"`
+	TotalTime    int                        `json:"total_time,omitempty"`
+	Visibility   string                     `json:"visibility,omitempty"`
+	PublishAt    *time.Time                 `json:"publish_at,omitempty"`
+	Ingredients  []RecipeSnapshotIngredient `json:"ingredients"`
+}
+
+// RecipeSnapshotIngredient representa um ingrediente da receita guardado em uma revisão.
+// @Description Ingrediente da receita em uma revisão; o nome permite restaurar ingredientes removidos do catálogo.
+type RecipeSnapshotIngredient struct {
+	IngredientID uint   `json:"ingredient_id"`
+	Name         string `json:"name"`
+	Quantity     string `json:"quantity"`
+	Group        string `json:"group,omitempty"`
+	Note         string `json:"note,omitempty"`
+	Optional     bool   `json:"optional,omitempty"`
+	ToTaste      bool   `json:"to_taste,omitempty"`
+}
+
+// RecipeRevisionDiff representa as diferenças entre duas revisões de uma receita.
+// @Description Diferenças entre duas revisões: campos alterados, linhas do modo de preparo e ingredientes.
+type RecipeRevisionDiff struct {
+	// From é o número da revisão de origem.
+	From int `json:"from" example:"1"`
+	// To é o número da revisão de destino.
+	To int `json:"to" example:"3"`
+	// Fields são os campos simples alterados.
+	Fields []FieldChange `json:"fields"`
+	// Instructions é a comparação linha a linha do modo de preparo; vazia quando não mudou.
+	Instructions []LineChange `json:"instructions"`
+	// Ingredients são os ingredientes adicionados, removidos ou alterados.
+	Ingredients []IngredientChange `json:"ingredients"`
+	// Reordered indica que a ordem dos ingredientes mudou.
+	Reordered bool `json:"reordered"`
+}
+
+// FieldChange representa a alteração de um campo.
+// @Description Campo alterado entre duas revisões.
+type FieldChange struct {
+	Field string `json:"field" example:"servings"`
+	Old   string `json:"old" example:"8"`
+	New   string `json:"new" example:"12"`
+}
+
+// LineChange representa uma linha da comparação do modo de preparo.
+// @Description Linha da comparação: equal (sem mudança), insert (adicionada) ou delete (removida).
+type LineChange struct {
+	Op   string `json:"op" example:"insert"`
+	Text string `json:"text" example:"Asse por 40 minutos."`
+}
+
+// IngredientChange representa a alteração de um ingrediente da receita.
+// @Description Ingrediente adicionado (added), removido (removed) ou alterado (changed) entre duas revisões.
+type IngredientChange struct {
+	Op           string        `json:"op" example:"changed"`
+	IngredientID uint          `json:"ingredient_id" example:"3"`
+	Name         string        `json:"name" example:"Farinha de trigo"`
+	Fields       []FieldChange `json:"fields,omitempty"`
+}
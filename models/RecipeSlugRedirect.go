@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RecipeSlugRedirect representa um slug antigo de uma receita, mantido após a renomeação para que
+// os links antigos continuem funcionando.
+// @Description Slug antigo de uma receita, redirecionado para o slug atual.
+type RecipeSlugRedirect struct {
+	// ID é o identificador único do redirecionamento.
+	ID uint `gorm:"primaryKey" json:"id"`
+	// RecipeID é o identificador da receita.
+	RecipeID uint `gorm:"not null;index" json:"recipe_id"`
+	// UserID é o autor da receita quando o slug estava em uso.
+	UserID uint `gorm:"not null;uniqueIndex:idx_recipe_slug_redirect" json:"user_id"`
+	// Slug é o slug antigo.
+	Slug string `gorm:"not null;uniqueIndex:idx_recipe_slug_redirect" json:"slug" example:"bolo-de-cenoura"`
+	// CreatedAt é a data em que o slug deixou de ser usado.
+	CreatedAt time.Time `json:"created_at"`
+}
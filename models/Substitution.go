@@ -0,0 +1,99 @@
+package models
+
+// Motivos alimentares das substituições
+const (
+	ReasonVegan      = "vegan"
+	ReasonVegetarian = "vegetarian"
+	ReasonDairyFree  = "dairy-free"
+	ReasonGlutenFree = "gluten-free"
+	ReasonEggFree    = "egg-free"
+)
+
+// SubstitutionReasons são os motivos aceitos nas substituições. Substituições sem motivo valem para
+// qualquer caso (ex.: falta do ingrediente).
+var SubstitutionReasons = []string{ReasonVegan, ReasonVegetarian, ReasonDairyFree, ReasonGlutenFree, ReasonEggFree}
+
+// Substitution representa uma forma de substituir um ingrediente por outros.
+// @Description Substituição de um ingrediente por um ou mais ingredientes, com a proporção de cada um (ex.: leitelho por leite e suco de limão).
+type Substitution struct {
+	// ID é o identificador único da substituição.
+	ID uint `gorm:"primaryKey" json:"id"`
+	// IngredientID é o ID do ingrediente substituído.
+	IngredientID uint `gorm:"not null;index" json:"ingredient_id" example:"12"`
+	// Ingredient é o ingrediente substituído.
+	Ingredient Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE" json:"ingredient"`
+	// Reason é o motivo alimentar da substituição (vegan, vegetarian, dairy-free, gluten-free ou egg-free); vazio vale para qualquer caso.
+	Reason string `gorm:"not null;default:'';index" json:"reason,omitempty" example:"vegan"`
+	// Notes são observações sobre a substituição.
+	Notes string `json:"notes,omitempty" example:"Misture e deixe descansar por 10 minutos antes de usar."`
+	// Replacements são os ingredientes que entram no lugar do substituído.
+	Replacements []SubstitutionItem `gorm:"constraint:OnDelete:CASCADE" json:"replacements"`
+}
+
+// SubstitutionItem representa um dos ingredientes que entram no lugar do substituído.
+// @Description Ingrediente usado na substituição, com a proporção em relação à quantidade do substituído.
+type SubstitutionItem struct {
+	// ID é o identificador único do item.
+	ID uint `gorm:"primaryKey" json:"-"`
+	// SubstitutionID é o ID da substituição à qual o item pertence.
+	SubstitutionID uint `gorm:"not null;index" json:"-"`
+	// IngredientID é o ID do ingrediente usado na substituição.
+	IngredientID uint `gorm:"not null;index" json:"ingredient_id" example:"7"`
+	// Ingredient é o ingrediente usado na substituição.
+	Ingredient Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE" json:"ingredient"`
+	// Ratio é a quantidade do ingrediente para cada unidade do substituído (ex.: 0.95 de leite para 1 de leitelho).
+	Ratio float64 `gorm:"not null;default:1" json:"ratio" example:"0.95"`
+	// Note é uma observação sobre o item.
+	Note string `json:"note,omitempty" example:"integral"`
+}
+
+// SubstitutionRequest representa os dados para cadastrar ou alterar uma substituição.
+// @Description Ingrediente substituído, motivo, observações e ingredientes que entram no lugar dele.
+type SubstitutionRequest struct {
+	IngredientID uint                      `json:"ingredient_id" example:"12"`
+	Reason       string                    `json:"reason" example:"vegan"`
+	Notes        string                    `json:"notes" example:"Misture e deixe descansar por 10 minutos antes de usar."`
+	Replacements []SubstitutionItemRequest `json:"replacements"`
+}
+
+// SubstitutionItemRequest representa um ingrediente que entra no lugar do substituído.
+// @Description Ingrediente usado na substituição; sem proporção, é usada a mesma quantidade do substituído.
+type SubstitutionItemRequest struct {
+	IngredientID uint    `json:"ingredient_id" example:"7"`
+	Ratio        float64 `json:"ratio" example:"0.95"`
+	Note         string  `json:"note" example:"integral"`
+}
+
+// RecipeSubstitutions representa as substituições sugeridas para os ingredientes de uma receita.
+// @Description Substituições sugeridas para cada ingrediente da receita, com as quantidades já calculadas.
+type RecipeSubstitutions struct {
+	RecipeID    uint                            `json:"recipe_id" example:"4"`
+	Ingredients []RecipeIngredientSubstitutions `json:"ingredients"`
+}
+
+// RecipeIngredientSubstitutions representa as substituições sugeridas para um ingrediente da receita.
+// @Description Ingrediente da receita e as substituições possíveis para ele.
+type RecipeIngredientSubstitutions struct {
+	IngredientID  uint                     `json:"ingredient_id" example:"12"`
+	Name          string                   `json:"name" example:"leitelho"`
+	Quantity      string                   `json:"quantity" example:"1 xícara"`
+	Substitutions []SubstitutionSuggestion `json:"substitutions"`
+}
+
+// SubstitutionSuggestion representa uma substituição aplicada a um ingrediente da receita.
+// @Description Substituição com as quantidades calculadas a partir da quantidade do ingrediente na receita.
+type SubstitutionSuggestion struct {
+	SubstitutionID uint                   `json:"substitution_id" example:"3"`
+	Reason         string                 `json:"reason,omitempty" example:"vegan"`
+	Notes          string                 `json:"notes,omitempty"`
+	Replacements   []SuggestedReplacement `json:"replacements"`
+}
+
+// SuggestedReplacement representa um ingrediente da substituição com a quantidade calculada.
+// @Description Ingrediente da substituição e a quantidade a usar na receita.
+type SuggestedReplacement struct {
+	IngredientID uint   `json:"ingredient_id" example:"7"`
+	Name         string `json:"name" example:"leite"`
+	Quantity     string `json:"quantity" example:"0,95 xícara"`
+	Note         string `json:"note,omitempty"`
+}
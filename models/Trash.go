@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Trash representa a lixeira de um usuário.
+// @Description Receitas e ingredientes na lixeira, que podem ser restaurados até serem removidos definitivamente.
+type Trash struct {
+	// Recipes são as receitas do usuário (para administradores, todas) que estão na lixeira.
+	Recipes []TrashItem `json:"recipes"`
+	// Ingredients são os ingredientes na lixeira, listados apenas para administradores.
+	Ingredients []TrashItem `json:"ingredients"`
+}
+
+// TrashItem representa um item da lixeira.
+// @Description Item da lixeira, com a data em que será removido definitivamente.
+type TrashItem struct {
+	// ID é o identificador do item.
+	ID uint `json:"id" example:"3"`
+	// Name é o nome do item.
+	Name string `json:"name" example:"bolo de chocolate"`
+	// DeletedAt é a data em que o item foi para a lixeira.
+	DeletedAt time.Time `json:"deleted_at"`
+	// PurgeAt é a data a partir da qual o item é removido definitivamente.
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// TrashPurgeReport representa o resultado da limpeza da lixeira.
+// @Description Quantidade de itens removidos definitivamente da lixeira.
+type TrashPurgeReport struct {
+	Recipes     int64 `json:"recipes" example:"2"`
+	Ingredients int64 `json:"ingredients" example:"1"`
+	Users       int64 `json:"users" example:"0"`
+}
@@ -1,5 +1,14 @@
 package models
 
+import "gorm.io/gorm"
+
+// Papéis dos usuários
+const (
+	RoleUser   = "user"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
 // User representa um usuário do sistema.
 // @Description Modelo para gerenciar os usuários do sistema.
 type User struct {
@@ -11,6 +20,11 @@ type User struct {
 	Email string `gorm:"unique;not null" example:"seuemail@gmail.com"`
 	// Password é a senha de entrada do usuário no sistema.
 	Password string `gorm:"not null"`
+	// Role é o papel do usuário, que libera as rotas administrativas (user, editor ou admin). Editores
+	// cuidam do conteúdo de referência, como as substituições de ingredientes.
+	Role string `gorm:"not null;default:user" example:"user"`
+	// DeletedAt é a data em que o usuário foi para a lixeira; usuários na lixeira não conseguem entrar no sistema.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
 }
 
 // UserLoginRequest representa as informações de login do usuário no sistema.
@@ -0,0 +1,138 @@
+// Package problem escreve as respostas de erro da API no formato do RFC 7807
+// (application/problem+json), com um código estável para cada erro, os campos inválidos nos erros
+// de validação e o ID da requisição.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"main.go/models"
+)
+
+// ContentType é o tipo das respostas de erro.
+const ContentType = "application/problem+json"
+
+// requestIDHeader é o cabeçalho da resposta com o ID da requisição, definido pelo
+// middlewares.RequestID antes dos handlers
+const requestIDHeader = "X-Request-ID"
+
+// Códigos dos erros. Os códigos não mudam entre versões da API; novos erros ganham novos códigos.
+const (
+	CodeInvalidJSON      = "invalid_json"
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeValidationFailed = "validation_failed"
+
+	CodeMissingAuthorization = "missing_authorization"
+	CodeInvalidToken         = "invalid_token"
+	CodeInvalidCredentials   = "invalid_credentials"
+	CodeForbidden            = "forbidden"
+	CodeInvalidSignature     = "invalid_signature"
+
+	CodeNotFound             = "not_found"
+	CodeRecipeNotFound       = "recipe_not_found"
+	CodeIngredientNotFound   = "ingredient_not_found"
+	CodeUserNotFound         = "user_not_found"
+	CodeRevisionNotFound     = "revision_not_found"
+	CodeSubstitutionNotFound = "substitution_not_found"
+	CodeImageNotFound        = "image_not_found"
+	CodeAliasNotFound        = "alias_not_found"
+	CodeTranslationNotFound  = "translation_not_found"
+	CodeExportNotFound       = "export_not_found"
+	CodeMediaNotFound        = "media_not_found"
+	CodeNoRecipeFound        = "no_recipe_found"
+
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeConflict             = "conflict"
+	CodeIngredientInUse      = "ingredient_in_use"
+	CodeNameInUse            = "name_in_use"
+	CodeExportNotFinished    = "export_not_finished"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+
+	CodeInternal        = "internal_error"
+	CodeExportQueueFull = "export_queue_full"
+)
+
+// Write responde com o erro do status e do código informados. O detalhe é opcional.
+func Write(w http.ResponseWriter, r *http.Request, status int, code string, detail string) {
+	write(w, r, models.Problem{Status: status, Code: code, Detail: detail}, nil)
+}
+
+// WriteExtended responde com o erro acrescido dos campos de extension (os membros de extensão do
+// RFC 7807), como a lista das receitas que impedem a remoção de um ingrediente.
+func WriteExtended(w http.ResponseWriter, r *http.Request, status int, code string, detail string, extension any) {
+	write(w, r, models.Problem{Status: status, Code: code, Detail: detail}, extension)
+}
+
+// WriteValidation responde com os erros de validação dos campos da requisição (422).
+func WriteValidation(w http.ResponseWriter, r *http.Request, errs []models.FieldError) {
+	write(w, r, models.Problem{
+		Status: http.StatusUnprocessableEntity,
+		Code:   CodeValidationFailed,
+		Detail: "The request has invalid fields",
+		Errors: errs,
+	}, nil)
+}
+
+// NotFound responde às requisições sem rota correspondente.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusNotFound, CodeNotFound, "No route matches "+r.URL.Path)
+}
+
+// MethodNotAllowed responde às requisições com um método não aceito pela rota.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path)
+}
+
+// Funções privadas
+
+// write completa e escreve o erro; os campos de extension não substituem os do erro
+func write(w http.ResponseWriter, r *http.Request, problem models.Problem, extension any) {
+	problem.Type = "about:blank"
+	problem.Title = http.StatusText(problem.Status)
+	problem.Instance = r.URL.Path
+	problem.RequestID = w.Header().Get(requestIDHeader)
+
+	problemJson, err := json.Marshal(problem)
+	if err == nil && extension != nil {
+		problemJson, err = merge(problemJson, extension)
+	}
+	if err != nil {
+		http.Error(w, problem.Title, problem.Status)
+		return
+	}
+
+	// Remove os cabeçalhos de uma resposta de sucesso que não se aplicam ao erro
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	w.Write(problemJson)
+}
+
+// merge acrescenta ao objeto JSON do erro os campos do objeto extension que ele ainda não tem
+func merge(problemJson []byte, extension any) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(problemJson, &fields); err != nil {
+		return nil, err
+	}
+
+	extensionJson, err := json.Marshal(extension)
+	if err != nil {
+		return nil, err
+	}
+	var extensionFields map[string]json.RawMessage
+	if err := json.Unmarshal(extensionJson, &extensionFields); err != nil {
+		return nil, err
+	}
+
+	for name, value := range extensionFields {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,56 @@
+package render
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// JSONLD gera o documento schema.org Recipe da receita, usado por mecanismos de busca.
+func JSONLD(view RecipeView) ([]byte, error) {
+	doc := map[string]any{
+		"@context": "https://schema.org",
+		"@type":    "Recipe",
+		"name":     view.Name,
+	}
+
+	if view.Author != "" {
+		doc["author"] = map[string]any{"@type": "Person", "name": view.Author}
+	}
+	if len(view.Images) > 0 {
+		doc["image"] = view.Images
+	}
+	if view.Servings > 0 {
+		doc["recipeYield"] = strconv.Itoa(view.Servings)
+	}
+	if view.PrepTime > 0 {
+		doc["prepTime"] = ISODuration(view.PrepTime)
+	}
+	if view.CookTime > 0 {
+		doc["cookTime"] = ISODuration(view.CookTime)
+	}
+	if view.TotalTime > 0 {
+		doc["totalTime"] = ISODuration(view.TotalTime)
+	}
+
+	ingredients := []string{}
+	for _, ingredient := range view.Ingredients {
+		ingredients = append(ingredients, ingredient.Text())
+	}
+	doc["recipeIngredient"] = ingredients
+
+	steps := []map[string]any{}
+	for _, step := range view.Steps {
+		howTo := map[string]any{
+			"@type":    "HowToStep",
+			"position": step.Number,
+			"text":     step.Text,
+		}
+		if len(step.Images) > 0 {
+			howTo["image"] = step.Images[0]
+		}
+		steps = append(steps, howTo)
+	}
+	doc["recipeInstructions"] = steps
+
+	return json.MarshalIndent(doc, "", "  ")
+}
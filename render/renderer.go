@@ -0,0 +1,87 @@
+package render
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+var funcs = map[string]any{
+	"minutes": Minutes,
+}
+
+// Renderer gera as representações em Markdown e HTML das receitas a partir de templates Go.
+// Os templates padrão ficam embutidos no binário e podem ser substituídos por arquivos de
+// mesmo nome (ex.: recipe.html.tmpl) em um diretório informado na configuração.
+type Renderer struct {
+	markdown *texttemplate.Template
+	html     *htmltemplate.Template
+}
+
+// NewRenderer carrega os templates, dando preferência aos encontrados em overrideDir (se informado).
+func NewRenderer(overrideDir string) (*Renderer, error) {
+	markdownSrc, err := loadTemplate(overrideDir, "recipe.md.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	markdown, err := texttemplate.New("recipe.md").Funcs(funcs).Parse(markdownSrc)
+	if err != nil {
+		return nil, fmt.Errorf("render: parsing recipe.md.tmpl: %w", err)
+	}
+
+	htmlSrc, err := loadTemplate(overrideDir, "recipe.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("recipe.html").Funcs(funcs).Parse(htmlSrc)
+	if err != nil {
+		return nil, fmt.Errorf("render: parsing recipe.html.tmpl: %w", err)
+	}
+
+	return &Renderer{markdown: markdown, html: html}, nil
+}
+
+// Markdown gera a receita em Markdown.
+func (r *Renderer) Markdown(view RecipeView) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.markdown.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// HTML gera a receita como uma página HTML pronta para impressão.
+func (r *Renderer) HTML(view RecipeView) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.html.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func loadTemplate(overrideDir string, name string) (string, error) {
+	if overrideDir != "" {
+		data, err := os.ReadFile(filepath.Join(overrideDir, name))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("render: reading %s: %w", name, err)
+		}
+	}
+
+	data, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"fmt"
+
+	"main.go/models"
+)
+
+// RecipeView reúne os dados da receita no formato usado pelos templates de exportação.
+type RecipeView struct {
+	ID          uint
+	Name        string
+	Author      string
+	Servings    int
+	PrepTime    int
+	CookTime    int
+	TotalTime   int
+	Images      []string
+	Ingredients []IngredientView
+	Steps       []StepView
+}
+
+// IngredientView representa uma linha da lista de ingredientes.
+type IngredientView struct {
+	Quantity string
+	Name     string
+	Note     string
+	Optional bool
+	ToTaste  bool
+	// Heading é o título do grupo (ex.: "Cobertura"), preenchido apenas no primeiro ingrediente do grupo.
+	Heading string
+}
+
+// Text formata a linha do ingrediente, como "200g farinha de trigo, peneirada (opcional)".
+func (i IngredientView) Text() string {
+	text := i.Name
+	if i.Quantity != "" {
+		text = i.Quantity + " " + text
+	}
+	if i.Note != "" {
+		text += ", " + i.Note
+	}
+	if i.ToTaste {
+		text += " a gosto"
+	}
+	if i.Optional {
+		text += " (opcional)"
+	}
+	return text
+}
+
+// StepView representa um passo do modo de preparo e suas imagens.
+type StepView struct {
+	Number int
+	Text   string
+	Images []string
+}
+
+// NewRecipeView monta a visão da receita. imageURL converte a chave de cada imagem na URL
+// usada pelo documento gerado (ex.: URL assinada e absoluta); pode ser nil para omitir as imagens.
+func NewRecipeView(recipe models.Recipe, author string, imageURL func(key string) string) RecipeView {
+	view := RecipeView{
+		ID:        recipe.ID,
+		Name:      recipe.Name,
+		Author:    author,
+		Servings:  recipe.Servings,
+		PrepTime:  recipe.PrepTime,
+		CookTime:  recipe.CookTime,
+		TotalTime: recipe.TotalTime,
+	}
+
+	group := ""
+	for _, ir := range recipe.IngredientsRecipes {
+		ingredient := IngredientView{
+			Quantity: ir.Quantity,
+			Name:     ir.Ingredient.Name,
+			Note:     ir.Note,
+			Optional: ir.Optional,
+			ToTaste:  ir.ToTaste,
+		}
+		if ir.Group != group {
+			ingredient.Heading = ir.Group
+			group = ir.Group
+		}
+		view.Ingredients = append(view.Ingredients, ingredient)
+	}
+
+	for i, text := range recipe.Steps() {
+		view.Steps = append(view.Steps, StepView{Number: i + 1, Text: text})
+	}
+
+	if imageURL != nil {
+		for _, image := range recipe.Images {
+			url := imageURL(image.Key)
+			if image.Step != nil && *image.Step >= 1 && *image.Step <= len(view.Steps) {
+				step := &view.Steps[*image.Step-1]
+				step.Images = append(step.Images, url)
+			} else {
+				view.Images = append(view.Images, url)
+			}
+		}
+	}
+
+	return view
+}
+
+// Minutes formata uma duração em minutos como "1h 30min".
+func Minutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dmin", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dmin", minutes/60, minutes%60)
+}
+
+// ISODuration formata uma duração em minutos no formato ISO 8601 ("PT1H30M"), usado pelo schema.org.
+// Durações zeradas ou negativas viram "PT0M".
+func ISODuration(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("PT%dM", max(minutes, 0))
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("PT%dH", minutes/60)
+	}
+	return fmt.Sprintf("PT%dH%dM", minutes/60, minutes%60)
+}
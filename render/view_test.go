@@ -0,0 +1,33 @@
+package render
+
+import "testing"
+
+func TestISODuration(t *testing.T) {
+	tests := map[int]string{
+		-5:  "PT0M",
+		0:   "PT0M",
+		45:  "PT45M",
+		60:  "PT1H",
+		90:  "PT1H30M",
+		120: "PT2H",
+	}
+	for minutes, want := range tests {
+		if got := ISODuration(minutes); got != want {
+			t.Errorf("ISODuration(%d) = %q, want %q", minutes, got, want)
+		}
+	}
+}
+
+func TestMinutes(t *testing.T) {
+	tests := map[int]string{
+		0:  "0min",
+		45: "45min",
+		60: "1h",
+		90: "1h 30min",
+	}
+	for minutes, want := range tests {
+		if got := Minutes(minutes); got != want {
+			t.Errorf("Minutes(%d) = %q, want %q", minutes, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"io"
+
+	"gorm.io/gorm"
+	"main.go/catalog"
+)
+
+// GormCatalogRepository importa e exporta o catálogo no banco, pelo GORM.
+type GormCatalogRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCatalogRepository cria o repositório do catálogo sobre a conexão informada.
+func NewGormCatalogRepository(db *gorm.DB) *GormCatalogRepository {
+	return &GormCatalogRepository{db: db}
+}
+
+func (r *GormCatalogRepository) Import(ctx context.Context, kind string, reader io.Reader, opts catalog.Options) (*catalog.Report, error) {
+	return catalog.Import(r.db.WithContext(ctx), kind, reader, opts)
+}
+
+func (r *GormCatalogRepository) Export(ctx context.Context, kind string, w io.Writer, format string) error {
+	return catalog.Export(r.db.WithContext(ctx), kind, w, format)
+}
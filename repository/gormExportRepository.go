@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// GormExportRepository guarda os pedidos de exportação de livros de receitas no banco, pelo GORM.
+type GormExportRepository struct {
+	db *gorm.DB
+}
+
+// NewGormExportRepository cria o repositório de exportações sobre a conexão informada.
+func NewGormExportRepository(db *gorm.DB) *GormExportRepository {
+	return &GormExportRepository{db: db}
+}
+
+func (r *GormExportRepository) Create(ctx context.Context, export *models.CookbookExport) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *GormExportRepository) Get(ctx context.Context, id uint, userID uint) (*models.CookbookExport, error) {
+	var export models.CookbookExport
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&export).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &export, nil
+}
+
+func (r *GormExportRepository) Fail(ctx context.Context, export *models.CookbookExport, message string) error {
+	now := time.Now()
+	export.Status, export.Error, export.FinishedAt = models.ExportFailed, message, &now
+	return r.db.WithContext(ctx).Model(export).Updates(map[string]any{"status": export.Status, "error": export.Error, "finished_at": export.FinishedAt}).Error
+}
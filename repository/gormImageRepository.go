@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// GormImageRepository guarda os registros das imagens das receitas no banco, pelo GORM.
+type GormImageRepository struct {
+	db *gorm.DB
+}
+
+// NewGormImageRepository cria o repositório de imagens sobre a conexão informada.
+func NewGormImageRepository(db *gorm.DB) *GormImageRepository {
+	return &GormImageRepository{db: db}
+}
+
+func (r *GormImageRepository) Get(ctx context.Context, recipeID uint, id uint) (*models.RecipeImage, error) {
+	var image models.RecipeImage
+	if err := r.db.WithContext(ctx).Preload("Thumbnails").Where("id = ? AND recipe_id = ?", id, recipeID).First(&image).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &image, nil
+}
+
+func (r *GormImageRepository) Create(ctx context.Context, image *models.RecipeImage) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+func (r *GormImageRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.RecipeImage{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
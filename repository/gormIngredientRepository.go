@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/catalog"
+	"main.go/models"
+)
+
+// GormIngredientRepository guarda os ingredientes no banco, pelo GORM.
+type GormIngredientRepository struct {
+	db *gorm.DB
+}
+
+// NewGormIngredientRepository cria o repositório de ingredientes sobre a conexão informada.
+func NewGormIngredientRepository(db *gorm.DB) *GormIngredientRepository {
+	return &GormIngredientRepository{db: db}
+}
+
+func (r *GormIngredientRepository) List(ctx context.Context) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.WithContext(ctx).Scopes(preloadNames).Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *GormIngredientRepository) Get(ctx context.Context, id uint) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	if err := r.db.WithContext(ctx).Scopes(preloadNames).Where("id = ?", id).First(&ingredient).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &ingredient, nil
+}
+
+func (r *GormIngredientRepository) Search(ctx context.Context, text string) ([]models.Ingredient, error) {
+	db := r.db.WithContext(ctx)
+	pattern := "%" + strings.ToLower(text) + "%"
+
+	aliases := db.Model(&models.IngredientAlias{}).Select("ingredient_id").Where("LOWER(name) LIKE ?", pattern)
+	translations := db.Model(&models.IngredientTranslation{}).Select("ingredient_id").Where("LOWER(name) LIKE ?", pattern)
+
+	var ingredients []models.Ingredient
+	err := db.Scopes(preloadNames).
+		Where("LOWER(name) LIKE ? OR id IN (?) OR id IN (?)", pattern, aliases, translations).
+		Order("id").Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *GormIngredientRepository) Create(ctx context.Context, ingredient *models.Ingredient) error {
+	if err := r.db.WithContext(ctx).Create(ingredient).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	return nil
+}
+
+func (r *GormIngredientRepository) Find(ctx context.Context, name string) (*models.Ingredient, error) {
+	ingredient, err := catalog.FindIngredient(r.db.WithContext(ctx), name)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return ingredient, nil
+}
+
+func (r *GormIngredientRepository) Rename(ctx context.Context, id uint, name string) error {
+	db := r.db.WithContext(ctx)
+
+	var ingredient models.Ingredient
+	if err := db.Where("id = ?", id).First(&ingredient).Error; err != nil {
+		return notFound(err)
+	}
+
+	if err := db.Model(&ingredient).Update("name", name).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	return nil
+}
+
+func (r *GormIngredientRepository) AddAlias(ctx context.Context, alias *models.IngredientAlias) error {
+	db := r.db.WithContext(ctx)
+
+	var count int64
+	if err := db.Model(&models.IngredientAlias{}).Where("LOWER(name) = ?", strings.ToLower(alias.Name)).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrConflict
+	}
+
+	// Um nome alternativo não pode levar a outro ingrediente
+	found, err := catalog.FindIngredient(db, alias.Name)
+	if err == nil && (found.ID != alias.IngredientID || strings.EqualFold(found.Name, alias.Name)) {
+		return ErrConflict
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err := db.Create(alias).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	return nil
+}
+
+func (r *GormIngredientRepository) RemoveAlias(ctx context.Context, ingredientID uint, name string) error {
+	result := r.db.WithContext(ctx).Where("ingredient_id = ? AND LOWER(name) = ?", ingredientID, strings.ToLower(name)).Delete(&models.IngredientAlias{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormIngredientRepository) SetTranslation(ctx context.Context, translation *models.IngredientTranslation) error {
+	db := r.db.WithContext(ctx)
+
+	// A tradução não pode ser o nome de outro ingrediente
+	found, err := catalog.FindIngredient(db, translation.Name)
+	if err == nil && found.ID != translation.IngredientID {
+		return ErrConflict
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return db.Where(models.IngredientTranslation{IngredientID: translation.IngredientID, Locale: translation.Locale}).
+		Assign(models.IngredientTranslation{Name: translation.Name}).FirstOrCreate(translation).Error
+}
+
+func (r *GormIngredientRepository) RemoveTranslation(ctx context.Context, ingredientID uint, locale string) error {
+	result := r.db.WithContext(ctx).Where("ingredient_id = ? AND locale = ?", ingredientID, locale).Delete(&models.IngredientTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormIngredientRepository) Usage(ctx context.Context, id uint, viewerID uint) (*models.IngredientUsage, error) {
+	usage := &models.IngredientUsage{IngredientID: id, Recipes: []models.IngredientUsageRecipe{}}
+
+	query := func() *gorm.DB {
+		return r.db.WithContext(ctx).Model(&models.Recipe{}).
+			Joins("JOIN ingredients_recipes ON ingredients_recipes.recipe_id = recipes.id").
+			Where("ingredients_recipes.ingredient_id = ?", id)
+	}
+
+	if err := query().Count(&usage.Count).Error; err != nil {
+		return nil, err
+	}
+	err := query().Scopes(catalog.Visible(viewerID)).Select("recipes.id", "recipes.name").Order("recipes.id").Find(&usage.Recipes).Error
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (r *GormIngredientRepository) Delete(ctx context.Context, id uint, deletedBy uint) error {
+	return moveToTrash(r.db.WithContext(ctx), &models.Ingredient{}, id, deletedBy)
+}
+
+func (r *GormIngredientRepository) Trash(ctx context.Context) ([]models.Ingredient, error) {
+	var ingredients []models.Ingredient
+	err := r.db.WithContext(ctx).Unscoped().Select("id", "name", "deleted_at").
+		Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *GormIngredientRepository) Restore(ctx context.Context, id uint) error {
+	return restoreFromTrash(r.db.WithContext(ctx), &models.Ingredient{}, id)
+}
+
+func (r *GormIngredientRepository) Merge(ctx context.Context, canonicalID uint, duplicateIDs []uint, authorID uint) (*models.IngredientMergeReport, error) {
+	var report *models.IngredientMergeReport
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = catalog.MergeIngredients(tx, canonicalID, duplicateIDs, authorID)
+		return err
+	})
+
+	if errors.Is(err, catalog.ErrIngredientNotFound) {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return report, err
+}
+
+func (r *GormIngredientRepository) Localize(ctx context.Context, locale string, ingredients ...*models.Ingredient) error {
+	return catalog.Localize(r.db.WithContext(ctx), locale, ingredients...)
+}
+
+// Funções privadas
+
+// preloadNames carrega os nomes alternativos e as traduções dos ingredientes
+func preloadNames(db *gorm.DB) *gorm.DB {
+	return db.Preload("Aliases").Preload("Translations")
+}
+
+// moveToTrash marca o registro como removido pelo usuário, sem apagá-lo. Retorna ErrNotFound se o
+// registro não existir fora da lixeira.
+func moveToTrash(db *gorm.DB, model any, id uint, deletedBy uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(model).Where("id = ?", id).Update("deleted_by", deletedBy).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// restoreFromTrash tira o registro da lixeira. Retorna ErrNotFound se ele não estiver lá.
+func restoreFromTrash(db *gorm.DB, model any, id uint) error {
+	result := db.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{"deleted_at": nil, "deleted_by": 0})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
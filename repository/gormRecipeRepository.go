@@ -0,0 +1,489 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"main.go/catalog"
+	"main.go/history"
+	"main.go/models"
+)
+
+// GormRecipeRepository guarda as receitas no banco, pelo GORM, registrando as revisões pelo pacote
+// history na mesma transação das alterações. As alterações começam bloqueando a receita (history.Lock),
+// para que alterações simultâneas não disputem o número da revisão.
+type GormRecipeRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRecipeRepository cria o repositório de receitas sobre a conexão informada.
+func NewGormRecipeRepository(db *gorm.DB) *GormRecipeRepository {
+	return &GormRecipeRepository{db: db}
+}
+
+func (r *GormRecipeRepository) List(ctx context.Context, viewerID uint) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	err := r.db.WithContext(ctx).Scopes(catalog.Listed(viewerID), preloadRecipe).Find(&recipes).Error
+	return recipes, err
+}
+
+func (r *GormRecipeRepository) ListByUser(ctx context.Context, userID uint, viewerID uint) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+	err := r.db.WithContext(ctx).Scopes(catalog.Listed(viewerID)).Where("user_id = ?", userID).Find(&recipes).Error
+	return recipes, err
+}
+
+func (r *GormRecipeRepository) Get(ctx context.Context, id uint, viewerID uint) (*models.Recipe, error) {
+	var recipe models.Recipe
+	err := r.db.WithContext(ctx).Scopes(catalog.Visible(viewerID), preloadRecipe).Where("id = ?", id).First(&recipe).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &recipe, nil
+}
+
+func (r *GormRecipeRepository) GetByName(ctx context.Context, name string, viewerID uint) (*models.Recipe, error) {
+	slug := catalog.Slugify(name)
+	name = strings.ToLower(strings.ReplaceAll(name, "-", " "))
+
+	var recipe models.Recipe
+	err := r.db.WithContext(ctx).Scopes(catalog.Visible(viewerID), preloadRecipe).
+		Where("slug = ? OR LOWER(name) = ?", slug, name).Order("id").First(&recipe).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &recipe, nil
+}
+
+func (r *GormRecipeRepository) GetBySlug(ctx context.Context, authorID uint, slug string, viewerID uint) (*models.Recipe, error) {
+	var recipe models.Recipe
+	err := r.db.WithContext(ctx).Scopes(catalog.Visible(viewerID), preloadRecipe).
+		Where("recipes.user_id = ? AND slug = ?", authorID, slug).First(&recipe).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &recipe, nil
+}
+
+func (r *GormRecipeRepository) GetBySlugRedirect(ctx context.Context, authorID uint, slug string, viewerID uint) (*models.Recipe, error) {
+	db := r.db.WithContext(ctx)
+
+	var redirect models.RecipeSlugRedirect
+	if err := db.Where("user_id = ? AND slug = ?", authorID, slug).First(&redirect).Error; err != nil {
+		return nil, notFound(err)
+	}
+
+	var recipe models.Recipe
+	if err := db.Scopes(catalog.Visible(viewerID)).Where("id = ?", redirect.RecipeID).First(&recipe).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &recipe, nil
+}
+
+func (r *GormRecipeRepository) Find(ctx context.Context, id uint) (*models.Recipe, error) {
+	var recipe models.Recipe
+	if err := r.db.WithContext(ctx).Preload("IngredientsRecipes").Where("id = ?", id).First(&recipe).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &recipe, nil
+}
+
+func (r *GormRecipeRepository) Visible(ctx context.Context, id uint, viewerID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Recipe{}).Scopes(catalog.Visible(viewerID)).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormRecipeRepository) ForkCounts(ctx context.Context, ids []uint) (map[uint]int64, error) {
+	forks := map[uint]int64{}
+	if len(ids) == 0 {
+		return forks, nil
+	}
+
+	var counts []struct {
+		ParentID uint
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Recipe{}).Scopes(catalog.Listed(0)).Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ?", ids).Group("parent_id").Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, count := range counts {
+		forks[count.ParentID] = count.Count
+	}
+	return forks, nil
+}
+
+func (r *GormRecipeRepository) Save(ctx context.Context, recipe *models.Recipe, opts SaveRecipeOptions) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if recipe.ID != 0 {
+			if err := history.Lock(tx, recipe.ID); err != nil {
+				return notFound(err)
+			}
+		}
+
+		// Uma lista ausente mantém os ingredientes atuais na atualização
+		var ingredients []models.IngredientsRecipes
+		if opts.Ingredients != nil {
+			resolved, errs, err := catalog.ResolveIngredients(tx, opts.Ingredients, opts.CreateMissingIngredients)
+			if err != nil {
+				return err
+			}
+			if len(errs) > 0 {
+				return &InvalidIngredientsError{Errors: errs}
+			}
+			ingredients = resolved
+		}
+
+		if err := catalog.AssignSlug(tx, recipe); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(recipe).Error; err != nil {
+			return fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+
+		if opts.Ingredients != nil {
+			if err := catalog.ReplaceIngredients(tx, recipe.ID, ingredients); err != nil {
+				return err
+			}
+		}
+
+		_, err := history.Record(tx, recipe.ID, opts.AuthorID, opts.Summary)
+		return err
+	})
+}
+
+func (r *GormRecipeRepository) Delete(ctx context.Context, id uint, deletedBy uint) error {
+	// Os ingredientes, imagens e o histórico são mantidos até a receita ser removida definitivamente
+	return moveToTrash(r.db.WithContext(ctx), &models.Recipe{}, id, deletedBy)
+}
+
+func (r *GormRecipeRepository) AddIngredient(ctx context.Context, line *models.IngredientsRecipes, authorID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := history.Lock(tx, line.RecipeID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %v", ErrConflict, err)
+			}
+			return err
+		}
+
+		// O ingrediente adicionado vai para o fim da lista
+		err := tx.Model(&models.IngredientsRecipes{}).Where("recipe_id = ?", line.RecipeID).
+			Select("COALESCE(MAX(position) + 1, 0)").Scan(&line.Position).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Ingredient").Create(line).Error; err != nil {
+			return fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+		_, err = history.Record(tx, line.RecipeID, authorID, "")
+		return err
+	})
+}
+
+func (r *GormRecipeRepository) UpdateIngredient(ctx context.Context, line *models.IngredientsRecipes, authorID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := history.Lock(tx, line.RecipeID); err != nil {
+			return notFound(err)
+		}
+
+		// Select inclui os campos vazios e falsos, que o Updates ignoraria
+		result := tx.Model(&models.IngredientsRecipes{}).
+			Where("recipe_id = ? AND ingredient_id = ?", line.RecipeID, line.IngredientID).
+			Select("Quantity", "Group", "Note", "Optional", "ToTaste").
+			Updates(models.IngredientsRecipes{
+				Quantity: line.Quantity,
+				Group:    line.Group,
+				Note:     line.Note,
+				Optional: line.Optional,
+				ToTaste:  line.ToTaste,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		_, err := history.Record(tx, line.RecipeID, authorID, "")
+		return err
+	})
+}
+
+func (r *GormRecipeRepository) RemoveIngredient(ctx context.Context, recipeID uint, ingredientID uint, authorID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := history.Lock(tx, recipeID); err != nil {
+			return notFound(err)
+		}
+
+		result := tx.Where("recipe_id = ? AND ingredient_id = ?", recipeID, ingredientID).Delete(&models.IngredientsRecipes{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		_, err := history.Record(tx, recipeID, authorID, "")
+		return err
+	})
+}
+
+func (r *GormRecipeRepository) ReorderIngredients(ctx context.Context, recipeID uint, ingredientIDs []uint, authorID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := history.Lock(tx, recipeID); err != nil {
+			return notFound(err)
+		}
+
+		for position, ingredientID := range ingredientIDs {
+			err := tx.Model(&models.IngredientsRecipes{}).
+				Where("recipe_id = ? AND ingredient_id = ?", recipeID, ingredientID).
+				Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := history.Record(tx, recipeID, authorID, "")
+		return err
+	})
+}
+
+func (r *GormRecipeRepository) Fork(ctx context.Context, parentID uint, opts ForkRecipeOptions) (*models.Recipe, error) {
+	var fork models.Recipe
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Só podem ser copiadas as receitas visíveis para o usuário
+		var parent models.Recipe
+		if err := tx.Scopes(catalog.Visible(opts.UserID), catalog.PreloadIngredients).Where("id = ?", parentID).First(&parent).Error; err != nil {
+			return notFound(err)
+		}
+
+		name := opts.Name
+		if name == "" {
+			available, err := forkName(tx, opts.UserID, parent.Name+opts.NameSuffix)
+			if err != nil {
+				return err
+			}
+			name = available
+		}
+
+		fork = models.Recipe{
+			UserID:       opts.UserID,
+			Name:         name,
+			Instructions: parent.Instructions,
+			Servings:     parent.Servings,
+			PrepTime:     parent.PrepTime,
+			CookTime:     parent.CookTime,
+			TotalTime:    parent.TotalTime,
+			Visibility:   models.VisibilityDraft,
+			ParentID:     &parent.ID,
+		}
+		if err := catalog.AssignSlug(tx, &fork); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Create(&fork).Error; err != nil {
+			return fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+
+		ingredients := make([]models.IngredientsRecipes, len(parent.IngredientsRecipes))
+		for i, item := range parent.IngredientsRecipes {
+			ingredients[i] = models.IngredientsRecipes{
+				IngredientID: item.IngredientID,
+				Quantity:     item.Quantity,
+				Position:     item.Position,
+				Group:        item.Group,
+				Note:         item.Note,
+				Optional:     item.Optional,
+				ToTaste:      item.ToTaste,
+			}
+		}
+		summary := fmt.Sprintf("forked from recipe %d", parent.ID)
+		if opts.Substitute != "" {
+			substituted, err := catalog.ApplySubstitutions(tx, parent.IngredientsRecipes, opts.Substitute)
+			if err != nil {
+				return err
+			}
+			ingredients = substituted
+			summary += fmt.Sprintf(" with %s substitutions", opts.Substitute)
+		}
+
+		if err := catalog.ReplaceIngredients(tx, fork.ID, ingredients); err != nil {
+			return err
+		}
+
+		_, err := history.Record(tx, fork.ID, opts.UserID, summary)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Scopes(catalog.PreloadIngredients).Where("id = ?", fork.ID).First(&fork).Error; err != nil {
+		return nil, err
+	}
+	return &fork, nil
+}
+
+func (r *GormRecipeRepository) Forks(ctx context.Context, parentIDs []uint, viewerID uint) ([]models.Recipe, error) {
+	var forks []models.Recipe
+	err := r.db.WithContext(ctx).Scopes(catalog.Listed(viewerID)).Select("id", "name", "user_id", "parent_id").
+		Where("parent_id IN ?", parentIDs).Order("id").Find(&forks).Error
+	return forks, err
+}
+
+func (r *GormRecipeRepository) Trash(ctx context.Context, userID uint) ([]models.Recipe, error) {
+	query := r.db.WithContext(ctx).Unscoped().Select("id", "name", "user_id", "deleted_at").Where("deleted_at IS NOT NULL")
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var recipes []models.Recipe
+	err := query.Order("deleted_at DESC").Find(&recipes).Error
+	return recipes, err
+}
+
+func (r *GormRecipeRepository) FindDeleted(ctx context.Context, id uint) (*models.Recipe, error) {
+	var recipe models.Recipe
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&recipe).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &recipe, nil
+}
+
+func (r *GormRecipeRepository) Restore(ctx context.Context, id uint) error {
+	return restoreFromTrash(r.db.WithContext(ctx), &models.Recipe{}, id)
+}
+
+func (r *GormRecipeRepository) Revisions(ctx context.Context, recipeID uint) ([]models.RecipeRevision, error) {
+	var revisions []models.RecipeRevision
+	err := r.db.WithContext(ctx).Omit("snapshot").Where("recipe_id = ?", recipeID).Order("number DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *GormRecipeRepository) Revision(ctx context.Context, recipeID uint, number int) (*models.RecipeRevision, error) {
+	var revision models.RecipeRevision
+	if err := r.db.WithContext(ctx).Where("recipe_id = ? AND number = ?", recipeID, number).First(&revision).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &revision, nil
+}
+
+func (r *GormRecipeRepository) RestoreRevision(ctx context.Context, revision *models.RecipeRevision, authorID uint) (*models.RecipeRevision, error) {
+	snapshot, err := history.Decode(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *models.RecipeRevision
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := history.Lock(tx, revision.RecipeID); err != nil {
+			return notFound(err)
+		}
+
+		var recipe models.Recipe
+		if err := tx.Where("id = ?", revision.RecipeID).First(&recipe).Error; err != nil {
+			return notFound(err)
+		}
+
+		restoreSnapshot(&recipe, snapshot)
+
+		if err := catalog.AssignSlug(tx, &recipe); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(&recipe).Error; err != nil {
+			return fmt.Errorf("%w: %v", ErrConflict, err)
+		}
+
+		ingredients, err := restoreIngredients(tx, snapshot.Ingredients)
+		if err != nil {
+			return err
+		}
+		if err := catalog.ReplaceIngredients(tx, recipe.ID, ingredients); err != nil {
+			return err
+		}
+
+		restored, err = history.Record(tx, recipe.ID, authorID, fmt.Sprintf("restored revision %d", revision.Number))
+		return err
+	})
+	return restored, err
+}
+
+// Funções privadas
+
+// preloadRecipe carrega os ingredientes, na ordem definida pelo autor, e as imagens da receita
+func preloadRecipe(db *gorm.DB) *gorm.DB {
+	return db.Scopes(catalog.PreloadIngredients).Preload("Images.Thumbnails")
+}
+
+// forkName retorna o nome disponível para a cópia entre as receitas do usuário: o próprio nome ou o
+// nome com o primeiro sufixo numérico livre (ex.: "Bolo (2)")
+func forkName(tx *gorm.DB, userID uint, name string) (string, error) {
+	candidate := name
+	for n := 2; ; n++ {
+		var count int64
+		if err := tx.Unscoped().Model(&models.Recipe{}).Where("user_id = ? AND name = ?", userID, candidate).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (%d)", name, n)
+	}
+}
+
+// restoreSnapshot copia para a receita os campos guardados na revisão. O autor atual é mantido: a
+// restauração não devolve a receita a quem a transferiu.
+func restoreSnapshot(recipe *models.Recipe, snapshot *models.RecipeSnapshot) {
+	recipe.Name = snapshot.Name
+	recipe.Instructions = snapshot.Instructions
+	recipe.Servings = snapshot.Servings
+	recipe.PrepTime = snapshot.PrepTime
+	recipe.CookTime = snapshot.CookTime
+	recipe.TotalTime = snapshot.TotalTime
+	// Revisões antigas não guardam a visibilidade, que então é mantida
+	if snapshot.Visibility != "" {
+		recipe.Visibility = snapshot.Visibility
+		recipe.PublishAt = snapshot.PublishAt
+	}
+}
+
+// restoreIngredients converte os ingredientes guardados na revisão nas associações da receita,
+// cadastrando novamente pelo nome os ingredientes que não existem mais
+func restoreIngredients(tx *gorm.DB, items []models.RecipeSnapshotIngredient) ([]models.IngredientsRecipes, error) {
+	var ingredients []models.IngredientsRecipes
+
+	for i, item := range items {
+		ingredientID := item.IngredientID
+
+		var count int64
+		if err := tx.Model(&models.Ingredient{}).Where("id = ?", ingredientID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			ingredient, _, err := catalog.FindOrCreateIngredient(tx, item.Name)
+			if err != nil {
+				return nil, err
+			}
+			ingredientID = ingredient.ID
+		}
+
+		ingredients = append(ingredients, models.IngredientsRecipes{
+			IngredientID: ingredientID,
+			Quantity:     item.Quantity,
+			Position:     i,
+			Group:        item.Group,
+			Note:         item.Note,
+			Optional:     item.Optional,
+			ToTaste:      item.ToTaste,
+		})
+	}
+
+	return ingredients, nil
+}
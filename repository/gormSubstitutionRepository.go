@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"main.go/catalog"
+	"main.go/models"
+)
+
+// GormSubstitutionRepository guarda as substituições de ingredientes no banco, pelo GORM.
+type GormSubstitutionRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSubstitutionRepository cria o repositório de substituições sobre a conexão informada.
+func NewGormSubstitutionRepository(db *gorm.DB) *GormSubstitutionRepository {
+	return &GormSubstitutionRepository{db: db}
+}
+
+func (r *GormSubstitutionRepository) List(ctx context.Context, ingredientID uint, reason string) ([]models.Substitution, error) {
+	query := r.db.WithContext(ctx).Scopes(preloadSubstitution)
+	if ingredientID != 0 {
+		query = query.Where("ingredient_id = ?", ingredientID)
+	}
+	if reason != "" {
+		query = query.Where("reason = ?", reason)
+	}
+
+	substitutions := []models.Substitution{}
+	err := query.Order("id").Find(&substitutions).Error
+	return substitutions, err
+}
+
+func (r *GormSubstitutionRepository) Get(ctx context.Context, id uint) (*models.Substitution, error) {
+	var substitution models.Substitution
+	if err := r.db.WithContext(ctx).Scopes(preloadSubstitution).Where("id = ?", id).First(&substitution).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &substitution, nil
+}
+
+func (r *GormSubstitutionRepository) Save(ctx context.Context, substitution *models.Substitution) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if substitution.ID != 0 {
+			if err := tx.Where("substitution_id = ?", substitution.ID).Delete(&models.SubstitutionItem{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Ingredient", "Replacements.Ingredient").Save(substitution).Error
+	})
+}
+
+func (r *GormSubstitutionRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Substitution{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *GormSubstitutionRepository) Suggest(ctx context.Context, recipe *models.Recipe, reason string, locale string) (*models.RecipeSubstitutions, error) {
+	return catalog.SuggestSubstitutions(r.db.WithContext(ctx), recipe, reason, locale)
+}
+
+// Funções privadas
+
+// preloadSubstitution carrega o ingrediente substituído e os ingredientes usados na substituição
+func preloadSubstitution(db *gorm.DB) *gorm.DB {
+	return db.Preload("Ingredient").Preload("Replacements", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Preload("Replacements.Ingredient")
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// GormUserRepository guarda os usuários no banco, pelo GORM.
+type GormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository cria o repositório de usuários sobre a conexão informada.
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
+}
+
+func (r *GormUserRepository) List(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Find(&users).Error
+	return users, err
+}
+
+func (r *GormUserRepository) Get(ctx context.Context, id uint) (*models.User, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.first(ctx, "email = ?", email)
+}
+
+func (r *GormUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.first(ctx, "username = ?", username)
+}
+
+func (r *GormUserRepository) Create(ctx context.Context, user *models.User) error {
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	return nil
+}
+
+func (r *GormUserRepository) Update(ctx context.Context, user *models.User) error {
+	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	}
+	return nil
+}
+
+func (r *GormUserRepository) Delete(ctx context.Context, id uint) error {
+	// As receitas vão para a lixeira com a mesma data do usuário, para voltarem junto com ele
+	deletedAt := time.Now().UTC()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", id).Update("deleted_at", deletedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Model(&models.Recipe{}).Where("user_id = ?", id).Update("deleted_at", deletedAt).Error
+	})
+}
+
+func (r *GormUserRepository) Restore(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&user).Error; err != nil {
+			return notFound(err)
+		}
+
+		if err := tx.Unscoped().Model(&user).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		// As receitas removidas antes do usuário continuam na lixeira
+		return tx.Unscoped().Model(&models.Recipe{}).Where("user_id = ? AND deleted_at >= ?", id, user.DeletedAt.Time).
+			Updates(map[string]any{"deleted_at": nil, "deleted_by": 0}).Error
+	})
+}
+
+func (r *GormUserRepository) Usernames(ctx context.Context, ids []uint) (map[uint]string, error) {
+	names := map[uint]string{}
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	var users []models.User
+	if err := r.db.WithContext(ctx).Select("id", "username").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.ID] = user.Username
+	}
+	return names, nil
+}
+
+// Funções privadas
+
+func (r *GormUserRepository) first(ctx context.Context, query string, args ...any) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+// notFound converte o erro de registro não encontrado do GORM em ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
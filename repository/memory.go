@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/history"
+	"main.go/models"
+)
+
+// Memory guarda usuários, receitas, ingredientes, substituições, imagens e exportações em memória,
+// compartilhados pelos repositórios criados a partir dele. Serve para testar os handlers sem banco:
+// segue as mesmas regras de visibilidade, lixeira, unicidade e histórico de revisões das
+// implementações sobre o GORM.
+type Memory struct {
+	mu            sync.Mutex
+	lastIDs       map[string]uint
+	users         map[uint]*models.User
+	ingredients   map[uint]*models.Ingredient
+	recipes       map[uint]*models.Recipe
+	redirects     []models.RecipeSlugRedirect
+	revisions     []models.RecipeRevision
+	substitutions map[uint]*models.Substitution
+	images        map[uint]*models.RecipeImage
+	exports       map[uint]*models.CookbookExport
+	now           func() time.Time
+}
+
+// NewMemory cria um armazenamento em memória vazio.
+func NewMemory() *Memory {
+	return &Memory{
+		lastIDs:       map[string]uint{},
+		users:         map[uint]*models.User{},
+		ingredients:   map[uint]*models.Ingredient{},
+		recipes:       map[uint]*models.Recipe{},
+		substitutions: map[uint]*models.Substitution{},
+		images:        map[uint]*models.RecipeImage{},
+		exports:       map[uint]*models.CookbookExport{},
+		now:           time.Now,
+	}
+}
+
+// Users retorna o repositório de usuários guardados em memória.
+func (m *Memory) Users() UserRepository {
+	return &memoryUserRepository{m}
+}
+
+// Recipes retorna o repositório de receitas guardadas em memória.
+func (m *Memory) Recipes() RecipeRepository {
+	return &memoryRecipeRepository{m}
+}
+
+// Ingredients retorna o repositório de ingredientes guardados em memória.
+func (m *Memory) Ingredients() IngredientRepository {
+	return &memoryIngredientRepository{m}
+}
+
+// Substitutions retorna o repositório de substituições guardadas em memória.
+func (m *Memory) Substitutions() SubstitutionRepository {
+	return &memorySubstitutionRepository{m}
+}
+
+// Images retorna o repositório de imagens guardadas em memória.
+func (m *Memory) Images() ImageRepository {
+	return &memoryImageRepository{m}
+}
+
+// Exports retorna o repositório de exportações guardadas em memória.
+func (m *Memory) Exports() ExportRepository {
+	return &memoryExportRepository{m}
+}
+
+// Funções privadas
+
+// nextID gera os IDs dos registros da tabela, como as sequências do banco
+func (m *Memory) nextID(table string) uint {
+	m.lastIDs[table]++
+	return m.lastIDs[table]
+}
+
+func (m *Memory) deletedAt() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: m.now(), Valid: true}
+}
+
+// sortedIDs retorna as chaves do mapa em ordem crescente, a ordem padrão das consultas
+func sortedIDs[T any](records map[uint]*T) []uint {
+	ids := make([]uint, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// findIngredient busca o ingrediente como catalog.FindIngredient: pelo nome e, não encontrando, pelos
+// nomes alternativos e pelas traduções. Com trashed, ingredientes na lixeira também são considerados.
+func (m *Memory) findIngredient(name string, trashed bool) *models.Ingredient {
+	var byName *models.Ingredient
+	for _, id := range sortedIDs(m.ingredients) {
+		ingredient := m.ingredients[id]
+		if ingredient.DeletedAt.Valid && !trashed {
+			continue
+		}
+		if strings.EqualFold(ingredient.Name, name) {
+			return ingredient
+		}
+		if byName != nil {
+			continue
+		}
+		for _, alias := range ingredient.Aliases {
+			if strings.EqualFold(alias.Name, name) {
+				byName = ingredient
+			}
+		}
+		for _, translation := range ingredient.Translations {
+			if strings.EqualFold(translation.Name, name) {
+				byName = ingredient
+			}
+		}
+	}
+	return byName
+}
+
+// aliasOwner retorna o ingrediente que tem o nome alternativo, sem diferenciar maiúsculas e minúsculas
+func (m *Memory) aliasOwner(name string) (*models.Ingredient, int) {
+	for _, ingredient := range m.ingredients {
+		for i, alias := range ingredient.Aliases {
+			if strings.EqualFold(alias.Name, name) {
+				return ingredient, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// recipeView copia a receita guardada, com os ingredientes na ordem definida pelo autor, incluindo os
+// que estão na lixeira, como em catalog.PreloadIngredients, e as imagens
+func (m *Memory) recipeView(recipe *models.Recipe) models.Recipe {
+	view := *recipe
+	view.IngredientsRecipes = slices.Clone(recipe.IngredientsRecipes)
+	slices.SortFunc(view.IngredientsRecipes, func(a, b models.IngredientsRecipes) int {
+		if a.Position != b.Position {
+			return a.Position - b.Position
+		}
+		return int(a.IngredientID) - int(b.IngredientID)
+	})
+	for i := range view.IngredientsRecipes {
+		if ingredient, ok := m.ingredients[view.IngredientsRecipes[i].IngredientID]; ok {
+			view.IngredientsRecipes[i].Ingredient = models.Ingredient{
+				ID:        ingredient.ID,
+				Name:      ingredient.Name,
+				DeletedAt: ingredient.DeletedAt,
+				DeletedBy: ingredient.DeletedBy,
+			}
+		}
+	}
+	view.Images = nil
+	for _, id := range sortedIDs(m.images) {
+		if image := m.images[id]; image.RecipeID == recipe.ID {
+			view.Images = append(view.Images, imageView(image))
+		}
+	}
+	return view
+}
+
+// substitutionView copia a substituição guardada com o ingrediente substituído e os usados nela, como
+// no preload das substituições no GORM, que ignora os ingredientes na lixeira
+func (m *Memory) substitutionView(substitution *models.Substitution) models.Substitution {
+	view := *substitution
+	view.Ingredient = m.ingredientRef(substitution.IngredientID)
+	view.Replacements = slices.Clone(substitution.Replacements)
+	for i := range view.Replacements {
+		view.Replacements[i].Ingredient = m.ingredientRef(view.Replacements[i].IngredientID)
+	}
+	return view
+}
+
+// findSubstitutions busca as substituições dos ingredientes, como catalog.FindSubstitutions
+func (m *Memory) findSubstitutions(ingredientIDs []uint, reason string) map[uint][]models.Substitution {
+	found := make(map[uint][]models.Substitution)
+	for _, id := range sortedIDs(m.substitutions) {
+		substitution := m.substitutions[id]
+		if slices.Contains(ingredientIDs, substitution.IngredientID) && (reason == "" || substitution.Reason == reason) {
+			found[substitution.IngredientID] = append(found[substitution.IngredientID], m.substitutionView(substitution))
+		}
+	}
+	return found
+}
+
+// ingredientRef retorna o ingrediente fora da lixeira com o ID e o nome, ou um ingrediente vazio
+func (m *Memory) ingredientRef(id uint) models.Ingredient {
+	ingredient, ok := m.ingredients[id]
+	if !ok || ingredient.DeletedAt.Valid {
+		return models.Ingredient{}
+	}
+	return models.Ingredient{ID: ingredient.ID, Name: ingredient.Name}
+}
+
+// listed e visible seguem os escopos catalog.Listed e catalog.Visible
+func (m *Memory) listed(recipe *models.Recipe, viewerID uint) bool {
+	if recipe.DeletedAt.Valid {
+		return false
+	}
+	if recipe.UserID == viewerID {
+		return true
+	}
+	return recipe.Visibility == models.VisibilityPublished && recipe.VisibleTo(viewerID, m.now())
+}
+
+func (m *Memory) visible(recipe *models.Recipe, viewerID uint) bool {
+	return !recipe.DeletedAt.Valid && (recipe.UserID == viewerID || recipe.VisibleTo(viewerID, m.now()))
+}
+
+// record registra a revisão com o estado atual da receita, como history.Record: sem resumo, ele é
+// gerado pelas diferenças para a revisão anterior, e nada é registrado se a receita não mudou
+func (m *Memory) record(recipeID uint, authorID uint, summary string) (*models.RecipeRevision, error) {
+	snapshot := history.NewSnapshot(m.recipeView(m.recipes[recipeID]))
+
+	var last *models.RecipeRevision
+	for i := range m.revisions {
+		if m.revisions[i].RecipeID == recipeID {
+			last = &m.revisions[i]
+		}
+	}
+
+	number := 1
+	if last != nil {
+		previous, err := history.Decode(last)
+		if err != nil {
+			return nil, err
+		}
+		diff := history.Diff(previous, snapshot)
+		if len(diff.Fields) == 0 && len(diff.Instructions) == 0 && len(diff.Ingredients) == 0 && !diff.Reordered {
+			return nil, nil
+		}
+		if summary == "" {
+			summary = history.Summarize(diff)
+		}
+		number = last.Number + 1
+	} else if summary == "" {
+		summary = history.SummaryCreated
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := models.RecipeRevision{
+		ID:        m.nextID("recipe_revisions"),
+		RecipeID:  recipeID,
+		Number:    number,
+		AuthorID:  authorID,
+		Summary:   summary,
+		Snapshot:  string(data),
+		CreatedAt: m.now(),
+	}
+	m.revisions = append(m.revisions, revision)
+	return &revision, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"main.go/models"
+)
+
+// memoryExportRepository é o ExportRepository de Memory
+type memoryExportRepository struct {
+	m *Memory
+}
+
+func (r *memoryExportRepository) Create(ctx context.Context, export *models.CookbookExport) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	export.ID = r.m.nextID("cookbook_exports")
+	export.CreatedAt = r.m.now()
+
+	stored := *export
+	r.m.exports[export.ID] = &stored
+	return nil
+}
+
+func (r *memoryExportRepository) Get(ctx context.Context, id uint, userID uint) (*models.CookbookExport, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	export, ok := r.m.exports[id]
+	if !ok || export.UserID != userID {
+		return nil, ErrNotFound
+	}
+	found := *export
+	return &found, nil
+}
+
+func (r *memoryExportRepository) Fail(ctx context.Context, export *models.CookbookExport, message string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	now := r.m.now()
+	export.Status, export.Error, export.FinishedAt = models.ExportFailed, message, &now
+	if stored, ok := r.m.exports[export.ID]; ok {
+		stored.Status, stored.Error, stored.FinishedAt = export.Status, export.Error, export.FinishedAt
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"main.go/models"
+)
+
+// memoryImageRepository é o ImageRepository de Memory
+type memoryImageRepository struct {
+	m *Memory
+}
+
+func (r *memoryImageRepository) Get(ctx context.Context, recipeID uint, id uint) (*models.RecipeImage, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	image, ok := r.m.images[id]
+	if !ok || image.RecipeID != recipeID {
+		return nil, ErrNotFound
+	}
+	view := imageView(image)
+	return &view, nil
+}
+
+func (r *memoryImageRepository) Create(ctx context.Context, image *models.RecipeImage) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	image.ID = r.m.nextID("recipe_images")
+	image.CreatedAt = r.m.now()
+	for i := range image.Thumbnails {
+		image.Thumbnails[i].ID = r.m.nextID("recipe_image_thumbnails")
+		image.Thumbnails[i].ImageID = image.ID
+	}
+
+	stored := imageView(image)
+	r.m.images[image.ID] = &stored
+	return nil
+}
+
+func (r *memoryImageRepository) Delete(ctx context.Context, id uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if _, ok := r.m.images[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.m.images, id)
+	return nil
+}
+
+// Funções privadas
+
+// imageView copia a imagem com as miniaturas
+func imageView(image *models.RecipeImage) models.RecipeImage {
+	view := *image
+	view.Thumbnails = slices.Clone(image.Thumbnails)
+	return view
+}
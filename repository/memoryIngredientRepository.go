@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// memoryIngredientRepository é o IngredientRepository de Memory
+type memoryIngredientRepository struct {
+	m *Memory
+}
+
+func (r *memoryIngredientRepository) List(ctx context.Context) ([]models.Ingredient, error) {
+	return r.filter(func(ingredient *models.Ingredient) bool { return true }), nil
+}
+
+func (r *memoryIngredientRepository) Get(ctx context.Context, id uint) (*models.Ingredient, error) {
+	found := r.filter(func(ingredient *models.Ingredient) bool { return ingredient.ID == id })
+	if len(found) == 0 {
+		return nil, ErrNotFound
+	}
+	return &found[0], nil
+}
+
+func (r *memoryIngredientRepository) Search(ctx context.Context, text string) ([]models.Ingredient, error) {
+	text = strings.ToLower(text)
+	contains := func(name string) bool {
+		return strings.Contains(strings.ToLower(name), text)
+	}
+
+	return r.filter(func(ingredient *models.Ingredient) bool {
+		if contains(ingredient.Name) {
+			return true
+		}
+		for _, alias := range ingredient.Aliases {
+			if contains(alias.Name) {
+				return true
+			}
+		}
+		for _, translation := range ingredient.Translations {
+			if contains(translation.Name) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (r *memoryIngredientRepository) Create(ctx context.Context, ingredient *models.Ingredient) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if r.nameTaken(ingredient.Name, 0) {
+		return ErrConflict
+	}
+	for _, alias := range ingredient.Aliases {
+		if owner, _ := r.m.aliasOwner(alias.Name); owner != nil {
+			return ErrConflict
+		}
+	}
+
+	ingredient.ID = r.m.nextID("ingredients")
+	for i := range ingredient.Aliases {
+		ingredient.Aliases[i].ID = r.m.nextID("ingredient_aliases")
+		ingredient.Aliases[i].IngredientID = ingredient.ID
+	}
+	for i := range ingredient.Translations {
+		ingredient.Translations[i].ID = r.m.nextID("ingredient_translations")
+		ingredient.Translations[i].IngredientID = ingredient.ID
+	}
+
+	stored := cloneIngredient(*ingredient)
+	r.m.ingredients[ingredient.ID] = &stored
+	return nil
+}
+
+func (r *memoryIngredientRepository) Find(ctx context.Context, name string) (*models.Ingredient, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	found := r.m.findIngredient(name, false)
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	ingredient := cloneIngredient(*found)
+	return &ingredient, nil
+}
+
+func (r *memoryIngredientRepository) Rename(ctx context.Context, id uint, name string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredient, ok := r.m.ingredients[id]
+	if !ok || ingredient.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if r.nameTaken(name, id) {
+		return ErrConflict
+	}
+	ingredient.Name = name
+	return nil
+}
+
+func (r *memoryIngredientRepository) AddAlias(ctx context.Context, alias *models.IngredientAlias) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredient, ok := r.m.ingredients[alias.IngredientID]
+	if !ok {
+		return ErrNotFound
+	}
+	if owner, _ := r.m.aliasOwner(alias.Name); owner != nil {
+		return ErrConflict
+	}
+
+	// Um nome alternativo não pode levar a outro ingrediente
+	if found := r.m.findIngredient(alias.Name, false); found != nil && (found != ingredient || strings.EqualFold(found.Name, alias.Name)) {
+		return ErrConflict
+	}
+
+	alias.ID = r.m.nextID("ingredient_aliases")
+	ingredient.Aliases = append(ingredient.Aliases, *alias)
+	return nil
+}
+
+func (r *memoryIngredientRepository) RemoveAlias(ctx context.Context, ingredientID uint, name string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	owner, i := r.m.aliasOwner(name)
+	if owner == nil || owner.ID != ingredientID {
+		return ErrNotFound
+	}
+	owner.Aliases = slices.Delete(owner.Aliases, i, i+1)
+	return nil
+}
+
+func (r *memoryIngredientRepository) SetTranslation(ctx context.Context, translation *models.IngredientTranslation) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredient, ok := r.m.ingredients[translation.IngredientID]
+	if !ok {
+		return ErrNotFound
+	}
+
+	// A tradução não pode ser o nome de outro ingrediente
+	if found := r.m.findIngredient(translation.Name, false); found != nil && found != ingredient {
+		return ErrConflict
+	}
+
+	for i := range ingredient.Translations {
+		if ingredient.Translations[i].Locale == translation.Locale {
+			ingredient.Translations[i].Name = translation.Name
+			*translation = ingredient.Translations[i]
+			return nil
+		}
+	}
+	translation.ID = r.m.nextID("ingredient_translations")
+	ingredient.Translations = append(ingredient.Translations, *translation)
+	return nil
+}
+
+func (r *memoryIngredientRepository) RemoveTranslation(ctx context.Context, ingredientID uint, locale string) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredient, ok := r.m.ingredients[ingredientID]
+	if !ok {
+		return ErrNotFound
+	}
+	i := slices.IndexFunc(ingredient.Translations, func(t models.IngredientTranslation) bool { return t.Locale == locale })
+	if i < 0 {
+		return ErrNotFound
+	}
+	ingredient.Translations = slices.Delete(ingredient.Translations, i, i+1)
+	return nil
+}
+
+func (r *memoryIngredientRepository) Usage(ctx context.Context, id uint, viewerID uint) (*models.IngredientUsage, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	usage := &models.IngredientUsage{IngredientID: id, Recipes: []models.IngredientUsageRecipe{}}
+	for _, recipeID := range sortedIDs(r.m.recipes) {
+		recipe := r.m.recipes[recipeID]
+		if recipe.DeletedAt.Valid {
+			continue
+		}
+		if !slices.ContainsFunc(recipe.IngredientsRecipes, func(line models.IngredientsRecipes) bool { return line.IngredientID == id }) {
+			continue
+		}
+		usage.Count++
+		if r.m.visible(recipe, viewerID) {
+			usage.Recipes = append(usage.Recipes, models.IngredientUsageRecipe{ID: recipe.ID, Name: recipe.Name})
+		}
+	}
+	return usage, nil
+}
+
+func (r *memoryIngredientRepository) Delete(ctx context.Context, id uint, deletedBy uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredient, ok := r.m.ingredients[id]
+	if !ok || ingredient.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	ingredient.DeletedAt = r.m.deletedAt()
+	ingredient.DeletedBy = deletedBy
+	return nil
+}
+
+func (r *memoryIngredientRepository) Trash(ctx context.Context) ([]models.Ingredient, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredients := []models.Ingredient{}
+	for _, id := range sortedIDs(r.m.ingredients) {
+		if ingredient := r.m.ingredients[id]; ingredient.DeletedAt.Valid {
+			ingredients = append(ingredients, models.Ingredient{ID: ingredient.ID, Name: ingredient.Name, DeletedAt: ingredient.DeletedAt})
+		}
+	}
+	slices.SortStableFunc(ingredients, func(a, b models.Ingredient) int { return b.DeletedAt.Time.Compare(a.DeletedAt.Time) })
+	return ingredients, nil
+}
+
+func (r *memoryIngredientRepository) Restore(ctx context.Context, id uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredient, ok := r.m.ingredients[id]
+	if !ok || !ingredient.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	ingredient.DeletedAt = gorm.DeletedAt{}
+	ingredient.DeletedBy = 0
+	return nil
+}
+
+func (r *memoryIngredientRepository) Merge(ctx context.Context, canonicalID uint, duplicateIDs []uint, authorID uint) (*models.IngredientMergeReport, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	canonical, ok := r.m.ingredients[canonicalID]
+	if !ok || canonical.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: ingredient %d", ErrNotFound, canonicalID)
+	}
+
+	// Duplicados na lixeira também podem ser unificados
+	duplicates := make([]*models.Ingredient, 0, len(duplicateIDs))
+	for _, id := range duplicateIDs {
+		duplicate, ok := r.m.ingredients[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: ingredient %d", ErrNotFound, id)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	slices.SortFunc(duplicates, func(a, b *models.Ingredient) int { return int(a.ID) - int(b.ID) })
+
+	// As receitas passam a usar o canônico; se já o usam, a linha dele prevalece
+	report := &models.IngredientMergeReport{RecipeIDs: []uint{}}
+	for _, recipeID := range sortedIDs(r.m.recipes) {
+		recipe := r.m.recipes[recipeID]
+		hasCanonical := slices.ContainsFunc(recipe.IngredientsRecipes, func(line models.IngredientsRecipes) bool { return line.IngredientID == canonical.ID })
+		changed := false
+
+		lines := recipe.IngredientsRecipes[:0]
+		for _, line := range recipe.IngredientsRecipes {
+			if !slices.Contains(duplicateIDs, line.IngredientID) {
+				lines = append(lines, line)
+				continue
+			}
+			changed = true
+			if hasCanonical {
+				continue
+			}
+			line.IngredientID = canonical.ID
+			hasCanonical = true
+			lines = append(lines, line)
+		}
+		recipe.IngredientsRecipes = lines
+
+		if changed && !recipe.DeletedAt.Valid {
+			report.RecipeIDs = append(report.RecipeIDs, recipe.ID)
+		}
+	}
+
+	// Os nomes alternativos e as traduções dos duplicados passam para o canônico; as traduções de
+	// idiomas que ele já tem viram nomes alternativos
+	for _, duplicate := range duplicates {
+		for _, alias := range duplicate.Aliases {
+			alias.IngredientID = canonical.ID
+			canonical.Aliases = append(canonical.Aliases, alias)
+		}
+		duplicate.Aliases = nil
+	}
+	for _, duplicate := range duplicates {
+		for _, translation := range duplicate.Translations {
+			if slices.ContainsFunc(canonical.Translations, func(t models.IngredientTranslation) bool { return t.Locale == translation.Locale }) {
+				r.mergeAlias(canonical, translation.Name)
+				continue
+			}
+			translation.IngredientID = canonical.ID
+			canonical.Translations = append(canonical.Translations, translation)
+		}
+	}
+	for _, duplicate := range duplicates {
+		r.mergeAlias(canonical, duplicate.Name)
+		delete(r.m.ingredients, duplicate.ID)
+	}
+
+	report.Ingredient = cloneIngredient(*canonical)
+	return report, nil
+}
+
+func (r *memoryIngredientRepository) Localize(ctx context.Context, locale string, ingredients ...*models.Ingredient) error {
+	if locale == models.DefaultLocale {
+		return nil
+	}
+
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, ingredient := range ingredients {
+		stored, ok := r.m.ingredients[ingredient.ID]
+		if !ok {
+			continue
+		}
+		for _, translation := range stored.Translations {
+			if translation.Locale == locale {
+				ingredient.Name = translation.Name
+			}
+		}
+	}
+	return nil
+}
+
+// Funções privadas
+
+// filter retorna cópias dos ingredientes fora da lixeira que atendem à condição, em ordem de ID
+func (r *memoryIngredientRepository) filter(match func(ingredient *models.Ingredient) bool) []models.Ingredient {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	ingredients := []models.Ingredient{}
+	for _, id := range sortedIDs(r.m.ingredients) {
+		if ingredient := r.m.ingredients[id]; !ingredient.DeletedAt.Valid && match(ingredient) {
+			ingredients = append(ingredients, cloneIngredient(*ingredient))
+		}
+	}
+	return ingredients
+}
+
+// nameTaken indica que o nome já pertence a outro ingrediente, mesmo na lixeira
+func (r *memoryIngredientRepository) nameTaken(name string, id uint) bool {
+	for _, other := range r.m.ingredients {
+		if other.ID != id && other.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeAlias guarda o nome como nome alternativo do canônico, como em catalog.MergeIngredients
+func (r *memoryIngredientRepository) mergeAlias(canonical *models.Ingredient, name string) {
+	if strings.EqualFold(name, canonical.Name) {
+		return
+	}
+
+	if owner, i := r.m.aliasOwner(name); owner != nil {
+		alias := owner.Aliases[i]
+		if owner == canonical {
+			return
+		}
+		owner.Aliases = slices.Delete(owner.Aliases, i, i+1)
+		alias.IngredientID = canonical.ID
+		canonical.Aliases = append(canonical.Aliases, alias)
+		return
+	}
+
+	canonical.Aliases = append(canonical.Aliases, models.IngredientAlias{ID: r.m.nextID("ingredient_aliases"), IngredientID: canonical.ID, Name: name})
+}
+
+func cloneIngredient(ingredient models.Ingredient) models.Ingredient {
+	ingredient.Aliases = slices.Clone(ingredient.Aliases)
+	ingredient.Translations = slices.Clone(ingredient.Translations)
+	return ingredient
+}
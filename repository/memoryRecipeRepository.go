@@ -0,0 +1,632 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/catalog"
+	"main.go/history"
+	"main.go/models"
+)
+
+// memoryRecipeRepository é o RecipeRepository de Memory
+type memoryRecipeRepository struct {
+	m *Memory
+}
+
+func (r *memoryRecipeRepository) List(ctx context.Context, viewerID uint) ([]models.Recipe, error) {
+	return r.filter(func(recipe *models.Recipe) bool { return r.m.listed(recipe, viewerID) }, true), nil
+}
+
+func (r *memoryRecipeRepository) ListByUser(ctx context.Context, userID uint, viewerID uint) ([]models.Recipe, error) {
+	return r.filter(func(recipe *models.Recipe) bool {
+		return recipe.UserID == userID && r.m.listed(recipe, viewerID)
+	}, false), nil
+}
+
+func (r *memoryRecipeRepository) Get(ctx context.Context, id uint, viewerID uint) (*models.Recipe, error) {
+	return r.first(func(recipe *models.Recipe) bool {
+		return recipe.ID == id && r.m.visible(recipe, viewerID)
+	})
+}
+
+func (r *memoryRecipeRepository) GetByName(ctx context.Context, name string, viewerID uint) (*models.Recipe, error) {
+	slug := catalog.Slugify(name)
+	name = strings.ReplaceAll(name, "-", " ")
+
+	return r.first(func(recipe *models.Recipe) bool {
+		return (recipe.Slug == slug || strings.EqualFold(recipe.Name, name)) && r.m.visible(recipe, viewerID)
+	})
+}
+
+func (r *memoryRecipeRepository) GetBySlug(ctx context.Context, authorID uint, slug string, viewerID uint) (*models.Recipe, error) {
+	return r.first(func(recipe *models.Recipe) bool {
+		return recipe.UserID == authorID && recipe.Slug == slug && r.m.visible(recipe, viewerID)
+	})
+}
+
+func (r *memoryRecipeRepository) GetBySlugRedirect(ctx context.Context, authorID uint, slug string, viewerID uint) (*models.Recipe, error) {
+	var recipeID uint
+	r.m.mu.Lock()
+	for _, redirect := range r.m.redirects {
+		if redirect.UserID == authorID && redirect.Slug == slug {
+			recipeID = redirect.RecipeID
+		}
+	}
+	r.m.mu.Unlock()
+	if recipeID == 0 {
+		return nil, ErrNotFound
+	}
+
+	recipe, err := r.first(func(recipe *models.Recipe) bool {
+		return recipe.ID == recipeID && r.m.visible(recipe, viewerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	recipe.IngredientsRecipes = nil
+	return recipe, nil
+}
+
+func (r *memoryRecipeRepository) Find(ctx context.Context, id uint) (*models.Recipe, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipe, ok := r.m.recipes[id]
+	if !ok || recipe.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	found := *recipe
+	found.IngredientsRecipes = slices.Clone(recipe.IngredientsRecipes)
+	return &found, nil
+}
+
+func (r *memoryRecipeRepository) Visible(ctx context.Context, id uint, viewerID uint) (bool, error) {
+	_, err := r.Get(ctx, id, viewerID)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *memoryRecipeRepository) ForkCounts(ctx context.Context, ids []uint) (map[uint]int64, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	forks := map[uint]int64{}
+	for _, recipe := range r.m.recipes {
+		if recipe.ParentID != nil && slices.Contains(ids, *recipe.ParentID) && r.m.listed(recipe, 0) {
+			forks[*recipe.ParentID]++
+		}
+	}
+	return forks, nil
+}
+
+func (r *memoryRecipeRepository) Save(ctx context.Context, recipe *models.Recipe, opts SaveRecipeOptions) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	current, exists := r.m.recipes[recipe.ID]
+	if recipe.ID != 0 && !exists {
+		return ErrNotFound
+	}
+
+	var lines []models.IngredientsRecipes
+	var missing []string
+	if opts.Ingredients != nil {
+		var errs []models.FieldError
+		lines, missing, errs = r.resolveIngredients(opts.Ingredients, opts.CreateMissingIngredients)
+		if len(errs) > 0 {
+			return &InvalidIngredientsError{Errors: errs}
+		}
+	}
+
+	if r.nameTaken(recipe.UserID, recipe.Name, recipe.ID) {
+		return fmt.Errorf("%w: recipe %q already exists", ErrConflict, recipe.Name)
+	}
+
+	// Ingredientes informados pelo nome que ainda não existem, identificados nas linhas pela posição
+	// em missing. Um ingrediente na lixeira com o mesmo nome é restaurado.
+	created := make([]uint, len(missing))
+	for i, name := range missing {
+		if found := r.m.findIngredient(name, true); found != nil {
+			found.DeletedAt = gorm.DeletedAt{}
+			found.DeletedBy = 0
+			created[i] = found.ID
+			continue
+		}
+		id := r.m.nextID("ingredients")
+		r.m.ingredients[id] = &models.Ingredient{ID: id, Name: name}
+		created[i] = id
+	}
+	for i := range lines {
+		if lines[i].IngredientID == 0 {
+			lines[i].IngredientID = created[lines[i].Position]
+		}
+	}
+
+	r.assignSlug(recipe)
+
+	if recipe.ID == 0 {
+		recipe.ID = r.m.nextID("recipes")
+	}
+	if recipe.Visibility == "" {
+		recipe.Visibility = models.VisibilityPublished
+	}
+
+	stored := *recipe
+	stored.Images, stored.Forks, stored.SlugRedirects, stored.Revisions = nil, nil, nil, nil
+	stored.IngredientsRecipes = nil
+	if exists {
+		stored.IngredientsRecipes = current.IngredientsRecipes
+	}
+	if opts.Ingredients != nil {
+		stored.IngredientsRecipes = make([]models.IngredientsRecipes, len(lines))
+		for i, line := range lines {
+			line.RecipeID = recipe.ID
+			line.Position = i
+			line.Ingredient = models.Ingredient{}
+			stored.IngredientsRecipes[i] = line
+		}
+	}
+	r.m.recipes[recipe.ID] = &stored
+
+	_, err := r.m.record(recipe.ID, opts.AuthorID, opts.Summary)
+	return err
+}
+
+func (r *memoryRecipeRepository) Delete(ctx context.Context, id uint, deletedBy uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipe, ok := r.m.recipes[id]
+	if !ok || recipe.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	recipe.DeletedAt = r.m.deletedAt()
+	recipe.DeletedBy = deletedBy
+	return nil
+}
+
+func (r *memoryRecipeRepository) AddIngredient(ctx context.Context, line *models.IngredientsRecipes, authorID uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipe, ok := r.m.recipes[line.RecipeID]
+	if !ok {
+		return fmt.Errorf("%w: recipe %d not found", ErrConflict, line.RecipeID)
+	}
+	if _, ok := r.m.ingredients[line.IngredientID]; !ok {
+		return fmt.Errorf("%w: ingredient %d not found", ErrConflict, line.IngredientID)
+	}
+
+	// O ingrediente adicionado vai para o fim da lista
+	line.Position = 0
+	for _, other := range recipe.IngredientsRecipes {
+		if other.IngredientID == line.IngredientID {
+			return fmt.Errorf("%w: ingredient %d is already in the recipe", ErrConflict, line.IngredientID)
+		}
+		line.Position = max(line.Position, other.Position+1)
+	}
+
+	stored := *line
+	stored.Ingredient = models.Ingredient{}
+	recipe.IngredientsRecipes = append(recipe.IngredientsRecipes, stored)
+
+	_, err := r.m.record(line.RecipeID, authorID, "")
+	return err
+}
+
+func (r *memoryRecipeRepository) UpdateIngredient(ctx context.Context, line *models.IngredientsRecipes, authorID uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	stored := r.line(line.RecipeID, line.IngredientID)
+	if stored == nil {
+		return ErrNotFound
+	}
+	stored.Quantity = line.Quantity
+	stored.Group = line.Group
+	stored.Note = line.Note
+	stored.Optional = line.Optional
+	stored.ToTaste = line.ToTaste
+
+	_, err := r.m.record(line.RecipeID, authorID, "")
+	return err
+}
+
+func (r *memoryRecipeRepository) RemoveIngredient(ctx context.Context, recipeID uint, ingredientID uint, authorID uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if r.line(recipeID, ingredientID) == nil {
+		return ErrNotFound
+	}
+	recipe := r.m.recipes[recipeID]
+	recipe.IngredientsRecipes = slices.DeleteFunc(recipe.IngredientsRecipes, func(line models.IngredientsRecipes) bool {
+		return line.IngredientID == ingredientID
+	})
+
+	_, err := r.m.record(recipeID, authorID, "")
+	return err
+}
+
+func (r *memoryRecipeRepository) ReorderIngredients(ctx context.Context, recipeID uint, ingredientIDs []uint, authorID uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if _, ok := r.m.recipes[recipeID]; !ok {
+		return ErrNotFound
+	}
+	for position, ingredientID := range ingredientIDs {
+		if line := r.line(recipeID, ingredientID); line != nil {
+			line.Position = position
+		}
+	}
+
+	_, err := r.m.record(recipeID, authorID, "")
+	return err
+}
+
+func (r *memoryRecipeRepository) Fork(ctx context.Context, parentID uint, opts ForkRecipeOptions) (*models.Recipe, error) {
+	r.m.mu.Lock()
+
+	// Só podem ser copiadas as receitas visíveis para o usuário
+	parent, ok := r.m.recipes[parentID]
+	if !ok || !r.m.visible(parent, opts.UserID) {
+		r.m.mu.Unlock()
+		return nil, ErrNotFound
+	}
+
+	name := opts.Name
+	if name == "" {
+		base := parent.Name + opts.NameSuffix
+		name = base
+		for n := 2; r.nameTaken(opts.UserID, name, 0); n++ {
+			name = fmt.Sprintf("%s (%d)", base, n)
+		}
+	}
+	if r.nameTaken(opts.UserID, name, 0) {
+		r.m.mu.Unlock()
+		return nil, fmt.Errorf("%w: recipe %q already exists", ErrConflict, name)
+	}
+
+	fork := &models.Recipe{
+		ID:                 r.m.nextID("recipes"),
+		UserID:             opts.UserID,
+		Name:               name,
+		Instructions:       parent.Instructions,
+		Servings:           parent.Servings,
+		PrepTime:           parent.PrepTime,
+		CookTime:           parent.CookTime,
+		TotalTime:          parent.TotalTime,
+		Visibility:         models.VisibilityDraft,
+		ParentID:           &parent.ID,
+		IngredientsRecipes: r.m.recipeView(parent).IngredientsRecipes,
+	}
+	if opts.Substitute != "" {
+		ids := make([]uint, 0, len(fork.IngredientsRecipes))
+		for _, line := range fork.IngredientsRecipes {
+			ids = append(ids, line.IngredientID)
+		}
+		fork.IngredientsRecipes = catalog.Substitute(fork.IngredientsRecipes, r.m.findSubstitutions(ids, opts.Substitute))
+	}
+	for i := range fork.IngredientsRecipes {
+		fork.IngredientsRecipes[i].RecipeID = fork.ID
+		fork.IngredientsRecipes[i].Ingredient = models.Ingredient{}
+	}
+	r.assignSlug(fork)
+	r.m.recipes[fork.ID] = fork
+
+	summary := fmt.Sprintf("forked from recipe %d", parent.ID)
+	if opts.Substitute != "" {
+		summary += fmt.Sprintf(" with %s substitutions", opts.Substitute)
+	}
+	_, err := r.m.record(fork.ID, opts.UserID, summary)
+	r.m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.first(func(recipe *models.Recipe) bool { return recipe.ID == fork.ID })
+}
+
+func (r *memoryRecipeRepository) Forks(ctx context.Context, parentIDs []uint, viewerID uint) ([]models.Recipe, error) {
+	return r.filter(func(recipe *models.Recipe) bool {
+		return recipe.ParentID != nil && slices.Contains(parentIDs, *recipe.ParentID) && r.m.listed(recipe, viewerID)
+	}, false), nil
+}
+
+func (r *memoryRecipeRepository) Trash(ctx context.Context, userID uint) ([]models.Recipe, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipes := []models.Recipe{}
+	for _, id := range sortedIDs(r.m.recipes) {
+		recipe := r.m.recipes[id]
+		if recipe.DeletedAt.Valid && (userID == 0 || recipe.UserID == userID) {
+			recipes = append(recipes, models.Recipe{ID: recipe.ID, Name: recipe.Name, UserID: recipe.UserID, DeletedAt: recipe.DeletedAt})
+		}
+	}
+	slices.SortStableFunc(recipes, func(a, b models.Recipe) int { return b.DeletedAt.Time.Compare(a.DeletedAt.Time) })
+	return recipes, nil
+}
+
+func (r *memoryRecipeRepository) FindDeleted(ctx context.Context, id uint) (*models.Recipe, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipe, ok := r.m.recipes[id]
+	if !ok || !recipe.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	found := *recipe
+	found.IngredientsRecipes = nil
+	return &found, nil
+}
+
+func (r *memoryRecipeRepository) Restore(ctx context.Context, id uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipe, ok := r.m.recipes[id]
+	if !ok || !recipe.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	recipe.DeletedAt = gorm.DeletedAt{}
+	recipe.DeletedBy = 0
+	return nil
+}
+
+func (r *memoryRecipeRepository) Revisions(ctx context.Context, recipeID uint) ([]models.RecipeRevision, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	revisions := []models.RecipeRevision{}
+	for i := len(r.m.revisions) - 1; i >= 0; i-- {
+		if revision := r.m.revisions[i]; revision.RecipeID == recipeID {
+			revision.Snapshot = ""
+			revisions = append(revisions, revision)
+		}
+	}
+	return revisions, nil
+}
+
+func (r *memoryRecipeRepository) Revision(ctx context.Context, recipeID uint, number int) (*models.RecipeRevision, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, revision := range r.m.revisions {
+		if revision.RecipeID == recipeID && revision.Number == number {
+			return &revision, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryRecipeRepository) RestoreRevision(ctx context.Context, revision *models.RecipeRevision, authorID uint) (*models.RecipeRevision, error) {
+	snapshot, err := history.Decode(revision)
+	if err != nil {
+		return nil, err
+	}
+
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipe, ok := r.m.recipes[revision.RecipeID]
+	if !ok || recipe.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	restored := *recipe
+	restoreSnapshot(&restored, snapshot)
+	if r.nameTaken(restored.UserID, restored.Name, restored.ID) {
+		return nil, fmt.Errorf("%w: recipe %q already exists", ErrConflict, restored.Name)
+	}
+
+	// Ingredientes removidos do catálogo desde a revisão são cadastrados novamente pelo nome
+	restored.IngredientsRecipes = make([]models.IngredientsRecipes, len(snapshot.Ingredients))
+	for i, item := range snapshot.Ingredients {
+		ingredientID := item.IngredientID
+		if _, ok := r.m.ingredients[ingredientID]; !ok {
+			if found := r.m.findIngredient(item.Name, true); found != nil {
+				found.DeletedAt = gorm.DeletedAt{}
+				found.DeletedBy = 0
+				ingredientID = found.ID
+			} else {
+				ingredientID = r.m.nextID("ingredients")
+				r.m.ingredients[ingredientID] = &models.Ingredient{ID: ingredientID, Name: item.Name}
+			}
+		}
+		restored.IngredientsRecipes[i] = models.IngredientsRecipes{
+			RecipeID:     recipe.ID,
+			IngredientID: ingredientID,
+			Quantity:     item.Quantity,
+			Position:     i,
+			Group:        item.Group,
+			Note:         item.Note,
+			Optional:     item.Optional,
+			ToTaste:      item.ToTaste,
+		}
+	}
+
+	r.assignSlug(&restored)
+	*recipe = restored
+
+	return r.m.record(recipe.ID, authorID, fmt.Sprintf("restored revision %d", revision.Number))
+}
+
+// Funções privadas
+
+// filter retorna cópias das receitas que atendem à condição, em ordem de ID, com os ingredientes e
+// as imagens quando pedido
+func (r *memoryRecipeRepository) filter(match func(recipe *models.Recipe) bool, ingredients bool) []models.Recipe {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	recipes := []models.Recipe{}
+	for _, id := range sortedIDs(r.m.recipes) {
+		recipe := r.m.recipes[id]
+		if !match(recipe) {
+			continue
+		}
+		view := r.m.recipeView(recipe)
+		if !ingredients {
+			view.IngredientsRecipes, view.Images = nil, nil
+		}
+		recipes = append(recipes, view)
+	}
+	return recipes
+}
+
+func (r *memoryRecipeRepository) first(match func(recipe *models.Recipe) bool) (*models.Recipe, error) {
+	recipes := r.filter(match, true)
+	if len(recipes) == 0 {
+		return nil, ErrNotFound
+	}
+	return &recipes[0], nil
+}
+
+// nameTaken indica que o usuário já tem outra receita com o nome, mesmo na lixeira
+func (r *memoryRecipeRepository) nameTaken(userID uint, name string, id uint) bool {
+	for _, other := range r.m.recipes {
+		if other.ID != id && other.UserID == userID && other.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// line retorna a linha guardada do ingrediente na receita, fora da lixeira
+func (r *memoryRecipeRepository) line(recipeID uint, ingredientID uint) *models.IngredientsRecipes {
+	recipe, ok := r.m.recipes[recipeID]
+	if !ok || recipe.DeletedAt.Valid {
+		return nil
+	}
+	for i := range recipe.IngredientsRecipes {
+		if recipe.IngredientsRecipes[i].IngredientID == ingredientID {
+			return &recipe.IngredientsRecipes[i]
+		}
+	}
+	return nil
+}
+
+// resolveIngredients segue catalog.ResolveIngredients, sem gravar nada: os ingredientes a cadastrar
+// são retornados em missing, e as linhas deles ficam com IngredientID 0 e a posição em missing.
+func (r *memoryRecipeRepository) resolveIngredients(items []models.RecipeIngredientRequest, createMissing bool) ([]models.IngredientsRecipes, []string, []models.FieldError) {
+	var lines []models.IngredientsRecipes
+	var missing []string
+	var errs []models.FieldError
+
+	// Posição de cada ingrediente na lista, para apontar os repetidos
+	positions := map[string]int{}
+
+	for i, item := range items {
+		field := fmt.Sprintf("ingredients[%d]", i)
+		name := strings.TrimSpace(item.Name)
+		quantity := strings.TrimSpace(item.Quantity)
+
+		if quantity == "" && !item.ToTaste {
+			errs = append(errs, models.FieldError{Field: field + ".quantity", Message: "quantity is required"})
+		}
+
+		line := models.IngredientsRecipes{
+			Quantity: quantity,
+			Group:    strings.TrimSpace(item.Group),
+			Note:     strings.TrimSpace(item.Note),
+			Optional: item.Optional,
+			ToTaste:  item.ToTaste,
+		}
+
+		var key string
+		switch {
+		case item.IngredientID != 0:
+			ingredient, ok := r.m.ingredients[item.IngredientID]
+			if !ok || ingredient.DeletedAt.Valid {
+				errs = append(errs, models.FieldError{Field: field + ".ingredient_id", Message: fmt.Sprintf("ingredient %d not found", item.IngredientID)})
+				continue
+			}
+			line.IngredientID = ingredient.ID
+		case name != "":
+			found := r.m.findIngredient(name, false)
+			switch {
+			case found != nil:
+				line.IngredientID = found.ID
+			case createMissing:
+				key = strings.ToLower(name)
+				if !slices.ContainsFunc(missing, func(other string) bool { return strings.EqualFold(other, name) }) {
+					missing = append(missing, name)
+				}
+				line.Position = slices.IndexFunc(missing, func(other string) bool { return strings.EqualFold(other, name) })
+			default:
+				errs = append(errs, models.FieldError{Field: field + ".name", Message: fmt.Sprintf("ingredient %q not found", name)})
+				continue
+			}
+		default:
+			errs = append(errs, models.FieldError{Field: field, Message: "ingredient_id or name is required"})
+			continue
+		}
+
+		if key == "" {
+			key = fmt.Sprint(line.IngredientID)
+		}
+		if j, ok := positions[key]; ok {
+			errs = append(errs, models.FieldError{Field: field, Message: fmt.Sprintf("ingredient is already listed in ingredients[%d]", j)})
+			continue
+		}
+		positions[key] = i
+
+		lines = append(lines, line)
+	}
+
+	return lines, missing, errs
+}
+
+// assignSlug segue catalog.AssignSlug: o slug atual é mantido enquanto o nome e o autor não mudarem;
+// caso contrário, o antigo passa a redirecionar para o novo
+func (r *memoryRecipeRepository) assignSlug(recipe *models.Recipe) {
+	base := catalog.Slugify(recipe.Name)
+
+	if current, ok := r.m.recipes[recipe.ID]; ok && current.Slug != "" {
+		if current.UserID == recipe.UserID && catalog.Slugify(current.Name) == base {
+			recipe.Slug = current.Slug
+			return
+		}
+
+		redirect := models.RecipeSlugRedirect{RecipeID: recipe.ID, UserID: current.UserID, Slug: current.Slug}
+		if !slices.ContainsFunc(r.m.redirects, func(other models.RecipeSlugRedirect) bool {
+			return other.RecipeID == redirect.RecipeID && other.UserID == redirect.UserID && other.Slug == redirect.Slug
+		}) {
+			redirect.ID = r.m.nextID("recipe_slug_redirects")
+			r.m.redirects = append(r.m.redirects, redirect)
+		}
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		taken := false
+		for _, other := range r.m.recipes {
+			taken = taken || (other.ID != recipe.ID && other.UserID == recipe.UserID && other.Slug == candidate)
+		}
+		for _, redirect := range r.m.redirects {
+			taken = taken || (redirect.RecipeID != recipe.ID && redirect.UserID == recipe.UserID && redirect.Slug == candidate)
+		}
+		if taken {
+			continue
+		}
+
+		// Um slug antigo da própria receita volta a ser o atual
+		recipe.Slug = candidate
+		r.m.redirects = slices.DeleteFunc(r.m.redirects, func(redirect models.RecipeSlugRedirect) bool {
+			return redirect.RecipeID == recipe.ID && redirect.UserID == recipe.UserID && redirect.Slug == candidate
+		})
+		return
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"main.go/catalog"
+	"main.go/models"
+)
+
+// memorySubstitutionRepository é o SubstitutionRepository de Memory
+type memorySubstitutionRepository struct {
+	m *Memory
+}
+
+func (r *memorySubstitutionRepository) List(ctx context.Context, ingredientID uint, reason string) ([]models.Substitution, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	substitutions := []models.Substitution{}
+	for _, id := range sortedIDs(r.m.substitutions) {
+		substitution := r.m.substitutions[id]
+		if (ingredientID == 0 || substitution.IngredientID == ingredientID) && (reason == "" || substitution.Reason == reason) {
+			substitutions = append(substitutions, r.m.substitutionView(substitution))
+		}
+	}
+	return substitutions, nil
+}
+
+func (r *memorySubstitutionRepository) Get(ctx context.Context, id uint) (*models.Substitution, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	substitution, ok := r.m.substitutions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	view := r.m.substitutionView(substitution)
+	return &view, nil
+}
+
+func (r *memorySubstitutionRepository) Save(ctx context.Context, substitution *models.Substitution) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if substitution.ID == 0 {
+		substitution.ID = r.m.nextID("substitutions")
+	} else if _, ok := r.m.substitutions[substitution.ID]; !ok {
+		return ErrNotFound
+	}
+
+	stored := *substitution
+	stored.Ingredient = models.Ingredient{}
+	stored.Replacements = slices.Clone(substitution.Replacements)
+	for i := range stored.Replacements {
+		substitution.Replacements[i].ID = r.m.nextID("substitution_items")
+		substitution.Replacements[i].SubstitutionID = substitution.ID
+		stored.Replacements[i] = substitution.Replacements[i]
+		stored.Replacements[i].Ingredient = models.Ingredient{}
+	}
+	r.m.substitutions[stored.ID] = &stored
+	return nil
+}
+
+func (r *memorySubstitutionRepository) Delete(ctx context.Context, id uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if _, ok := r.m.substitutions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.m.substitutions, id)
+	return nil
+}
+
+func (r *memorySubstitutionRepository) Suggest(ctx context.Context, recipe *models.Recipe, reason string, locale string) (*models.RecipeSubstitutions, error) {
+	ids := make([]uint, 0, len(recipe.IngredientsRecipes))
+	for _, item := range recipe.IngredientsRecipes {
+		ids = append(ids, item.IngredientID)
+	}
+
+	r.m.mu.Lock()
+	found := r.m.findSubstitutions(ids, reason)
+	r.m.mu.Unlock()
+
+	// Traduz os nomes como catalog.SuggestSubstitutions
+	ingredients := make([]*models.Ingredient, 0, len(ids))
+	for i := range recipe.IngredientsRecipes {
+		ingredients = append(ingredients, &recipe.IngredientsRecipes[i].Ingredient)
+	}
+	for _, substitutions := range found {
+		for i := range substitutions {
+			for j := range substitutions[i].Replacements {
+				ingredients = append(ingredients, &substitutions[i].Replacements[j].Ingredient)
+			}
+		}
+	}
+	if err := r.m.Ingredients().Localize(ctx, locale, ingredients...); err != nil {
+		return nil, err
+	}
+
+	return catalog.Suggestions(recipe, found), nil
+}
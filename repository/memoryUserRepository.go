@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"main.go/models"
+)
+
+// memoryUserRepository é o UserRepository de Memory
+type memoryUserRepository struct {
+	m *Memory
+}
+
+func (r *memoryUserRepository) List(ctx context.Context) ([]models.User, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	users := []models.User{}
+	for _, id := range sortedIDs(r.m.users) {
+		if user := r.m.users[id]; !user.DeletedAt.Valid {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) Get(ctx context.Context, id uint) (*models.User, error) {
+	return r.first(func(user *models.User) bool { return user.ID == id })
+}
+
+func (r *memoryUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.first(func(user *models.User) bool { return user.Email == email })
+}
+
+func (r *memoryUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.first(func(user *models.User) bool { return user.Username == username })
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if r.taken(user) {
+		return ErrConflict
+	}
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+
+	user.ID = r.m.nextID("users")
+	stored := *user
+	r.m.users[user.ID] = &stored
+	return nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *models.User) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	if _, ok := r.m.users[user.ID]; !ok {
+		return ErrNotFound
+	}
+	if r.taken(user) {
+		return ErrConflict
+	}
+
+	stored := *user
+	r.m.users[user.ID] = &stored
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	user, ok := r.m.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	user.DeletedAt = r.m.deletedAt()
+
+	for _, recipe := range r.m.recipes {
+		if recipe.UserID == id && !recipe.DeletedAt.Valid {
+			recipe.DeletedAt = user.DeletedAt
+		}
+	}
+	return nil
+}
+
+func (r *memoryUserRepository) Restore(ctx context.Context, id uint) error {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	user, ok := r.m.users[id]
+	if !ok || !user.DeletedAt.Valid {
+		return ErrNotFound
+	}
+
+	for _, recipe := range r.m.recipes {
+		if recipe.UserID == id && recipe.DeletedAt.Valid && !recipe.DeletedAt.Time.Before(user.DeletedAt.Time) {
+			recipe.DeletedAt = gorm.DeletedAt{}
+			recipe.DeletedBy = 0
+		}
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (r *memoryUserRepository) Usernames(ctx context.Context, ids []uint) (map[uint]string, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	names := map[uint]string{}
+	for _, id := range ids {
+		if user, ok := r.m.users[id]; ok && !user.DeletedAt.Valid {
+			names[id] = user.Username
+		}
+	}
+	return names, nil
+}
+
+// Funções privadas
+
+// first busca o primeiro usuário fora da lixeira que atende à condição
+func (r *memoryUserRepository) first(match func(user *models.User) bool) (*models.User, error) {
+	r.m.mu.Lock()
+	defer r.m.mu.Unlock()
+
+	for _, id := range sortedIDs(r.m.users) {
+		if user := r.m.users[id]; !user.DeletedAt.Valid && match(user) {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// taken indica que o nome de usuário ou o e-mail já pertencem a outro usuário, mesmo na lixeira
+func (r *memoryUserRepository) taken(user *models.User) bool {
+	for _, other := range r.m.users {
+		if other.ID != user.ID && (other.Username == user.Username || other.Email == user.Email) {
+			return true
+		}
+	}
+	return false
+}
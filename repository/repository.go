@@ -0,0 +1,229 @@
+// Package repository isola o acesso aos dados dos usuários, receitas, ingredientes, substituições,
+// imagens e exportações atrás de interfaces, com uma implementação sobre o GORM e outra em memória,
+// usada nos testes dos handlers.
+// A importação e a exportação do catálogo, que gravam e leem o catálogo inteiro, só têm a
+// implementação sobre o GORM.
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"main.go/catalog"
+	"main.go/models"
+)
+
+// ErrNotFound é retornado quando o registro não existe ou não é visível para o usuário.
+var ErrNotFound = errors.New("repository: record not found")
+
+// ErrConflict é retornado quando a gravação viola uma restrição, como um nome já existente.
+var ErrConflict = errors.New("repository: record conflicts with an existing one")
+
+// InvalidIngredientsError lista os problemas dos ingredientes informados ao gravar uma receita.
+type InvalidIngredientsError struct {
+	Errors []models.FieldError
+}
+
+func (e *InvalidIngredientsError) Error() string {
+	return "repository: invalid recipe ingredients"
+}
+
+// UserRepository acessa os usuários.
+type UserRepository interface {
+	// List retorna todos os usuários fora da lixeira.
+	List(ctx context.Context) ([]models.User, error)
+	// Get busca o usuário pelo ID.
+	Get(ctx context.Context, id uint) (*models.User, error)
+	// GetByEmail busca o usuário pelo e-mail, usado no login.
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// GetByUsername busca o usuário pelo nome de usuário.
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	// Create grava um novo usuário, retornando ErrConflict se o nome ou o e-mail já forem usados.
+	Create(ctx context.Context, user *models.User) error
+	// Update grava os dados do usuário já existente.
+	Update(ctx context.Context, user *models.User) error
+	// Delete move o usuário para a lixeira, junto com as receitas dele.
+	Delete(ctx context.Context, id uint) error
+	// Restore tira o usuário da lixeira, com as receitas que foram para lá junto com ele ou depois
+	// dele, retornando ErrNotFound se ele não estiver lá.
+	Restore(ctx context.Context, id uint) error
+	// Usernames retorna o nome de cada usuário encontrado entre os IDs informados.
+	Usernames(ctx context.Context, ids []uint) (map[uint]string, error)
+}
+
+// SaveRecipeOptions complementa a gravação de uma receita.
+type SaveRecipeOptions struct {
+	// Ingredients substitui todos os ingredientes da receita; nil mantém os atuais.
+	Ingredients []models.RecipeIngredientRequest
+	// CreateMissingIngredients cadastra os ingredientes informados pelo nome que ainda não existem.
+	CreateMissingIngredients bool
+	// AuthorID e Summary identificam a revisão registrada no histórico.
+	AuthorID uint
+	Summary  string
+}
+
+// ForkRecipeOptions complementa a cópia de uma receita.
+type ForkRecipeOptions struct {
+	// UserID é o dono da cópia, que precisa poder ver a receita original.
+	UserID uint
+	// Name é o nome da cópia. Vazio, usa o nome da original seguido de NameSuffix, com um sufixo
+	// numérico se o usuário já tiver uma receita com esse nome.
+	Name       string
+	NameSuffix string
+	// Substitute troca os ingredientes que têm substituição para o motivo (ex.: vegan).
+	Substitute string
+}
+
+// RecipeRepository acessa as receitas e seus ingredientes. Os métodos que recebem viewerID só
+// encontram as receitas visíveis para esse usuário (0 para anônimos), como em catalog.Visible e
+// catalog.Listed. As alterações registram uma revisão no histórico da receita.
+type RecipeRepository interface {
+	// List retorna as receitas listadas para o usuário, com ingredientes e imagens.
+	List(ctx context.Context, viewerID uint) ([]models.Recipe, error)
+	// ListByUser retorna as receitas do autor listadas para o usuário, sem os ingredientes.
+	ListByUser(ctx context.Context, userID uint, viewerID uint) ([]models.Recipe, error)
+	// Get busca a receita visível pelo ID, com ingredientes e imagens.
+	Get(ctx context.Context, id uint, viewerID uint) (*models.Recipe, error)
+	// GetByName busca a receita visível mais antiga pelo slug ou pelo nome, sem diferenciar
+	// maiúsculas e minúsculas e convertendo '-' para espaços.
+	GetByName(ctx context.Context, name string, viewerID uint) (*models.Recipe, error)
+	// GetBySlug busca a receita visível do autor pelo slug atual.
+	GetBySlug(ctx context.Context, authorID uint, slug string, viewerID uint) (*models.Recipe, error)
+	// GetBySlugRedirect busca a receita visível do autor que usava o slug antigo, sem os ingredientes.
+	GetBySlugRedirect(ctx context.Context, authorID uint, slug string, viewerID uint) (*models.Recipe, error)
+	// Find busca a receita pelo ID, independentemente da visibilidade, com as linhas de ingredientes
+	// mas sem os ingredientes.
+	Find(ctx context.Context, id uint) (*models.Recipe, error)
+	// Visible indica se a receita existe e é visível para o usuário.
+	Visible(ctx context.Context, id uint, viewerID uint) (bool, error)
+	// ForkCounts conta as cópias publicadas feitas diretamente a partir de cada receita.
+	ForkCounts(ctx context.Context, ids []uint) (map[uint]int64, error)
+	// Save cria a receita, quando não tem ID, ou a atualiza, definindo o slug. Ingredientes
+	// inválidos resultam em *InvalidIngredientsError, e nome ou slug já usados, em ErrConflict.
+	Save(ctx context.Context, recipe *models.Recipe, opts SaveRecipeOptions) error
+	// Delete move a receita para a lixeira, registrando quem a removeu.
+	Delete(ctx context.Context, id uint, deletedBy uint) error
+	// AddIngredient coloca o ingrediente no fim da lista da receita. Retorna ErrConflict se a receita
+	// ou o ingrediente não existirem, ou se o ingrediente já estiver na receita.
+	AddIngredient(ctx context.Context, line *models.IngredientsRecipes, authorID uint) error
+	// UpdateIngredient grava a quantidade, o grupo, a observação e as marcações da linha.
+	UpdateIngredient(ctx context.Context, line *models.IngredientsRecipes, authorID uint) error
+	// RemoveIngredient tira o ingrediente da receita.
+	RemoveIngredient(ctx context.Context, recipeID uint, ingredientID uint, authorID uint) error
+	// ReorderIngredients grava a posição de cada ingrediente pela ordem da lista, que deve conter
+	// todos os ingredientes da receita.
+	ReorderIngredients(ctx context.Context, recipeID uint, ingredientIDs []uint, authorID uint) error
+	// Fork copia a receita visível para o usuário, com seus ingredientes, como rascunho dele. Retorna a
+	// cópia com os ingredientes, ErrNotFound se a original não for visível ou ErrConflict se o nome
+	// já for usado.
+	Fork(ctx context.Context, parentID uint, opts ForkRecipeOptions) (*models.Recipe, error)
+	// Forks retorna as cópias listadas para o usuário feitas diretamente a partir das receitas, em
+	// ordem de ID e sem os ingredientes.
+	Forks(ctx context.Context, parentIDs []uint, viewerID uint) ([]models.Recipe, error)
+	// Trash retorna as receitas do autor na lixeira (de todos os autores, com userID 0), da removida
+	// mais recentemente para a mais antiga, sem os ingredientes.
+	Trash(ctx context.Context, userID uint) ([]models.Recipe, error)
+	// FindDeleted busca a receita na lixeira pelo ID.
+	FindDeleted(ctx context.Context, id uint) (*models.Recipe, error)
+	// Restore tira a receita da lixeira, com seus ingredientes, imagens e histórico.
+	Restore(ctx context.Context, id uint) error
+	// Revisions retorna as revisões da receita, da mais recente para a mais antiga, sem o estado
+	// guardado em cada uma.
+	Revisions(ctx context.Context, recipeID uint) ([]models.RecipeRevision, error)
+	// Revision busca a revisão da receita pelo número, com o estado guardado.
+	Revision(ctx context.Context, recipeID uint, number int) (*models.RecipeRevision, error)
+	// RestoreRevision volta a receita e seus ingredientes ao estado guardado na revisão, cadastrando
+	// novamente pelo nome os ingredientes que não existem mais, e retorna a revisão registrada. Quando
+	// a receita já está igual à revisão, o retorno é nil. Retorna ErrConflict se o nome já for usado.
+	RestoreRevision(ctx context.Context, revision *models.RecipeRevision, authorID uint) (*models.RecipeRevision, error)
+}
+
+// IngredientRepository acessa os ingredientes, com os nomes alternativos e as traduções.
+type IngredientRepository interface {
+	// List retorna todos os ingredientes fora da lixeira.
+	List(ctx context.Context) ([]models.Ingredient, error)
+	// Get busca o ingrediente pelo ID.
+	Get(ctx context.Context, id uint) (*models.Ingredient, error)
+	// Search busca os ingredientes cujo nome, nome alternativo ou tradução contém o texto, sem
+	// diferenciar maiúsculas e minúsculas.
+	Search(ctx context.Context, text string) ([]models.Ingredient, error)
+	// Create grava o ingrediente com os nomes alternativos e as traduções, retornando ErrConflict
+	// se o nome já for usado.
+	Create(ctx context.Context, ingredient *models.Ingredient) error
+	// Find busca o ingrediente fora da lixeira pelo nome, nome alternativo ou tradução, como em
+	// catalog.FindIngredient.
+	Find(ctx context.Context, name string) (*models.Ingredient, error)
+	// Rename troca o nome do ingrediente.
+	Rename(ctx context.Context, id uint, name string) error
+	// AddAlias grava o nome alternativo do ingrediente, retornando ErrConflict se o nome já for outro
+	// nome alternativo ou levar a outro ingrediente.
+	AddAlias(ctx context.Context, alias *models.IngredientAlias) error
+	// RemoveAlias apaga o nome alternativo do ingrediente, sem diferenciar maiúsculas e minúsculas.
+	RemoveAlias(ctx context.Context, ingredientID uint, name string) error
+	// SetTranslation grava o nome do ingrediente no idioma, substituindo o atual. Retorna ErrConflict
+	// se o nome levar a outro ingrediente.
+	SetTranslation(ctx context.Context, translation *models.IngredientTranslation) error
+	// RemoveTranslation apaga o nome do ingrediente no idioma.
+	RemoveTranslation(ctx context.Context, ingredientID uint, locale string) error
+	// Usage conta as receitas que usam o ingrediente e lista as que são visíveis para o usuário.
+	Usage(ctx context.Context, id uint, viewerID uint) (*models.IngredientUsage, error)
+	// Delete move o ingrediente para a lixeira, registrando quem o removeu.
+	Delete(ctx context.Context, id uint, deletedBy uint) error
+	// Trash retorna os ingredientes na lixeira, do removido mais recentemente para o mais antigo.
+	Trash(ctx context.Context) ([]models.Ingredient, error)
+	// Restore tira o ingrediente da lixeira, retornando ErrNotFound se ele não estiver lá.
+	Restore(ctx context.Context, id uint) error
+	// Merge unifica os duplicados ao ingrediente canônico, como em catalog.MergeIngredients.
+	Merge(ctx context.Context, canonicalID uint, duplicateIDs []uint, authorID uint) (*models.IngredientMergeReport, error)
+	// Localize troca o nome dos ingredientes pela tradução no idioma informado, quando houver.
+	Localize(ctx context.Context, locale string, ingredients ...*models.Ingredient) error
+}
+
+// SubstitutionRepository acessa as substituições de ingredientes, com os ingredientes usados em cada
+// uma.
+type SubstitutionRepository interface {
+	// List retorna as substituições em ordem de ID, filtradas pelo ingrediente substituído e pelo
+	// motivo quando informados (diferentes de 0 e vazio).
+	List(ctx context.Context, ingredientID uint, reason string) ([]models.Substitution, error)
+	// Get busca a substituição pelo ID.
+	Get(ctx context.Context, id uint) (*models.Substitution, error)
+	// Save cria a substituição, quando não tem ID, ou a atualiza, substituindo os ingredientes usados.
+	Save(ctx context.Context, substitution *models.Substitution) error
+	// Delete apaga a substituição.
+	Delete(ctx context.Context, id uint) error
+	// Suggest lista as substituições para cada ingrediente da receita já carregada, como em
+	// catalog.SuggestSubstitutions.
+	Suggest(ctx context.Context, recipe *models.Recipe, reason string, locale string) (*models.RecipeSubstitutions, error)
+}
+
+// ImageRepository acessa os registros das imagens das receitas. Os arquivos ficam no
+// storage.BlobStore, a cargo de quem chama.
+type ImageRepository interface {
+	// Get busca a imagem da receita pelo ID, com as miniaturas.
+	Get(ctx context.Context, recipeID uint, id uint) (*models.RecipeImage, error)
+	// Create grava a imagem com as miniaturas.
+	Create(ctx context.Context, image *models.RecipeImage) error
+	// Delete apaga a imagem e as miniaturas.
+	Delete(ctx context.Context, id uint) error
+}
+
+// ExportRepository acessa os pedidos de exportação de livros de receitas, processados pelo
+// cookbook.Exporter.
+type ExportRepository interface {
+	// Create grava o pedido de exportação.
+	Create(ctx context.Context, export *models.CookbookExport) error
+	// Get busca a exportação pedida pelo usuário.
+	Get(ctx context.Context, id uint, userID uint) (*models.CookbookExport, error)
+	// Fail marca a exportação como falha, com a mensagem de erro.
+	Fail(ctx context.Context, export *models.CookbookExport, message string) error
+}
+
+// CatalogRepository importa e exporta o catálogo inteiro de ingredientes ou receitas, nos formatos do
+// pacote catalog.
+type CatalogRepository interface {
+	// Import grava os registros do tipo kind lidos de r, como em catalog.Import.
+	Import(ctx context.Context, kind string, r io.Reader, opts catalog.Options) (*catalog.Report, error)
+	// Export escreve todos os registros do tipo kind em w, como em catalog.Export.
+	Export(ctx context.Context, kind string, w io.Writer, format string) error
+}
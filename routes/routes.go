@@ -1,12 +1,25 @@
 package routes
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi/v5"
 	"main.go/app"
 	"main.go/handlers"
+	"main.go/metrics"
 	"main.go/middlewares"
+	"main.go/models"
 )
 
+// RegisterHealthRoutes registra as verificações usadas pelo orquestrador e as métricas do Prometheus,
+// que não exigem autenticação e ficam fora do log e das métricas das requisições.
+func RegisterHealthRoutes(r chi.Router, app *app.App) {
+	r.Get("/healthz", handlers.HealthzHandler(app))
+	r.Get("/readyz", handlers.ReadyzHandler(app))
+	r.Get("/version", handlers.VersionHandler(app))
+	r.Method(http.MethodGet, "/metrics", metrics.Handler())
+}
+
 func RegisterRoutes(r chi.Router, app *app.App) {
 	// Usuário
 	r.Route("/user", func(r chi.Router) {
@@ -14,11 +27,14 @@ func RegisterRoutes(r chi.Router, app *app.App) {
 		r.Post("/login", handlers.LoginUserHandler(app))
 
 		// Sub-rotas com autenticação
-		r.With(middlewares.AuthMiddleware).Put("/{id}", handlers.UpdateUserHandler(app))
-		r.With(middlewares.AuthMiddleware).Delete("/{id}", handlers.DeleteUserHandler(app))
-		r.With(middlewares.AuthMiddleware).Get("/{id}", handlers.GetUserByIdHandler(app))
-		r.With(middlewares.AuthMiddleware).Get("/{id}/recipes", handlers.GetUserRecipesHandler(app))
-		r.With(middlewares.AuthMiddleware).Get("/", handlers.GetAllUsersHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Put("/{id}", handlers.UpdateUserHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Delete("/{id}", handlers.DeleteUserHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Get("/{id}", handlers.GetUserByIdHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Get("/{id}/recipes", handlers.GetUserRecipesHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Get("/", handlers.GetAllUsersHandler(app))
+
+		// Receita do usuário pelo slug, com redirecionamento dos slugs antigos
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/{username}/recipe/{slug}", handlers.GetUserRecipeBySlugHandler(app))
 	})
 
 	// Ingrediente
@@ -28,25 +44,107 @@ func RegisterRoutes(r chi.Router, app *app.App) {
 		r.Get("/name/{name}", handlers.GetIngredientByNameHandler(app))
 
 		// // Sub-rotas com autenticação
-		r.With(middlewares.AuthMiddleware).Post("/create", handlers.CreateIngredientHandler(app))
-		r.With(middlewares.AuthMiddleware).Put("/{id}", handlers.UpdateIngredientHandler(app))
-		r.With(middlewares.AuthMiddleware).Delete("/{id}", handlers.DeleteIngredientHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Post("/create", handlers.CreateIngredientHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Put("/{id}", handlers.UpdateIngredientHandler(app))
+		r.With(middlewares.AuthMiddleware(app), middlewares.RequireRole(app, models.RoleAdmin, models.RoleEditor)).Delete("/{id}", handlers.DeleteIngredientHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Post("/{id}/restore", handlers.RestoreIngredientHandler(app))
+
+		// Nomes alternativos e traduções do ingrediente, cuidados por editores e administradores, já que
+		// a busca dos ingredientes pelo nome também passa por eles
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(app))
+			r.Use(middlewares.RequireRole(app, models.RoleAdmin, models.RoleEditor))
+			r.Post("/{id}/aliases", handlers.CreateIngredientAliasHandler(app))
+			r.Delete("/{id}/aliases/{name}", handlers.DeleteIngredientAliasHandler(app))
+			r.Put("/{id}/translations/{locale}", handlers.PutIngredientTranslationHandler(app))
+			r.Delete("/{id}/translations/{locale}", handlers.DeleteIngredientTranslationHandler(app))
+		})
 	})
 
 	// Receita
 	r.Route("/recipe", func(r chi.Router) {
-		r.Get("/", handlers.GetAllRecipesHandler(app))
-		r.Get("/{id}", handlers.GetRecipeByIdHandler(app))
-		r.Get("/name/{name}", handlers.GetRecipeByNameHandler(app))
+		// Rotas públicas: com token, o autor também vê as próprias receitas não publicadas
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/", handlers.GetAllRecipesHandler(app))
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/{id}", handlers.GetRecipeByIdHandler(app))
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/name/{name}", handlers.GetRecipeByNameHandler(app))
 
 		// Sub-rotas com autenticação
-		r.With(middlewares.AuthMiddleware).Post("/create", handlers.CreateRecipeHandler(app))
-		r.With(middlewares.AuthMiddleware).Put("/{id}", handlers.UpdateRecipeHandler(app))
-		r.With(middlewares.AuthMiddleware).Delete("/{id}", handlers.DeleteRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Post("/create", handlers.CreateRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Post("/import", handlers.ImportRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Put("/{id}", handlers.UpdateRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Delete("/{id}", handlers.DeleteRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Post("/{id}/restore", handlers.RestoreRecipeHandler(app))
+
+		// Adição, alteração, ordenação e remoção de ingredientes associados à receita
+		r.With(middlewares.AuthMiddleware(app)).Post("/ingredients/{id}", handlers.AddIngredientRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Put("/ingredients/{id}/order", handlers.ReorderIngredientsRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Put("/ingredients/{id}/{ingredient_id}", handlers.UpdateIngredientRecipeHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Delete("/ingredients/{id}/{ingredient_id}", handlers.DeleteIngredientRecipeHandler(app))
 
-		// Adição e remoção de ingredientes associados à receita
-		r.With(middlewares.AuthMiddleware).Post("/ingredients/{id}", handlers.AddIngredientRecipeHandler(app))
-		r.With(middlewares.AuthMiddleware).Delete("/ingredients/{id}/{ingredient_id}", handlers.DeleteIngredientRecipeHandler(app))
+		// Histórico de revisões da receita
+		r.With(middlewares.AuthMiddleware(app)).Get("/{id}/revisions", handlers.GetRecipeRevisionsHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Get("/{id}/revisions/diff", handlers.DiffRecipeRevisionsHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Get("/{id}/revisions/{number}", handlers.GetRecipeRevisionHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Post("/{id}/revisions/{number}/restore", handlers.RestoreRecipeRevisionHandler(app))
+
+		// Cópias (forks) da receita
+		r.With(middlewares.AuthMiddleware(app)).Post("/{id}/fork", handlers.ForkRecipeHandler(app))
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/{id}/forks", handlers.GetRecipeForksHandler(app))
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/{id}/parent/diff", handlers.DiffRecipeParentHandler(app))
+
+		// Substituições sugeridas para os ingredientes da receita
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/{id}/substitutions", handlers.GetRecipeSubstitutionsHandler(app))
+
+		// Imagens da receita e dos passos do modo de preparo
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/{id}/images", handlers.GetRecipeImagesHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Post("/{id}/images", handlers.UploadRecipeImageHandler(app))
+		r.With(middlewares.AuthMiddleware(app)).Delete("/{id}/images/{image_id}", handlers.DeleteRecipeImageHandler(app))
+		r.With(middlewares.OptionalAuthMiddleware(app)).Get("/{id}/images/{image_id}/file", handlers.ServeRecipeImageHandler(app))
 	})
 
+	// Substituições de ingredientes, cuidadas por editores e administradores
+	r.Route("/substitution", func(r chi.Router) {
+		r.Get("/", handlers.GetSubstitutionsHandler(app))
+		r.Get("/{id}", handlers.GetSubstitutionByIdHandler(app))
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.AuthMiddleware(app))
+			r.Use(middlewares.RequireRole(app, models.RoleAdmin, models.RoleEditor))
+			r.Post("/", handlers.CreateSubstitutionHandler(app))
+			r.Put("/{id}", handlers.UpdateSubstitutionHandler(app))
+			r.Delete("/{id}", handlers.DeleteSubstitutionHandler(app))
+		})
+	})
+
+	// Lixeira do usuário
+	r.With(middlewares.AuthMiddleware(app)).Get("/trash", handlers.GetTrashHandler(app))
+
+	// Exportação de livros de receitas
+	r.Route("/cookbook", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(app))
+		r.Post("/export", handlers.CreateCookbookExportHandler(app))
+		r.Get("/export/{id}", handlers.GetCookbookExportHandler(app))
+		r.Get("/export/{id}/download", handlers.DownloadCookbookExportHandler(app))
+	})
+
+	// Administração
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(app))
+		r.Use(middlewares.RequireRole(app, models.RoleAdmin))
+
+		// Importação e exportação do catálogo em lote
+		r.Post("/catalog/{kind}/import", handlers.ImportCatalogHandler(app))
+		r.Get("/catalog/{kind}/export", handlers.ExportCatalogHandler(app))
+
+		// Restauração de usuários e limpeza da lixeira
+		r.Post("/users/{id}/restore", handlers.RestoreUserHandler(app))
+		r.Post("/trash/purge", handlers.PurgeTrashHandler(app))
+
+		// Unificação de ingredientes duplicados
+		r.Post("/ingredients/merge", handlers.MergeIngredientsHandler(app))
+	})
+
+	// Arquivos de mídia, acessados por URLs assinadas
+	r.Get("/media/*", handlers.ServeMediaHandler(app))
+
 }
@@ -0,0 +1,119 @@
+// Package server executa o servidor HTTP da API até o encerramento, dando às requisições em
+// andamento o tempo de SHUTDOWN_TIMEOUT_SECONDS para terminar.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"main.go/config"
+)
+
+// New cria o servidor HTTP com os limites de tempo e de tamanho dos cabeçalhos da configuração.
+func New(cfg config.Server, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: seconds(cfg.ReadHeaderTimeoutSeconds),
+		ReadTimeout:       seconds(cfg.ReadTimeoutSeconds),
+		WriteTimeout:      seconds(cfg.WriteTimeoutSeconds),
+		IdleTimeout:       seconds(cfg.IdleTimeoutSeconds),
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		// Erros das conexões (ex.: falhas no handshake TLS) vão para o log da aplicação
+		ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+}
+
+// Run atende as requisições até ctx ser cancelado (ex.: por SIGINT ou SIGTERM) e então encerra o
+// servidor, esperando as requisições em andamento por até ShutdownTimeoutSeconds. Com TLS, o
+// servidor em RedirectAddr, se configurado, redireciona as requisições HTTP para o endereço HTTPS.
+// Retorna o erro que impediu o servidor de iniciar ou de continuar atendendo.
+func Run(ctx context.Context, cfg config.Server, handler http.Handler) error {
+	servers := []*http.Server{New(cfg, handler)}
+	if cfg.TLS() && cfg.RedirectAddr != "" {
+		redirect := New(cfg, redirectHandler(cfg.Addr))
+		redirect.Addr = cfg.RedirectAddr
+		servers = append(servers, redirect)
+	}
+
+	// Os endereços são abertos antes de atender, para que um endereço em uso impeça o início
+	listeners := make([]net.Listener, len(servers))
+	for i, server := range servers {
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			for _, opened := range listeners[:i] {
+				opened.Close()
+			}
+			return fmt.Errorf("listen on %s: %w", server.Addr, err)
+		}
+		listeners[i] = listener
+	}
+
+	errs := make(chan error, len(servers))
+	for i, server := range servers {
+		go func() {
+			var err error
+			if i == 0 && cfg.TLS() {
+				err = server.ServeTLS(listeners[i], cfg.TLSCertFile, cfg.TLSKeyFile)
+			} else {
+				err = server.Serve(listeners[i])
+			}
+			errs <- err
+		}()
+	}
+
+	slog.Info("Server running", "addr", cfg.Addr, "tls", cfg.TLS())
+	if len(servers) > 1 {
+		slog.Info("Redirecting HTTP to HTTPS", "addr", cfg.RedirectAddr)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down, waiting for requests in progress", "timeout_seconds", cfg.ShutdownTimeoutSeconds)
+	case err = <-errs:
+		err = fmt.Errorf("serve: %w", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), seconds(cfg.ShutdownTimeoutSeconds))
+	defer cancel()
+
+	for _, server := range servers {
+		if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+			// As conexões que não terminaram a tempo são fechadas
+			server.Close()
+			err = errors.Join(err, fmt.Errorf("shutdown %s: %w", server.Addr, shutdownErr))
+		}
+	}
+
+	return err
+}
+
+// Funções privadas
+
+// redirectHandler redireciona as requisições para o mesmo host e caminho no endereço HTTPS
+func redirectHandler(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		// 308 mantém o método e o corpo da requisição, ao contrário do 301
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
+
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound é retornado quando o objeto solicitado não existe no armazenamento.
+var ErrNotFound = errors.New("storage: object not found")
+
+// ObjectInfo descreve um objeto guardado no armazenamento.
+type ObjectInfo struct {
+	// Key é a chave (caminho) do objeto dentro do armazenamento.
+	Key string
+	// ContentType é o tipo MIME do conteúdo guardado.
+	ContentType string
+	// Size é o tamanho do objeto em bytes.
+	Size int64
+}
+
+// BlobStore abstrai o armazenamento de arquivos binários (imagens, exportações etc.),
+// permitindo trocar o sistema de arquivos local por um serviço compatível com S3.
+type BlobStore interface {
+	// Put grava o conteúdo lido de r na chave informada, sobrescrevendo se já existir.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get abre o objeto da chave informada. Quem chama é responsável por fechar o leitor.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Delete remove o objeto da chave informada. Remover uma chave inexistente não é erro.
+	Delete(ctx context.Context, key string) error
+}
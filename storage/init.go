@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"main.go/config"
+)
+
+// InitStorage cria o BlobStore e o URLSigner a partir da configuração, com o driver "local" ou "s3".
+// As URLs são assinadas com o secret da aplicação.
+func InitStorage(cfg config.Storage, secret string) (BlobStore, *URLSigner) {
+	var store BlobStore
+	var err error
+
+	switch cfg.Driver {
+	case "s3":
+		store, err = NewS3Store(S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+		})
+	case "local":
+		store, err = NewLocalStore(cfg.Path)
+	default:
+		err = fmt.Errorf("unknown STORAGE_DRIVER %q", cfg.Driver)
+	}
+
+	if err != nil {
+		slog.Error("Failed to initialize storage", "error", err)
+		os.Exit(1)
+	}
+
+	signer := NewURLSigner(secret, "/media", cfg.MediaURLTTL())
+
+	return store, signer
+}
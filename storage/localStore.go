@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore guarda os objetos como arquivos em um diretório do sistema de arquivos local.
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore cria um LocalStore com raiz no diretório informado, criando-o se necessário.
+func NewLocalStore(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: creating root directory: %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+// Put grava o objeto em um arquivo temporário e o renomeia ao final, evitando leituras parciais.
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+// Get abre o arquivo do objeto. O tipo do conteúdo é deduzido pela extensão da chave.
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	info := &ObjectInfo{
+		Key:         key,
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		Size:        stat.Size(),
+	}
+	return file, info, nil
+}
+
+// Delete remove o arquivo do objeto, ignorando chaves inexistentes.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	fullPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(fullPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path converte a chave em um caminho dentro da raiz, recusando chaves que escapem dela.
+func (s *LocalStore) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
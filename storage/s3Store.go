@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config reúne as informações de acesso a um serviço compatível com S3 (AWS S3, MinIO etc.).
+type S3Config struct {
+	// Endpoint é a URL base do serviço, ex.: "https://s3.amazonaws.com" ou "http://localhost:9000".
+	Endpoint string
+	// Region é a região usada na assinatura das requisições.
+	Region string
+	// Bucket é o bucket onde os objetos serão guardados.
+	Bucket string
+	// AccessKey e SecretKey são as credenciais de acesso.
+	AccessKey string
+	SecretKey string
+}
+
+// S3Store guarda os objetos em um bucket compatível com S3, usando endereçamento por caminho
+// (endpoint/bucket/key) e assinatura AWS Signature Version 4, o que funciona também com o MinIO.
+type S3Store struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store cria um S3Store a partir da configuração informada.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("storage: S3 endpoint, bucket and credentials are required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+
+	return &S3Store{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 60 * time.Second},
+		now:    time.Now,
+	}, nil
+}
+
+// Put envia o objeto ao bucket. O conteúdo é lido para a memória para calcular o hash da assinatura.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.ContentLength = int64(len(body))
+	s.sign(req, body)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s.responseError(res)
+	}
+	return nil
+}
+
+// Get baixa o objeto do bucket.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.sign(req, nil)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, nil, ErrNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, nil, s.responseError(res)
+	}
+
+	info := &ObjectInfo{
+		Key:         key,
+		ContentType: res.Header.Get("Content-Type"),
+		Size:        res.ContentLength,
+	}
+	return res.Body, info, nil
+}
+
+// Delete remove o objeto do bucket. O S3 já responde com sucesso para chaves inexistentes.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, nil)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound {
+		return s.responseError(res)
+	}
+	return nil
+}
+
+func (s *S3Store) newRequest(ctx context.Context, method string, key string, body []byte) (*http.Request, error) {
+	if key == "" || strings.Contains(key, "..") {
+		return nil, fmt.Errorf("storage: invalid key %q", key)
+	}
+
+	rawURL := s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + encodePath(strings.TrimPrefix(key, "/"))
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	return http.NewRequestWithContext(ctx, method, rawURL, reader)
+}
+
+// sign adiciona os cabeçalhos de autenticação AWS Signature Version 4 à requisição.
+func (s *S3Store) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	payloadHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHex)
+
+	// Cabeçalhos assinados, em minúsculo e ordenados
+	headerNames := []string{"host"}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headerNames = append(headerNames, lower)
+		}
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHex,
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), date)
+	signingKey = hmacSHA256(signingKey, s.cfg.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKey, scope, signedHeaders, signature,
+	))
+}
+
+func (s *S3Store) responseError(res *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("storage: S3 responded %s: %s", res.Status, strings.TrimSpace(string(msg)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// encodePath codifica cada segmento da chave conforme a RFC 3986, preservando as barras.
+func encodePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		for _, value := range values[key] {
+			parts = append(parts, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
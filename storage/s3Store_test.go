@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 imita um bucket S3 com endereçamento por caminho, guardando os objetos em memória
+// e conferindo os cabeçalhos da assinatura de cada requisição.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	paths   []string
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3Store) {
+	t.Helper()
+
+	fake := &fakeS3{t: t, objects: map[string]fakeObject{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:  server.URL + "/",
+		Region:    "sa-east-1",
+		Bucket:    "receitas",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+	store.now = func() time.Time { return time.Date(2024, 5, 17, 12, 30, 0, 0, time.UTC) }
+	return fake, store
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	hash := sha256.Sum256(body)
+	auth := r.Header.Get("Authorization")
+	switch {
+	case r.Header.Get("X-Amz-Date") != "20240517T123000Z":
+		f.t.Errorf("X-Amz-Date = %q", r.Header.Get("X-Amz-Date"))
+	case r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]):
+		f.t.Errorf("X-Amz-Content-Sha256 does not match the body")
+	case !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240517/sa-east-1/s3/aws4_request, SignedHeaders="):
+		f.t.Errorf("Authorization = %q", auth)
+	case !strings.Contains(auth, "host;") || !strings.Contains(auth, "x-amz-content-sha256;x-amz-date"):
+		f.t.Errorf("Authorization does not sign the required headers: %q", auth)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, r.URL.EscapedPath())
+
+	key := strings.TrimPrefix(r.URL.Path, "/receitas/")
+	switch r.Method {
+	case http.MethodPut:
+		if key == "falha" {
+			http.Error(w, "<Error><Code>InternalError</Code></Error>", http.StatusInternalServerError)
+			return
+		}
+		f.objects[key] = fakeObject{data: body, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3StoreRoundTrip(t *testing.T) {
+	fake, store := newFakeS3(t)
+	ctx := context.Background()
+	key := "recipes/1/bolo de fubá.jpg"
+
+	if err := store.Put(ctx, key, strings.NewReader("conteúdo"), -1, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	body, info, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "conteúdo" || info.ContentType != "image/jpeg" || info.Size != int64(len(data)) || info.Key != key {
+		t.Fatalf("Get = %q, %+v", data, info)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete of a missing key: %v", err)
+	}
+
+	// Cada segmento da chave é codificado, preservando as barras
+	if want := "/receitas/recipes/1/bolo%20de%20fub%C3%A1.jpg"; fake.paths[0] != want {
+		t.Fatalf("path = %q, want %q", fake.paths[0], want)
+	}
+}
+
+func TestS3StoreErrors(t *testing.T) {
+	_, store := newFakeS3(t)
+	ctx := context.Background()
+
+	err := store.Put(ctx, "falha", strings.NewReader("x"), 1, "")
+	if err == nil || !strings.Contains(err.Error(), "500") || !strings.Contains(err.Error(), "InternalError") {
+		t.Fatalf("Put: err = %v, want the S3 error", err)
+	}
+
+	for _, key := range []string{"", "../outro-bucket/chave"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q): want an error", key)
+		}
+	}
+
+	if _, err := NewS3Store(S3Config{Endpoint: "http://localhost:9000", Bucket: "receitas"}); err == nil {
+		t.Fatal("NewS3Store without credentials: want an error")
+	}
+}
+
+func TestS3StoreSignature(t *testing.T) {
+	_, store := newFakeS3(t)
+
+	signature := func(store *S3Store) string {
+		req, err := store.newRequest(context.Background(), http.MethodGet, "recipes/1.jpg", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.sign(req, nil)
+		auth := req.Header.Get("Authorization")
+		return auth[strings.Index(auth, "Signature=")+len("Signature="):]
+	}
+
+	first := signature(store)
+	if len(first) != 64 || first != signature(store) {
+		t.Fatalf("signature = %q, want a stable SHA-256 hex digest", first)
+	}
+
+	other := *store
+	other.cfg.SecretKey = "outro"
+	if signature(&other) == first {
+		t.Fatal("signature does not depend on the secret key")
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// URLSigner gera e verifica URLs assinadas com validade, usadas para servir objetos privados
+// sem exigir o token de autenticação (ex.: em tags <img>).
+type URLSigner struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+// NewURLSigner cria um URLSigner. baseURL é o prefixo das rotas de mídia (ex.: "/media")
+// e ttl é o tempo de validade padrão das URLs geradas.
+func NewURLSigner(secret string, baseURL string, ttl time.Duration) *URLSigner {
+	return &URLSigner{secret: []byte(secret), baseURL: baseURL, ttl: ttl}
+}
+
+// SignedURL retorna a URL assinada para a chave informada, válida pelo tempo padrão.
+func (s *URLSigner) SignedURL(key string) string {
+	expires := time.Now().Add(s.ttl).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.signature(key, expires))
+
+	return s.baseURL + "/" + encodePath(key) + "?" + query.Encode()
+}
+
+// Verify confere a assinatura e a validade recebidas na URL para a chave informada.
+func (s *URLSigner) Verify(key string, expiresParam string, signature string) bool {
+	expires, err := strconv.ParseInt(expiresParam, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	expected := s.signature(key, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (s *URLSigner) signature(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestURLSigner(t *testing.T) {
+	signer := NewURLSigner("secret", "/media", time.Hour)
+	key := "recipes/1/bolo de fubá.jpg"
+
+	signed, err := url.Parse(signer.SignedURL(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/media/recipes/1/bolo%20de%20fub%C3%A1.jpg"; signed.EscapedPath() != want {
+		t.Fatalf("path = %q, want %q", signed.EscapedPath(), want)
+	}
+
+	expires := signed.Query().Get("expires")
+	signature := signed.Query().Get("signature")
+	if !signer.Verify(key, expires, signature) {
+		t.Fatal("Verify rejected a valid URL")
+	}
+
+	tests := []struct {
+		name      string
+		signer    *URLSigner
+		key       string
+		expires   string
+		signature string
+	}{
+		{"other key", signer, "recipes/2/bolo.jpg", expires, signature},
+		{"tampered signature", signer, key, expires, strings.Repeat("0", len(signature))},
+		{"extended expiry", signer, key, expires + "0", signature},
+		{"invalid expiry", signer, key, "amanhã", signature},
+		{"other secret", NewURLSigner("outro", "/media", time.Hour), key, expires, signature},
+	}
+	for _, tt := range tests {
+		if tt.signer.Verify(tt.key, tt.expires, tt.signature) {
+			t.Errorf("%s: Verify accepted the URL", tt.name)
+		}
+	}
+}
+
+func TestURLSignerExpired(t *testing.T) {
+	signer := NewURLSigner("secret", "/media", -time.Minute)
+
+	signed, err := url.Parse(signer.SignedURL("recipes/1.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.Verify("recipes/1.jpg", signed.Query().Get("expires"), signed.Query().Get("signature")) {
+		t.Fatal("Verify accepted an expired URL")
+	}
+}
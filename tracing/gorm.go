@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey guarda na instância da consulta o span aberto antes dela
+const spanKey = "tracing:span"
+
+// maxQueryLength limita o tamanho do SQL registrado no span
+const maxQueryLength = 2000
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numericLiteral = regexp.MustCompile(`\$?\b\d+(?:\.\d+)?\b`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+// GormPlugin cria um span filho do span da requisição para cada consulta do GORM, com o SQL sem os
+// valores. Instalado com db.Use(tracing.GormPlugin{}); as consultas precisam usar WithContext para
+// ficarem no trace da requisição.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	errs := []error{
+		callback.Create().Before("gorm:create").Register("tracing:before_create", before("create")),
+		callback.Create().After("gorm:create").Register("tracing:after_create", after("create")),
+		callback.Query().Before("gorm:query").Register("tracing:before_query", before("query")),
+		callback.Query().After("gorm:query").Register("tracing:after_query", after("query")),
+		callback.Update().Before("gorm:update").Register("tracing:before_update", before("update")),
+		callback.Update().After("gorm:update").Register("tracing:after_update", after("update")),
+		callback.Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete")),
+		callback.Delete().After("gorm:delete").Register("tracing:after_delete", after("delete")),
+		callback.Row().Before("gorm:row").Register("tracing:before_row", before("row")),
+		callback.Row().After("gorm:row").Register("tracing:after_row", after("row")),
+		callback.Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw")),
+		callback.Raw().After("gorm:raw").Register("tracing:after_raw", after("raw")),
+	}
+	return errors.Join(errs...)
+}
+
+// SanitizeSQL troca os valores literais do SQL (textos e números) por "?" e limita o tamanho, para
+// que os spans não levem dados dos usuários. Os parâmetros das consultas já não fazem parte do SQL.
+func SanitizeSQL(sql string) string {
+	sql = stringLiteral.ReplaceAllString(sql, "?")
+	sql = numericLiteral.ReplaceAllStringFunc(sql, func(literal string) string {
+		// Mantém os marcadores de parâmetros do Postgres ($1, $2...)
+		if strings.HasPrefix(literal, "$") {
+			return literal
+		}
+		return "?"
+	})
+	sql = strings.TrimSpace(whitespace.ReplaceAllString(sql, " "))
+	if len(sql) > maxQueryLength {
+		sql = sql[:maxQueryLength] + "..."
+	}
+	return sql
+}
+
+// Funções privadas
+
+func before(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		// Apenas as consultas feitas dentro de um span (ex.: o da requisição) são registradas
+		if !trace.SpanContextFromContext(db.Statement.Context).IsValid() {
+			return
+		}
+		ctx, span := Tracer().Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBOperationName(operation)),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		defer span.End()
+
+		if span.IsRecording() {
+			record(span, operation, db)
+		}
+	}
+}
+
+// record registra no span o SQL, a tabela e o erro da consulta, nomeando o span pela operação e pela
+// tabela (ex.: "query recipes")
+func record(span trace.Span, operation string, db *gorm.DB) {
+	attrs := []attribute.KeyValue{
+		dbSystem(db.Dialector.Name()),
+		semconv.DBQueryText(SanitizeSQL(db.Statement.SQL.String())),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	}
+	if table := db.Statement.Table; table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(table))
+		span.SetName(operation + " " + table)
+	}
+	span.SetAttributes(attrs...)
+
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
+
+func dbSystem(dialector string) attribute.KeyValue {
+	switch dialector {
+	case "postgres":
+		return semconv.DBSystemPostgreSQL
+	case "sqlite":
+		return semconv.DBSystemSqlite
+	default:
+		return semconv.DBSystemKey.String(dialector)
+	}
+}
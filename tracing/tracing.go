@@ -0,0 +1,116 @@
+// Package tracing configura os traces do OpenTelemetry: o contexto W3C (traceparent) recebido nas
+// requisições é propagado, e os spans das requisições e das consultas ao banco são enviados por OTLP
+// ou escritos em JSON na saída padrão ou em um arquivo.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"main.go/config"
+)
+
+// instrumentationName identifica os spans criados pela aplicação
+const instrumentationName = "main.go"
+
+// Tracer cria os spans da aplicação no TracerProvider global.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup configura a propagação do contexto W3C e, quando o exporter não for "none", o TracerProvider
+// global. Retorna a função que envia os spans pendentes e encerra o envio, chamada no encerramento
+// do servidor.
+func Setup(ctx context.Context, cfg config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Exporter == "none" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// Falhas no envio dos spans (ex.: coletor fora do ar) vão para o log da aplicação
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		slog.Error("Tracing error", "error", err)
+	}))
+
+	exporter, file, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing resource: %w", err)
+	}
+
+	// As requisições que chegam com um trace seguem a decisão de amostragem de quem as enviou
+	sampler := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(cfg.SamplePercent) / 100))
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+		return err
+	}, nil
+}
+
+// Funções privadas
+
+// newExporter cria o exporter da configuração, retornando também o arquivo aberto para o exporter
+// stdout, que deve ser fechado no encerramento
+func newExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case "otlp":
+		var options []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err := otlptracehttp.New(ctx, options...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("otlp exporter: %w", err)
+		}
+		return exporter, nil, nil
+	case "stdout":
+		if cfg.File == "" {
+			exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+			return exporter, nil, err
+		}
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open tracing file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+}
@@ -0,0 +1,128 @@
+package trash
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"main.go/logging"
+	"main.go/models"
+	"main.go/storage"
+)
+
+// DefaultRetention é o tempo que os itens ficam na lixeira antes de serem removidos definitivamente.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// purgeInterval é o intervalo entre as limpezas automáticas da lixeira
+const purgeInterval = time.Hour
+
+// Purger remove definitivamente, em segundo plano, os itens que estão na lixeira há mais tempo que
+// a retenção configurada.
+type Purger struct {
+	db        *gorm.DB
+	store     storage.BlobStore
+	retention time.Duration
+	running   sync.WaitGroup
+}
+
+// NewPurger cria um Purger com a retenção informada (DefaultRetention quando não for positiva).
+func NewPurger(db *gorm.DB, store storage.BlobStore, retention time.Duration) *Purger {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Purger{db: db, store: store, retention: retention}
+}
+
+// Retention é o tempo que os itens ficam na lixeira.
+func (p *Purger) Retention() time.Duration {
+	return p.retention
+}
+
+// Start limpa a lixeira imediatamente e depois a cada hora, até ctx ser cancelado.
+func (p *Purger) Start(ctx context.Context) {
+	p.running.Add(1)
+	go func() {
+		defer p.running.Done()
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			report, err := p.Purge(ctx, time.Now().Add(-p.retention))
+			if err != nil {
+				if ctx.Err() == nil {
+					logging.FromContext(ctx).Error("Error purging trash", "error", err)
+				}
+			} else if report.Recipes+report.Ingredients+report.Users > 0 {
+				logging.FromContext(ctx).Info("Purged trash", "recipes", report.Recipes, "ingredients", report.Ingredients, "users", report.Users)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait espera a limpeza em andamento terminar depois de o ctx de Start ser cancelado.
+func (p *Purger) Wait() {
+	p.running.Wait()
+}
+
+// Purge remove definitivamente as receitas, ingredientes e usuários que foram para a lixeira antes
+// de before, junto com as imagens das receitas. As receitas dos usuários removidos também são
+// removidas, mesmo que tenham sido restauradas, já que recipes.user_id não tem chave estrangeira.
+// As associações das receitas e dos ingredientes são removidas em cascata pelo banco.
+func (p *Purger) Purge(ctx context.Context, before time.Time) (*models.TrashPurgeReport, error) {
+	db := p.db.WithContext(ctx)
+	report := &models.TrashPurgeReport{}
+
+	// As datas de remoção são comparadas em UTC, como são gravadas no SQLite
+	before = before.UTC()
+
+	purgedUsers := db.Unscoped().Model(&models.User{}).Select("id").Where("deleted_at < ?", before)
+
+	var recipes []models.Recipe
+	err := db.Unscoped().Preload("Images.Thumbnails").Where("deleted_at < ? OR user_id IN (?)", before, purgedUsers).Find(&recipes).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, recipe := range recipes {
+		if err := db.Unscoped().Delete(&recipe).Error; err != nil {
+			return report, err
+		}
+		report.Recipes++
+
+		// Os arquivos só são removidos depois da receita, para não deixar imagens quebradas
+		for _, image := range recipe.Images {
+			p.deleteBlob(ctx, image.Key)
+			for _, thumbnail := range image.Thumbnails {
+				p.deleteBlob(ctx, thumbnail.Key)
+			}
+		}
+	}
+
+	result := db.Unscoped().Where("deleted_at < ?", before).Delete(&models.Ingredient{})
+	if result.Error != nil {
+		return report, result.Error
+	}
+	report.Ingredients = result.RowsAffected
+
+	result = db.Unscoped().Where("deleted_at < ?", before).Delete(&models.User{})
+	if result.Error != nil {
+		return report, result.Error
+	}
+	report.Users = result.RowsAffected
+
+	return report, nil
+}
+
+// Funções privadas
+
+func (p *Purger) deleteBlob(ctx context.Context, key string) {
+	if err := p.store.Delete(ctx, key); err != nil {
+		logging.FromContext(ctx).Error("Error deleting media", "key", key, "error", err)
+	}
+}
@@ -0,0 +1,86 @@
+package trash
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"main.go/db"
+	"main.go/db/migrations"
+	"main.go/models"
+	"main.go/repository"
+)
+
+func TestPurgeRemovesRecipesOfPurgedUsers(t *testing.T) {
+	database, err := db.OpenSQLite(filepath.Join(t.TempDir(), "trash.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := migrations.New(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	users := repository.NewGormUserRepository(database)
+	recipes := repository.NewGormRecipeRepository(database)
+
+	var ids []uint
+	for _, username := range []string{"alice", "bob"} {
+		user := models.User{Username: username, Email: username + "@example.com", Password: "secret"}
+		if err := users.Create(ctx, &user); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, user.ID)
+	}
+	alice, bob := ids[0], ids[1]
+
+	recipe := func(userID uint, name string) uint {
+		recipe := models.Recipe{UserID: userID, Name: name, Instructions: "Misture tudo."}
+		if err := recipes.Save(ctx, &recipe, repository.SaveRecipeOptions{AuthorID: userID}); err != nil {
+			t.Fatal(err)
+		}
+		return recipe.ID
+	}
+	cake, pie, bread := recipe(alice, "Bolo"), recipe(alice, "Torta"), recipe(bob, "Pão")
+
+	// As receitas vão para a lixeira com o usuário e voltam com ele
+	if err := users.Delete(ctx, alice); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recipes.Get(ctx, cake, alice); err == nil {
+		t.Fatal("recipe of a trashed user is still visible")
+	}
+	if err := users.Restore(ctx, alice); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recipes.Get(ctx, cake, alice); err != nil {
+		t.Fatalf("recipe not restored with its author: %v", err)
+	}
+
+	// Mesmo uma receita restaurada sozinha é removida com o usuário
+	if err := users.Delete(ctx, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := recipes.Restore(ctx, pie); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := NewPurger(database, nil, 0).Purge(ctx, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Users != 1 || report.Recipes != 2 {
+		t.Fatalf("report = %+v, want 1 user and 2 recipes", report)
+	}
+
+	var left []uint
+	database.Unscoped().Model(&models.Recipe{}).Order("id").Pluck("id", &left)
+	if len(left) != 1 || left[0] != bread {
+		t.Fatalf("recipes left = %v, want only %d", left, bread)
+	}
+}